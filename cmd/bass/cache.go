@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tonistiigi/units"
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/cli"
+	"github.com/vito/progrock"
+)
+
+func caches(ctx context.Context) error {
+	ctx, pool, err := setupPool(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	return cli.Step(ctx, cmdline, func(ctx context.Context, vertex *progrock.VertexRecorder) error {
+		runtimes, err := pool.All()
+		if err != nil {
+			return err
+		}
+
+		switch cacheCmd {
+		case "ls":
+			return listCaches(ctx, runtimes)
+		case "rm":
+			return removeCaches(ctx, runtimes, flags.Args())
+		default:
+			return fmt.Errorf("unknown cache command: %s (expected ls or rm)", cacheCmd)
+		}
+	})
+}
+
+func listCaches(ctx context.Context, runtimes []bass.Runtime) error {
+	tw := tabwriter.NewWriter(os.Stdout, 2, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSIZE\tLAST USED\tUSES\tDESCRIPTION")
+
+	for i, runtime := range runtimes {
+		caches, err := runtime.Caches(ctx)
+		if err != nil {
+			return fmt.Errorf("list caches for runtime #%d: %w", i+1, err)
+		}
+
+		for _, cache := range caches {
+			lastUsed := "never"
+			if !cache.LastUsedAt.IsZero() {
+				lastUsed = time.Since(cache.LastUsedAt).Truncate(time.Second).String() + " ago"
+			}
+
+			fmt.Fprintf(tw, "%s\t%.2f\t%s\t%d\t%s\n",
+				cache.ID,
+				units.Bytes(cache.Size),
+				lastUsed,
+				cache.UsageCount,
+				cache.Description,
+			)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func removeCaches(ctx context.Context, runtimes []bass.Runtime, ids []string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("usage: bass --cache rm <id>...")
+	}
+
+	for i, runtime := range runtimes {
+		err := runtime.Prune(ctx, bass.PruneOpts{
+			All: true,
+			IDs: ids,
+		})
+		if err != nil {
+			return fmt.Errorf("prune runtime #%d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
@@ -61,12 +61,16 @@ func exportPath(ctx context.Context, vertex *progrock.VertexRecorder, path bass.
 		return fmt.Errorf("cannot export bass thunk path: %s", path)
 	}
 
+	if exportFormat != "" || len(exportTags) > 0 {
+		return fmt.Errorf("--export-format and --export-tag only apply to thunks")
+	}
+
 	runtime, err := bass.RuntimeFromContext(ctx, *platform)
 	if err != nil {
 		return err
 	}
 
-	return writeTar(vertex, func(w io.Writer) error {
+	return writeExport(ctx, vertex, func(w io.Writer) error {
 		return runtime.ExportPath(ctx, w, path)
 	})
 }
@@ -77,16 +81,47 @@ func exportThunk(ctx context.Context, vertex *progrock.VertexRecorder, thunk bas
 		return fmt.Errorf("cannot export bass thunk: %s", thunk)
 	}
 
-	runtime, err := bass.RuntimeFromContext(ctx, *platform)
+	format, err := bass.ParseExportFormat(exportFormat)
 	if err != nil {
 		return err
 	}
 
-	return writeTar(vertex, func(w io.Writer) error {
-		return runtime.Export(ctx, w, thunk)
+	opts := bass.ExportOpts{
+		Format: format,
+		Tags:   exportTags,
+	}
+
+	if format == bass.ExportOCIDir {
+		if exportTo == "" {
+			return fmt.Errorf("--export-format %s requires --export-to", format)
+		}
+
+		return thunk.ExportTo(ctx, bass.NewHostDir(exportTo), opts)
+	}
+
+	return writeExport(ctx, vertex, func(w io.Writer) error {
+		return thunk.Export(ctx, w, opts)
 	})
 }
 
+// writeExport writes the tar stream to the --export-to path if set, and
+// otherwise to stdout.
+func writeExport(ctx context.Context, vertex *progrock.VertexRecorder, f func(w io.Writer) error) error {
+	if exportTo == "" {
+		return writeTar(vertex, f)
+	}
+
+	r, w := io.Pipe()
+
+	go func() {
+		w.CloseWithError(f(w))
+	}()
+
+	defer r.Close()
+
+	return bass.ParseHostPath(exportTo).Write(ctx, r)
+}
+
 func writeTar(vertex *progrock.VertexRecorder, f func(w io.Writer) error) error {
 	if isatty.IsTerminal(os.Stdout.Fd()) {
 		r, w := io.Pipe()
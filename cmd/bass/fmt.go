@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/bassfmt"
+	"github.com/vito/bass/pkg/cli"
+)
+
+func format(ctx context.Context) error {
+	var unformatted int
+	for _, path := range flags.Args() {
+		changed, err := formatFile(path)
+		if err != nil {
+			cli.WriteError(ctx, err)
+			return err
+		}
+
+		if changed && fmtCheck {
+			fmt.Println(path)
+			unformatted++
+		}
+	}
+
+	if unformatted > 0 {
+		// the file list is the message; avoid the cryptic error tip
+		return fmt.Errorf("%d file(s) not formatted", unformatted)
+	}
+
+	return nil
+}
+
+// formatFile formats the file, returning whether its content changed. The
+// file is only written if --check is not given.
+func formatFile(path string) (bool, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	fmted, err := bassfmt.Format(bass.NewHostPath(
+		dir,
+		bass.ParseFileOrDirPath(filepath.ToSlash(base)),
+	), src)
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal(fmted, src) {
+		return false, nil
+	}
+
+	if fmtCheck {
+		return true, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	return true, os.WriteFile(path, fmted, info.Mode())
+}
@@ -64,6 +64,10 @@ func (fs *InputsFilesystem) Write(path string, r io.Reader) error {
 	return nil
 }
 
+func (fs *InputsFilesystem) WriteTar(dir string, r io.Reader) error {
+	return nil
+}
+
 func frontendBuild(ctx context.Context, gw gwclient.Client) (*gwclient.Result, error) {
 	caps := gw.BuildOpts().Caps
 	opts := gw.BuildOpts().Opts
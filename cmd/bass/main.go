@@ -9,7 +9,9 @@ import (
 	"os"
 	"runtime/pprof"
 	"strings"
+	"time"
 
+	"github.com/docker/docker/pkg/reexec"
 	"github.com/moby/buildkit/util/appcontext"
 	flag "github.com/spf13/pflag"
 	"github.com/vito/bass/pkg/bass"
@@ -28,13 +30,26 @@ var inputs []string
 
 var runRun bool
 var runExport bool
+var exportFormat string
+var exportTags []string
+var exportTo string
 var runBump bool
 var runPrune bool
+var pruneKeepDuration time.Duration
+var pruneKeepBytes int64
+var pruneIDs []string
+var cacheCmd string
 var runnerAddr string
 
+var cacheFrom []string
+var cacheTo []string
+
 var runLSP bool
 var lspLogs string
 
+var runFmt bool
+var fmtCheck bool
+
 var runFrontend bool
 
 var profPort int
@@ -43,6 +58,7 @@ var profFilePath string
 var showHelp bool
 var showVersion bool
 var showDebug bool
+var debugShell bool
 
 func init() {
 	flags.SetOutput(os.Stdout)
@@ -51,16 +67,29 @@ func init() {
 	flags.StringSliceVarP(&inputs, "input", "i", nil, "inputs to encode as JSON on *stdin*, name=value; value may be a path")
 
 	flags.BoolVarP(&runExport, "export", "e", false, "write a thunk path to stdout as a tar stream, or log the tar contents if stdout is a tty")
+	flags.StringVar(&exportFormat, "export-format", "", "format for exporting a thunk: oci (default), oci-dir, docker, or rootfs")
+	flags.StringSliceVar(&exportTags, "export-tag", nil, "repository tag for an image exported in docker format; may be repeated")
+	flags.StringVar(&exportTo, "export-to", "", "write the export to this path instead of stdout; required for oci-dir")
 	flags.BoolVar(&runRun, "run", false, "run a thunk read from stdin in JSON format")
 	flags.BoolVarP(&runBump, "bump", "b", false, "re-generate all calls in bass.lock files")
 
 	flags.BoolVarP(&runPrune, "prune", "p", false, "release data and caches retained by runtimes")
+	flags.DurationVar(&pruneKeepDuration, "keep-duration", 0, "when pruning, keep data used within this duration, e.g. 24h")
+	flags.Int64Var(&pruneKeepBytes, "keep-bytes", 0, "when pruning, keep up to this many bytes of data")
+	flags.StringArrayVar(&pruneIDs, "prune-id", nil, "when pruning, only prune the cache with this ID, as listed by --cache ls; may be repeated")
+	flags.StringVar(&cacheCmd, "cache", "", "inspect caches retained by runtimes: 'ls' lists them and 'rm <id>...' removes them")
 
 	flags.StringVarP(&runnerAddr, "runner", "r", "", "serve locally configured runtimes over SSH")
 
+	flags.StringArrayVar(&cacheFrom, "cache-from", nil, "import build cache, e.g. type=local,src=./cache; overrides cache_imports in the runtime config")
+	flags.StringArrayVar(&cacheTo, "cache-to", nil, "export build cache, e.g. type=local,dest=./cache; overrides cache_exports in the runtime config")
+
 	flags.BoolVar(&runLSP, "lsp", false, "run the bass language server")
 	flags.StringVar(&lspLogs, "lsp-log-file", "", "write language server logs to this file")
 
+	flags.BoolVar(&runFmt, "fmt", false, "format the given .bass files in place")
+	flags.BoolVar(&fmtCheck, "check", false, "with --fmt, list files which are not formatted instead of writing them, and fail if there are any")
+
 	flags.BoolVar(&runFrontend, "frontend", false, "run the bass buildkit frontend")
 
 	flags.IntVar(&profPort, "profile", 0, "port number to bind for Go HTTP profiling")
@@ -70,6 +99,7 @@ func init() {
 	flags.BoolVarP(&showHelp, "help", "h", false, "show bass usage and exit")
 
 	flags.BoolVar(&showDebug, "debug", false, "show debug logs")
+	flags.BoolVar(&debugShell, "debug-shell", false, "open an interactive shell in the container of any thunk that fails")
 }
 
 func logLevel() zapcore.LevelEnabler {
@@ -81,6 +111,11 @@ func logLevel() zapcore.LevelEnabler {
 }
 
 func main() {
+	// the native runtime re-executes bass to set up containers
+	if reexec.Init() {
+		return
+	}
+
 	// reusing for convenience; originally for frontend
 	ctx := appcontext.Context()
 
@@ -99,6 +134,10 @@ func main() {
 
 	ctx = zapctx.ToContext(ctx, bass.StdLogger(logLevel()))
 
+	if debugShell {
+		ctx = bass.WithDebugShell(ctx)
+	}
+
 	err = root(ctx)
 	if err != nil {
 		os.Exit(1)
@@ -161,6 +200,10 @@ func root(ctx context.Context) error {
 		return cli.WithProgress(ctx, export)
 	}
 
+	if cacheCmd != "" {
+		return cli.WithProgress(ctx, caches)
+	}
+
 	if runPrune {
 		return cli.WithProgress(ctx, prune)
 	}
@@ -169,6 +212,10 @@ func root(ctx context.Context) error {
 		return langServer(ctx)
 	}
 
+	if runFmt {
+		return format(ctx)
+	}
+
 	if runBump {
 		return cli.WithProgress(ctx, bump)
 	}
@@ -212,6 +259,11 @@ func setupPool(ctx context.Context, oneShot bool) (context.Context, *runtimes.Po
 		return nil, nil, err
 	}
 
+	if err := overrideCacheConfig(config); err != nil {
+		cli.WriteError(ctx, err)
+		return nil, nil, err
+	}
+
 	pool, err := runtimes.NewPool(ctx, config)
 	if err != nil {
 		cli.WriteError(ctx, err)
@@ -220,3 +272,44 @@ func setupPool(ctx context.Context, oneShot bool) (context.Context, *runtimes.Po
 
 	return bass.WithRuntimePool(ctx, pool), pool, nil
 }
+
+// overrideCacheConfig replaces the cache imports and exports of every
+// Buildkit runtime with those given by --cache-from and --cache-to.
+func overrideCacheConfig(config *bass.Config) error {
+	overrides := map[bass.Symbol][]string{
+		"cache_imports": cacheFrom,
+		"cache_exports": cacheTo,
+	}
+
+	for i, rt := range config.Runtimes {
+		if rt.Runtime != runtimes.BuildkitName {
+			continue
+		}
+
+		if rt.Config == nil {
+			rt.Config = bass.NewEmptyScope()
+		}
+
+		for key, strs := range overrides {
+			if len(strs) == 0 {
+				continue
+			}
+
+			caches := make([]bass.Value, len(strs))
+			for j, str := range strs {
+				cache, err := runtimes.ParseBuildkitCacheConfig(str)
+				if err != nil {
+					return err
+				}
+
+				caches[j] = cache.Scope()
+			}
+
+			rt.Config.Set(key, bass.NewList(caches...))
+		}
+
+		config.Runtimes[i] = rt
+	}
+
+	return nil
+}
@@ -28,7 +28,11 @@ func prune(ctx context.Context) error {
 		}
 
 		for i, runtime := range runtimes {
-			err := runtime.Prune(ctx, bass.PruneOpts{})
+			err := runtime.Prune(ctx, bass.PruneOpts{
+				KeepDuration: pruneKeepDuration,
+				KeepBytes:    pruneKeepBytes,
+				IDs:          pruneIDs,
+			})
 			if err != nil {
 				return fmt.Errorf("prune runtime #%d: %w", i+1, err)
 			}
@@ -87,6 +87,32 @@ var errType = reflect.TypeOf((*error)(nil)).Elem()
 var ctxType = reflect.TypeOf((*context.Context)(nil)).Elem()
 var contType = reflect.TypeOf((*ReadyCont)(nil)).Elem()
 
+// Arity returns the number of arguments the builtin needs, not counting
+// those passed automatically, and whether it accepts more.
+func (builtin Builtin) Arity() (int, bool) {
+	ftype := builtin.Func.Type()
+
+	need := ftype.NumIn()
+
+	if ftype.NumIn() >= 1 && ftype.In(0) == ctxType {
+		need--
+	}
+
+	if ftype.NumOut() == 1 && ftype.Out(0) == contType {
+		need--
+	}
+
+	if builtin.Operative {
+		need--
+	}
+
+	if ftype.IsVariadic() {
+		need--
+	}
+
+	return need, ftype.IsVariadic()
+}
+
 func (builtin Builtin) Call(ctx context.Context, val Value, scope *Scope, cont Cont) ReadyCont {
 	ftype := builtin.Func.Type()
 
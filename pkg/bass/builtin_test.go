@@ -47,6 +47,49 @@ func TestBuiltinEqual(t *testing.T) {
 	is.True(!val.Equal(bass.Func("noop", "[]", func() {})))
 }
 
+func TestBuiltinArity(t *testing.T) {
+	is := is.New(t)
+
+	type example struct {
+		Builtin  *bass.Builtin
+		Need     int
+		Variadic bool
+	}
+
+	applicative := func(builtin *bass.Builtin) *bass.Builtin {
+		builtin.Operative = false
+		return builtin
+	}
+
+	for _, e := range []example{
+		{
+			Builtin: applicative(bass.Op("noop", "[]", func() {})),
+			Need:    0,
+		},
+		{
+			Builtin: applicative(bass.Op("ctx", "[a b]", func(context.Context, bass.Value, bass.Value) {})),
+			Need:    2,
+		},
+		{
+			Builtin:  applicative(bass.Op("variadic", "[a & bs]", func(bass.Value, ...bass.Value) {})),
+			Need:     1,
+			Variadic: true,
+		},
+		{
+			Builtin: bass.Op("op", "[a b]", func(*bass.Scope, bass.Value, bass.Value) {}),
+			Need:    2,
+		},
+		{
+			Builtin: bass.Op("cont", "[a]", func(context.Context, bass.Cont, *bass.Scope, bass.Value) bass.ReadyCont { return nil }),
+			Need:    1,
+		},
+	} {
+		need, variadic := e.Builtin.Arity()
+		is.Equal(need, e.Need)
+		is.Equal(variadic, e.Variadic)
+	}
+}
+
 func TestBuiltinCall(t *testing.T) {
 	is := is.New(t)
 
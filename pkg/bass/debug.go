@@ -0,0 +1,66 @@
+package bass
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// Terminal is an interactive terminal that a runtime may attach a process to,
+// e.g. a debug shell.
+//
+// Reads return the user's input and writes are displayed to the user.
+type Terminal interface {
+	io.ReadWriteCloser
+
+	// Resized returns a channel that receives the terminal's size whenever it
+	// changes, starting with its initial size.
+	Resized() <-chan TerminalSize
+}
+
+// TerminalSize is the size of a Terminal in characters.
+type TerminalSize struct {
+	Rows int
+	Cols int
+}
+
+// OpenTerminalFunc opens a Terminal for an interactive session with the
+// given name.
+type OpenTerminalFunc func(context.Context, string) (Terminal, error)
+
+// ErrNoTerminal is returned when the context.Context does not have a way to
+// open a Terminal.
+var ErrNoTerminal = errors.New("no terminal available")
+
+type terminalKey struct{}
+
+// WithTerminal configures how to open a Terminal for interactive sessions.
+func WithTerminal(ctx context.Context, open OpenTerminalFunc) context.Context {
+	return context.WithValue(ctx, terminalKey{}, open)
+}
+
+// OpenTerminal opens a Terminal using the func set by WithTerminal.
+func OpenTerminal(ctx context.Context, name string) (Terminal, error) {
+	open := ctx.Value(terminalKey{})
+	if open == nil {
+		return nil, ErrNoTerminal
+	}
+
+	return open.(OpenTerminalFunc)(ctx, name)
+}
+
+type debugShellKey struct{}
+
+// WithDebugShell configures runtimes to open an interactive shell in the
+// container of any thunk that fails.
+//
+// Runtimes which do not support debug shells ignore it.
+func WithDebugShell(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugShellKey{}, true)
+}
+
+// DebugShellEnabled returns true if WithDebugShell was called on the context.
+func DebugShellEnabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(debugShellKey{}).(bool)
+	return enabled
+}
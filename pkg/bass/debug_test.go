@@ -0,0 +1,55 @@
+package bass_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/runtimes"
+	"github.com/vito/is"
+)
+
+func TestDebug(t *testing.T) {
+	is := is.New(t)
+
+	boom := errors.New("boom")
+
+	var debugged bool
+	ctx := bass.WithRuntimePool(context.Background(), &runtimes.Pool{
+		Runtimes: []runtimes.Assoc{
+			{
+				Platform: fakePlatform,
+				Runtime: &FakeRuntime{
+					RunFunc: func(ctx context.Context, thunk bass.Thunk) error {
+						debugged = bass.DebugShellEnabled(ctx)
+						return boom
+					},
+				},
+			},
+		},
+	})
+
+	is.True(!bass.DebugShellEnabled(ctx))
+
+	scope := bass.NewStandardScope()
+	scope.Set("thunk", bass.Thunk{
+		Image: &bass.ThunkImage{
+			Ref: &bass.ImageRef{
+				Platform: fakePlatform,
+			},
+		},
+		Args: []bass.Value{bass.CommandPath{"false"}},
+	})
+
+	_, err := bass.EvalString(ctx, scope, "(debug thunk)", bass.NewInMemoryFile("test", ""))
+	is.True(errors.Is(err, boom))
+	is.True(debugged)
+}
+
+func TestOpenTerminal(t *testing.T) {
+	is := is.New(t)
+
+	_, err := bass.OpenTerminal(context.Background(), "test")
+	is.Equal(err, bass.ErrNoTerminal)
+}
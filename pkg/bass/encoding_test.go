@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/vito/bass/pkg/bass"
 	"github.com/vito/bass/pkg/basstest"
@@ -19,6 +20,8 @@ var encodable = []bass.Value{
 	bass.Bool(true),
 	bass.Bool(false),
 	bass.Int(42),
+	bass.Float(4.2),
+	bass.Float(42),
 	bass.NewList(
 		bass.Bool(true),
 		bass.Int(1),
@@ -131,6 +134,36 @@ var validThiccThunk = bass.Thunk{
 		Cert: bass.FilePath{"cert"},
 		Key:  bass.FilePath{"key"},
 	},
+	Timeout: time.Minute,
+	Retry: &bass.ThunkRetry{
+		Attempts: 3,
+		Backoff:  time.Second,
+	},
+	Resources: &bass.ThunkResources{
+		CPU:    1.5,
+		Memory: 512 * 1024 * 1024,
+		Pids:   100,
+	},
+	Network: bass.NetworkModeNone,
+	User:    "1000:1000",
+	ImageConfig: &bass.ThunkImageConfig{
+		WorkingDir: "/app",
+		Annotations: bass.Bindings{
+			"org.opencontainers.image.source": bass.String("https://example.com"),
+		}.Scope(),
+		Labels: bass.Bindings{
+			"org.opencontainers.image.version": bass.String("1.0"),
+		}.Scope(),
+		StopSignal: "SIGINT",
+		Healthcheck: &bass.ThunkHealthcheck{
+			Command:     []string{"check"},
+			Interval:    time.Minute,
+			Timeout:     time.Second,
+			StartPeriod: 10 * time.Second,
+			Retries:     3,
+		},
+		Volumes: []string{"/data"},
+	},
 }
 
 var validThunkImages = []bass.ThunkImage{
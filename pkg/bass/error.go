@@ -1,6 +1,9 @@
 package bass
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 type Error struct {
 	Err error
@@ -52,3 +55,44 @@ var _ Combiner = Error{}
 func (value Error) Call(ctx context.Context, val Value, scope *Scope, cont Cont) ReadyCont {
 	return cont.Call(nil, value.Err)
 }
+
+// NewErrorScope returns a scope describing the error for use by (try)
+// handlers.
+//
+// The scope contains the error :message, any structured fields passed to
+// (error), the :trace of frames leading up to the error, the failed :thunk if
+// there is one, and the original :error which re-raises when called.
+func NewErrorScope(err error, frames []*Annotate) *Scope {
+	scope := NewEmptyScope()
+
+	var structured *StructuredError
+	if errors.As(err, &structured) {
+		_ = structured.Fields.Each(func(k Symbol, v Value) error {
+			scope.Set(k, v)
+			return nil
+		})
+
+		scope.Set("message", String(structured.Message))
+	} else {
+		scope.Set("message", String(err.Error()))
+	}
+
+	trace := make([]Value, len(frames))
+	for i, frame := range frames {
+		meta := NewEmptyScope()
+		frame.Range.ToMeta(meta)
+		meta.Set("form", frame.Value)
+		trace[i] = meta
+	}
+
+	scope.Set("trace", NewList(trace...))
+
+	var thunkErr ThunkError
+	if errors.As(err, &thunkErr) {
+		scope.Set("thunk", thunkErr.Thunk)
+	}
+
+	scope.Set("error", Error{err})
+
+	return scope
+}
@@ -53,3 +53,49 @@ func TestErrorDecode(t *testing.T) {
 	is.NoErr(errv.Decode(&inner))
 	is.Equal(errv.Err, inner)
 }
+
+func TestNewErrorScope(t *testing.T) {
+	is := is.New(t)
+
+	thunk := bass.MustThunk(bass.CommandPath{"false"})
+	structured := bass.NewError("oh no!", bass.Symbol("exit-code"), bass.Int(2))
+	err := bass.ThunkError{Thunk: thunk, Err: structured}
+
+	frame := &bass.Annotate{
+		Value: bass.Symbol("boom"),
+		Range: bass.Range{
+			File:  bass.NewInMemoryFile("test", ""),
+			Start: bass.Position{Ln: 1, Col: 2},
+		},
+	}
+
+	scope := bass.NewErrorScope(err, []*bass.Annotate{frame})
+
+	var msg string
+	is.NoErr(scope.GetDecode("message", &msg))
+	is.Equal(msg, "oh no!")
+
+	var code int
+	is.NoErr(scope.GetDecode("exit-code", &code))
+	is.Equal(code, 2)
+
+	var failed bass.Thunk
+	is.NoErr(scope.GetDecode("thunk", &failed))
+	is.True(failed.Equal(thunk))
+
+	var trace []*bass.Scope
+	is.NoErr(scope.GetDecode("trace", &trace))
+	is.Equal(len(trace), 1)
+
+	var form bass.Symbol
+	is.NoErr(trace[0].GetDecode("form", &form))
+	is.Equal(form, bass.Symbol("boom"))
+
+	var line int
+	is.NoErr(trace[0].GetDecode("line", &line))
+	is.Equal(line, 1)
+
+	var errv bass.Error
+	is.NoErr(scope.GetDecode("error", &errv))
+	is.True(errors.Is(errv.Err, structured))
+}
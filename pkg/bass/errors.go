@@ -1,11 +1,13 @@
 package bass
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/agext/levenshtein"
 	"github.com/morikuni/aec"
@@ -84,21 +86,7 @@ func (err UnboundError) Error() string {
 func (unbound UnboundError) NiceError(w io.Writer, outer error) error {
 	fmt.Fprintln(w, aec.RedF.Apply(outer.Error()))
 
-	similar := []Symbol{}
-	unbound.Scope.Each(func(k Symbol, _ Value) error {
-		if levenshtein.Match(string(unbound.Symbol), string(k), nil) > 0.5 {
-			similar = append(similar, k)
-		}
-
-		return nil
-	})
-
-	sort.Slice(similar, func(i, j int) bool {
-		a := levenshtein.Match(string(unbound.Symbol), string(similar[i]), nil)
-		b := levenshtein.Match(string(unbound.Symbol), string(similar[j]), nil)
-		return a > b // higher scores first
-	})
-
+	similar := unbound.Similar()
 	if len(similar) > 0 {
 		fmt.Fprintln(w)
 		fmt.Fprintln(w, `similar bindings:`)
@@ -123,6 +111,27 @@ func (unbound UnboundError) NiceError(w io.Writer, outer error) error {
 	return nil
 }
 
+// Similar returns the bindings in the scope which are similar to the unbound
+// symbol, most similar first.
+func (unbound UnboundError) Similar() []Symbol {
+	similar := []Symbol{}
+	unbound.Scope.Each(func(k Symbol, _ Value) error {
+		if levenshtein.Match(string(unbound.Symbol), string(k), nil) > 0.5 {
+			similar = append(similar, k)
+		}
+
+		return nil
+	})
+
+	sort.Slice(similar, func(i, j int) bool {
+		a := levenshtein.Match(string(unbound.Symbol), string(similar[i]), nil)
+		b := levenshtein.Match(string(unbound.Symbol), string(similar[j]), nil)
+		return a > b // higher scores first
+	})
+
+	return similar
+}
+
 type ArityError struct {
 	Name     string
 	Need     int
@@ -230,6 +239,37 @@ func (structured *StructuredError) NiceError(w io.Writer, outer error) error {
 	return nil
 }
 
+// ThunkError is returned when a runtime fails to run a thunk.
+//
+// It records the thunk that failed so that it may be inspected by (try)
+// handlers, and otherwise behaves exactly like the underlying error.
+type ThunkError struct {
+	Thunk Thunk
+	Err   error
+}
+
+func (err ThunkError) Error() string {
+	return err.Err.Error()
+}
+
+func (err ThunkError) Unwrap() error {
+	return err.Err
+}
+
+// TimeoutError is returned when a thunk or form does not finish within its
+// timeout.
+type TimeoutError struct {
+	Timeout time.Duration
+}
+
+func (err TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s", err.Timeout)
+}
+
+func (err TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
 // HostPathEscapeError is returned when an attempt is made to (read) a host
 // path that traverses outside of its context dir.
 type HostPathEscapeError struct {
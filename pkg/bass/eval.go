@@ -60,6 +60,15 @@ func EvalReader(ctx context.Context, e *Scope, r io.Reader, source Readable) (Va
 	return res, nil
 }
 
+// evalSettled evaluates a form to completion in a nested trampoline, for
+// builtins which need its result before they can continue, e.g. to handle
+// its error, time it out, retry it, or evaluate it concurrently.
+//
+// TODO: using a Trampoline here is a bit of a smell
+func evalSettled(ctx context.Context, scope *Scope, form Value) (Value, error) {
+	return Trampoline(ctx, form.Eval(ctx, scope, Identity))
+}
+
 func Trampoline(ctx context.Context, val Value) (Value, error) {
 	var err error
 	for ctx.Err() == nil {
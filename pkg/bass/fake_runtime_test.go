@@ -13,6 +13,10 @@ import (
 
 type FakeRuntime struct {
 	ExportPaths []ExportPath
+	RunFunc     func(context.Context, bass.Thunk) error
+	ResultFunc  func(context.Context, bass.Thunk) (bass.RunResult, error)
+	ExportFunc  func(context.Context, io.Writer, bass.Thunk, bass.ExportOpts) error
+	PublishFunc func(context.Context, bass.ImageRef, bass.Thunk) (bass.ImageRef, error)
 }
 
 type ExportPath struct {
@@ -26,7 +30,11 @@ func (fake *FakeRuntime) Resolve(context.Context, bass.ImageRef) (bass.Thunk, er
 	return bass.Thunk{}, fmt.Errorf("Resolve unimplemented")
 }
 
-func (fake *FakeRuntime) Run(context.Context, bass.Thunk) error {
+func (fake *FakeRuntime) Run(ctx context.Context, thunk bass.Thunk) error {
+	if fake.RunFunc != nil {
+		return fake.RunFunc(ctx, thunk)
+	}
+
 	return fmt.Errorf("Run unimplemented")
 }
 
@@ -34,18 +42,38 @@ func (fake *FakeRuntime) Read(context.Context, io.Writer, bass.Thunk) error {
 	return fmt.Errorf("Read unimplemented")
 }
 
+func (fake *FakeRuntime) RunResult(ctx context.Context, thunk bass.Thunk) (bass.RunResult, error) {
+	if fake.ResultFunc != nil {
+		return fake.ResultFunc(ctx, thunk)
+	}
+
+	return bass.RunResult{}, fmt.Errorf("RunResult unimplemented")
+}
+
 func (fake *FakeRuntime) Load(context.Context, bass.Thunk) (*bass.Scope, error) {
 	return nil, fmt.Errorf("Load unimplemented")
 }
 
-func (fake *FakeRuntime) Export(context.Context, io.Writer, bass.Thunk) error {
+func (fake *FakeRuntime) Export(ctx context.Context, w io.Writer, thunk bass.Thunk, opts bass.ExportOpts) error {
+	if fake.ExportFunc != nil {
+		return fake.ExportFunc(ctx, w, thunk, opts)
+	}
+
 	return fmt.Errorf("Export unimplemented")
 }
 
-func (fake *FakeRuntime) Publish(context.Context, bass.ImageRef, bass.Thunk) (bass.ImageRef, error) {
+func (fake *FakeRuntime) Publish(ctx context.Context, ref bass.ImageRef, thunk bass.Thunk) (bass.ImageRef, error) {
+	if fake.PublishFunc != nil {
+		return fake.PublishFunc(ctx, ref, thunk)
+	}
+
 	return bass.ImageRef{}, fmt.Errorf("Publish unimplemented")
 }
 
+func (fake *FakeRuntime) PublishIndex(context.Context, bass.ImageRef, []bass.Thunk) (bass.ImageRef, error) {
+	return bass.ImageRef{}, fmt.Errorf("PublishIndex unimplemented")
+}
+
 func (fake *FakeRuntime) SetExportPath(path bass.ThunkPath, fs fstest.MapFS) {
 	fake.ExportPaths = append([]ExportPath{{path, fs}}, fake.ExportPaths...)
 }
@@ -103,6 +131,10 @@ func (fake *FakeRuntime) Prune(context.Context, bass.PruneOpts) error {
 	return fmt.Errorf("Prune unimplemented")
 }
 
+func (fake *FakeRuntime) Caches(context.Context) ([]bass.CacheInfo, error) {
+	return nil, fmt.Errorf("Caches unimplemented")
+}
+
 func (fake *FakeRuntime) Close() error {
 	return nil
 }
@@ -0,0 +1,74 @@
+package bass
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Float float64
+
+func (value Float) String() string {
+	str := strconv.FormatFloat(float64(value), 'g', -1, 64)
+
+	// always include a decimal point so the value reads back as a Float
+	if !strings.ContainsAny(str, ".eIN") {
+		str += ".0"
+	}
+
+	return str
+}
+
+func (value Float) Equal(other Value) bool {
+	var o Float
+	return other.Decode(&o) == nil && value == o
+}
+
+func (value Float) Decode(dest any) error {
+	switch x := dest.(type) {
+	case *Float:
+		*x = value
+		return nil
+	case *Value:
+		*x = value
+		return nil
+	case *Bindable:
+		*x = value
+		return nil
+	case *float64:
+		*x = float64(value)
+		return nil
+	default:
+		return DecodeError{
+			Source:      value,
+			Destination: dest,
+		}
+	}
+}
+
+// MarshalJSON encodes the value as a JSON number which always includes a
+// decimal point, so that it decodes back into a Float.
+func (value Float) MarshalJSON() ([]byte, error) {
+	f := float64(value)
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, EncodeError{value}
+	}
+
+	return []byte(value.String()), nil
+}
+
+// Eval returns the value.
+func (value Float) Eval(_ context.Context, _ *Scope, cont Cont) ReadyCont {
+	return cont.Call(value, nil)
+}
+
+var _ Bindable = Float(0)
+
+func (binding Float) Bind(_ context.Context, _ *Scope, cont Cont, val Value, _ ...Annotated) ReadyCont {
+	return cont.Call(binding, BindConst(binding, val))
+}
+
+func (Float) EachBinding(func(Symbol, Range) error) error {
+	return nil
+}
@@ -0,0 +1,51 @@
+package bass_test
+
+import (
+	"testing"
+
+	"github.com/vito/bass/pkg/bass"
+	. "github.com/vito/bass/pkg/basstest"
+	"github.com/vito/is"
+)
+
+func TestFloatDecode(t *testing.T) {
+	is := is.New(t)
+
+	var foo float64
+	err := bass.Float(4.2).Decode(&foo)
+	is.NoErr(err)
+	is.Equal(4.2, foo)
+
+	var f bass.Float
+	err = bass.Float(4.2).Decode(&f)
+	is.NoErr(err)
+	is.Equal(f, bass.Float(4.2))
+
+	var i int
+	err = bass.Float(4.2).Decode(&i)
+	is.True(err != nil)
+
+	err = bass.Int(42).Decode(&foo)
+	is.NoErr(err)
+	is.Equal(42.0, foo)
+}
+
+func TestFloatEqual(t *testing.T) {
+	is := is.New(t)
+
+	Equal(t, bass.Float(4.2), bass.Float(4.2))
+	Equal(t, bass.Float(0), bass.Float(0))
+	is.True(!bass.Float(4.2).Equal(bass.Float(0)))
+	is.True(!bass.Float(42).Equal(bass.Int(42)))
+	Equal(t, bass.Float(4.2), wrappedValue{bass.Float(4.2)})
+	is.True(!bass.Float(4.2).Equal(wrappedValue{bass.Float(0)}))
+}
+
+func TestFloatString(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(bass.Float(4.2).String(), "4.2")
+	is.Equal(bass.Float(42).String(), "42.0")
+	is.Equal(bass.Float(-0.5).String(), "-0.5")
+	is.Equal(bass.Float(1e21).String(), "1e+21")
+}
@@ -1,21 +1,26 @@
 package bass
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"path"
+	"runtime"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/docker/distribution/reference"
 	"github.com/jonboulle/clockwork"
+	"github.com/pelletier/go-toml/v2"
 	"github.com/vito/bass/pkg/internal"
 	"github.com/vito/bass/pkg/ioctx"
 	"github.com/vito/bass/pkg/zapctx"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
 )
 
 // Ground is the scope providing the standard library.
@@ -100,6 +105,40 @@ func init() {
 		`returns a string containing val encoded as JSON`,
 		`=> (json {:foo-bar "baz"})`)
 
+	Ground.Set("yaml",
+		Func("yaml", "[val]", func(val Value) (string, error) {
+			native, err := ToNative(val)
+			if err != nil {
+				return "", err
+			}
+
+			payload, err := yaml.Marshal(native)
+			if err != nil {
+				return "", err
+			}
+
+			return string(payload), nil
+		}),
+		`returns a string containing val encoded as YAML`,
+		`=> (yaml {:foo-bar "baz" :nums [1 2 3]})`)
+
+	Ground.Set("toml",
+		Func("toml", "[scope]", func(scope *Scope) (string, error) {
+			native, err := ToNative(scope)
+			if err != nil {
+				return "", err
+			}
+
+			payload, err := toml.Marshal(native)
+			if err != nil {
+				return "", err
+			}
+
+			return string(payload), nil
+		}),
+		`returns a string containing scope encoded as TOML`,
+		`=> (toml {:name "bass" :deps {:go "1.20"}})`)
+
 	Ground.Set("log",
 		Func("log", "[val & fields]", func(ctx context.Context, v Value, kv ...Value) (Value, error) {
 			logger := zapctx.FromContext(ctx)
@@ -145,6 +184,138 @@ func init() {
 		`=> (error "oh no!")`,
 		`=> (error "oh no!" :exit-code 2)`)
 
+	Ground.Set("try",
+		Annotated{
+			Value: Op("try", "[form handler]", func(ctx context.Context, cont Cont, scope *Scope, form, handler Value) ReadyCont {
+				trace, traced := TraceFrom(ctx)
+
+				var depth int
+				if traced {
+					depth = trace.depth
+				}
+
+				res, err := evalSettled(ctx, scope, form)
+				if err == nil {
+					return cont.Call(res, nil)
+				}
+
+				if errors.Is(err, ErrInterrupted) || ctx.Err() != nil {
+					return cont.Call(nil, err)
+				}
+
+				var frames []*Annotate
+				if traced && trace.depth > depth {
+					// only keep the frames from within the form
+					frames = trace.Frames()
+					if n := trace.depth - depth; n < len(frames) {
+						frames = frames[len(frames)-n:]
+					}
+
+					// unwind the frames left behind by the error
+					trace.Pop(trace.depth - depth)
+				}
+
+				return handler.Eval(ctx, scope, Continue(func(res Value) Value {
+					var comb Combiner
+					if err := res.Decode(&comb); err != nil {
+						return cont.Call(nil, err)
+					}
+
+					return comb.Call(ctx, NewList(NewErrorScope(err, frames)), scope, cont)
+				}))
+			}),
+			Meta: Bindings{"indent": Bool(true)}.Scope(),
+		},
+		`evaluates a form, calling handler with the error if it fails`,
+		`The handler is only evaluated if the form raises an error. It is called with a scope containing the error :message, any structured fields passed to [error], the :trace of frames from the form to the error, and the failed :thunk if there is one.`,
+		`The original :error is also included. Calling it re-raises the error.`,
+		`=> (try (error "oh no!" :exit-code 2) (fn [err] (:exit-code err)))`,
+		`=> (try (next (list->source [])) (fn [err] (:message err)))`,
+		`=> (try (run (from (linux/alpine) ($ false))) (fn [err] (thunk-cmd (:thunk err))))`)
+
+	Ground.Set("timeout",
+		Annotated{
+			Value: Op("timeout", "[seconds form]", func(ctx context.Context, cont Cont, scope *Scope, secondsForm, form Value) ReadyCont {
+				return secondsForm.Eval(ctx, scope, Continue(func(res Value) Value {
+					var seconds float64
+					if err := res.Decode(&seconds); err != nil {
+						return cont.Call(nil, err)
+					}
+
+					timeout := secondsDuration(seconds)
+
+					tctx, cancel := context.WithCancelCause(ctx)
+					timer := time.AfterFunc(timeout, func() {
+						cancel(TimeoutError{Timeout: timeout})
+					})
+
+					res, err := evalSettled(tctx, scope, form)
+					timer.Stop()
+
+					if err != nil {
+						err = timeoutErr(ctx, tctx, err)
+					}
+
+					// anything started by the form is bounded by the timeout, so it
+					// ends with the form, e.g. a stream returned by (read)
+					cancel(err)
+
+					return cont.Call(res, err)
+				}))
+			}),
+			Meta: Bindings{"indent": Bool(true)}.Scope(),
+		},
+		`evaluates a form, raising an error if it takes longer than the given seconds`,
+		`Once the timeout elapses, any work started by the form is canceled, including running thunks and waiting on (next).`,
+		`Work started by the form is also canceled once it returns, so a stream must be consumed within the form rather than returned from it.`,
+		`=> (timeout 1 (+ 1 2))`,
+		`=> (try (timeout 0.1 (next (read ($ sleep 10) :raw))) (fn [err] (:message err)))`)
+
+	Ground.Set("retry",
+		Annotated{
+			Value: Op("retry", "[attempts backoff form]", func(ctx context.Context, cont Cont, scope *Scope, attemptsForm, backoffForm, form Value) ReadyCont {
+				return attemptsForm.Eval(ctx, scope, Continue(func(attemptsVal Value) Value {
+					return backoffForm.Eval(ctx, scope, Continue(func(backoffVal Value) Value {
+						var attempts int
+						if err := attemptsVal.Decode(&attempts); err != nil {
+							return cont.Call(nil, err)
+						}
+
+						var backoff float64
+						if err := backoffVal.Decode(&backoff); err != nil {
+							return cont.Call(nil, err)
+						}
+
+						trace, traced := TraceFrom(ctx)
+
+						var depth int
+						if traced {
+							depth = trace.depth
+						}
+
+						var res Value
+						err := Retry(ctx, "retry "+form.String(), attempts, secondsDuration(backoff), func(ctx context.Context) error {
+							// unwind the frames left behind by a failed attempt
+							if traced && trace.depth > depth {
+								trace.Pop(trace.depth - depth)
+							}
+
+							var err error
+							res, err = evalSettled(ctx, scope, form)
+							return err
+						})
+
+						return cont.Call(res, err)
+					}))
+				}))
+			}),
+			Meta: Bindings{"indent": Bool(true)}.Scope(),
+		},
+		`evaluates a form, evaluating it again if it fails`,
+		`The form is evaluated up to the given number of attempts. Between attempts it waits for the backoff, in seconds, which grows exponentially with each attempt.`,
+		`If every attempt fails, the error from the last attempt is raised.`,
+		`=> (retry 3 0.1 (run (from (linux/alpine) ($ sh -c "exit $((RANDOM % 2))"))))`)
+
 	Ground.Set("now",
 		Func("now", "[seconds]", func(duration int) string {
 			return Clock.Now().Truncate(time.Duration(duration) * time.Second).UTC().Format(time.RFC3339)
@@ -202,6 +373,19 @@ func init() {
 		`=> (eval :abc {:abc 123})`,
 		`=> (eval [* :x :y] {:x 6 :y 7})`)
 
+	Ground.Set("quasiquote",
+		Op("quasiquote", "[form]", func(ctx context.Context, cont Cont, scope *Scope, form Value) ReadyCont {
+			return Quasiquote(ctx, scope, form, cont)
+		}),
+		`returns a form without evaluating it, except for unquoted forms`,
+		"Typically written with reader syntax, i.e. `form instead of (quasiquote form).",
+		`Within the form, ~x (unquote x) is replaced by the value of x, and ~@xs (unquote-splicing xs) splices the list value of xs into the enclosing list.`,
+		`This is handy for operatives which build up code to evaluate.`,
+		"=> (def x 42)",
+		"=> `(+ 1 ~x)",
+		"=> `[1 ~@[2 3] 4]",
+		"=> (eval `(* ~@[6 7]) (current-scope))")
+
 	Ground.Set("make-scope",
 		Func("make-scope", "parents", NewEmptyScope),
 		`construct a scope with the given parents`,
@@ -249,82 +433,112 @@ func init() {
 	}
 
 	Ground.Set("+",
-		Func("+", "nums", func(nums ...int) int {
-			sum := 0
-			for _, num := range nums {
-				sum += num
+		Func("+", "nums", func(nums ...Value) (Value, error) {
+			ints, floats, isFloat, err := numbers(nums)
+			if err != nil {
+				return nil, err
+			}
+
+			if isFloat {
+				return Float(sum(floats)), nil
 			}
 
-			return sum
+			return Int(sum(ints)), nil
 		}),
 		`sums numbers`,
-		`=> (+ 1 2 3)`)
+		`Returns a float if any of the numbers is a float.`,
+		`=> (+ 1 2 3)`,
+		`=> (+ 1 2.5)`)
 
 	Ground.Set("*",
-		Func("*", "nums", func(nums ...int) int {
-			mul := 1
-			for _, num := range nums {
-				mul *= num
+		Func("*", "nums", func(nums ...Value) (Value, error) {
+			ints, floats, isFloat, err := numbers(nums)
+			if err != nil {
+				return nil, err
+			}
+
+			if isFloat {
+				return Float(product(floats)), nil
 			}
 
-			return mul
+			return Int(product(ints)), nil
 		}),
 		`multiplies numbers`,
-		`=> (* 2 3 7)`)
+		`Returns a float if any of the numbers is a float.`,
+		`=> (* 2 3 7)`,
+		`=> (* 2 0.5)`)
 
 	Ground.Set("quot",
-		Func("quot", "[num denom]", func(num, denom int) int {
-			return num / denom
+		Func("quot", "[num denom]", func(num, denom Value) (Value, error) {
+			ints, floats, isFloat, err := numbers([]Value{num, denom})
+			if err != nil {
+				return nil, err
+			}
+
+			if isFloat {
+				return Float(floats[0] / floats[1]), nil
+			}
+
+			return Int(ints[0] / ints[1]), nil
 		}),
 		`quot(ient) of dividing num by denum`,
-		`=> (quot 84 2)`)
+		`Integer division is used unless either number is a float.`,
+		`=> (quot 84 2)`,
+		`=> (quot 1 2.0)`)
 
 	Ground.Set("-",
-		Func("-", "[num & nums]", func(num int, nums ...int) int {
-			if len(nums) == 0 {
-				return -num
+		Func("-", "[num & nums]", func(num Value, nums ...Value) (Value, error) {
+			ints, floats, isFloat, err := numbers(append([]Value{num}, nums...))
+			if err != nil {
+				return nil, err
 			}
 
-			sub := num
-			for _, num := range nums {
-				sub -= num
+			if isFloat {
+				return Float(difference(floats)), nil
 			}
 
-			return sub
+			return Int(difference(ints)), nil
 		}),
 		`subtracts ys from x`,
 		`If only x is given, returns the negation of x.`,
 		`=> (- 10 4)`,
 		`=> (- 10 4 1)`,
-		`=> (- 6)`)
+		`=> (- 6)`,
+		`=> (- 1.5 1)`)
 
 	Ground.Set("max",
-		Func("max", "[num & nums]", func(num int, nums ...int) int {
-			max := num
-			for _, num := range nums {
-				if num > max {
-					max = num
-				}
+		Func("max", "[num & nums]", func(num Value, nums ...Value) (Value, error) {
+			ints, floats, isFloat, err := numbers(append([]Value{num}, nums...))
+			if err != nil {
+				return nil, err
+			}
+
+			if isFloat {
+				return Float(slices.Max(floats)), nil
 			}
 
-			return max
+			return Int(slices.Max(ints)), nil
 		}),
 		`returns the largest number`,
-		`=> (max 6 42 7)`)
+		`=> (max 6 42 7)`,
+		`=> (max 6 42.5 7)`)
 
 	Ground.Set("min",
-		Func("min", "[num & nums]", func(num int, nums ...int) int {
-			min := num
-			for _, num := range nums {
-				if num < min {
-					min = num
-				}
+		Func("min", "[num & nums]", func(num Value, nums ...Value) (Value, error) {
+			ints, floats, isFloat, err := numbers(append([]Value{num}, nums...))
+			if err != nil {
+				return nil, err
 			}
 
-			return min
+			if isFloat {
+				return Float(slices.Min(floats)), nil
+			}
+
+			return Int(slices.Min(ints)), nil
 		}),
 		`returns the smallest number`,
-		`=> (min 6 42 7)`)
+		`=> (min 6 42 7)`,
+		`=> (min 6.5 42 7)`)
 
 	Ground.Set("=",
 		Func("=", "[val & vals]", func(val Value, others ...Value) bool {
@@ -343,68 +557,42 @@ func init() {
 	)
 
 	Ground.Set(">",
-		Func(">", "[num & nums]", func(num int, nums ...int) bool {
-			min := num
-			for _, num := range nums {
-				if num >= min {
-					return false
-				}
-
-				min = num
-			}
-
-			return true
+		Func(">", "[num & nums]", func(num Value, nums ...Value) (bool, error) {
+			return compareNums(append([]Value{num}, nums...), func(c int) bool {
+				return c > 0
+			})
 		}),
 		`returns true if the numbers are in descending order`,
 		`=> (> 9 8 7)`,
-		`=> (> 9 8 8)`)
+		`=> (> 9 8 8)`,
+		`=> (> 9 8.5 8)`)
 
 	Ground.Set(">=",
-		Func(">=", "[num & nums]", func(num int, nums ...int) bool {
-			max := num
-			for _, num := range nums {
-				if num > max {
-					return false
-				}
-
-				max = num
-			}
-
-			return true
+		Func(">=", "[num & nums]", func(num Value, nums ...Value) (bool, error) {
+			return compareNums(append([]Value{num}, nums...), func(c int) bool {
+				return c >= 0
+			})
 		}),
 		`returns true if the numbers are in descending or equal order`,
 		`=> (> 9 8 7)`,
 		`=> (> 9 8 8)`)
 
 	Ground.Set("<",
-		Func("<", "[num & nums]", func(num int, nums ...int) bool {
-			max := num
-			for _, num := range nums {
-				if num <= max {
-					return false
-				}
-
-				max = num
-			}
-
-			return true
+		Func("<", "[num & nums]", func(num Value, nums ...Value) (bool, error) {
+			return compareNums(append([]Value{num}, nums...), func(c int) bool {
+				return c < 0
+			})
 		}),
 		`returns true if the numbers are in ascending order`,
 		`=> (< 7 8 9)`,
-		`=> (> 8 8 9)`)
+		`=> (> 8 8 9)`,
+		`=> (< 7 7.5 8)`)
 
 	Ground.Set("<=",
-		Func("<=", "[num & nums]", func(num int, nums ...int) bool {
-			max := num
-			for _, num := range nums {
-				if num < max {
-					return false
-				}
-
-				max = num
-			}
-
-			return true
+		Func("<=", "[num & nums]", func(num Value, nums ...Value) (bool, error) {
+			return compareNums(append([]Value{num}, nums...), func(c int) bool {
+				return c <= 0
+			})
 		}),
 		`returns true if the numbers are in ascending or equal order`,
 		`=> (< 7 8 9)`,
@@ -442,7 +630,7 @@ func init() {
 
 	Ground.Set("next",
 		Func("next", "[src & default]", func(ctx context.Context, source PipeSource, def ...Value) (Value, error) {
-			val, err := source.Next(ctx)
+			val, err := nextInterruptible(ctx, source)
 			if err != nil {
 				if errors.Is(err, ErrEndOfSource) && len(def) > 0 {
 					return def[0], nil
@@ -485,6 +673,99 @@ func init() {
 		`=> (reduce-kv assoc {:d 4} {:a 1 :b 2 :c 3})`,
 	)
 
+	Ground.Set("pmap",
+		Wrap(Op("pmap", "[f xs & limit]", func(ctx context.Context, scope *Scope, fn Applicative, xs List, limit ...int) (List, error) {
+			op := fn.Unwrap()
+
+			vals, err := ToSlice(xs)
+			if err != nil {
+				return nil, err
+			}
+
+			workers := runtime.NumCPU()
+			switch len(limit) {
+			case 0:
+			case 1:
+				if limit[0] < 1 {
+					return nil, fmt.Errorf("pmap: limit must be at least 1, have %d", limit[0])
+				}
+
+				workers = limit[0]
+			default:
+				return nil, ArityError{
+					Name: "pmap",
+					Need: 3,
+					Have: 2 + len(limit),
+				}
+			}
+
+			if workers > len(vals) {
+				workers = len(vals)
+			}
+
+			parent := ctx
+
+			ctx, stop := context.WithCancel(ctx)
+			defer stop()
+
+			ctx, runs := TrackRuns(ctx)
+
+			indices := make(chan int, len(vals))
+			for i := range vals {
+				indices <- i
+			}
+			close(indices)
+
+			results := make([]Value, len(vals))
+			for w := 0; w < workers; w++ {
+				// each goroutine must have its own trace
+				workerCtx := ForkTrace(ctx)
+
+				runs.Go(stop, func() error {
+					for i := range indices {
+						if workerCtx.Err() != nil {
+							// canceled by a sibling's failure
+							return nil
+						}
+
+						// the operative is self-evaluating and receives the value as-is
+						res, err := evalSettled(workerCtx, scope, NewList(op, vals[i]))
+						if err != nil {
+							if workerCtx.Err() != nil {
+								// canceled by a sibling's failure
+								return nil
+							}
+
+							// cancel siblings
+							stop()
+
+							return err
+						}
+
+						results[i] = res
+					}
+
+					return nil
+				})
+			}
+
+			if err := runs.Wait(); err != nil {
+				return nil, err
+			}
+
+			if parent.Err() != nil {
+				return nil, ErrInterrupted
+			}
+
+			return NewList(results...), nil
+		})),
+		`returns a list containing the result of applying f to each member of xs, concurrently`,
+		`Calls f with up to limit values at a time, or one per CPU if no limit is given. Results are returned in the same order as xs.`,
+		`If any call fails, the rest are canceled and the error is raised.`,
+		`=> (pmap (fn [x] (* x 7)) [5 6 7])`,
+		`=> (pmap (fn [x] (* x 7)) [5 6 7] 2)`,
+	)
+
 	Ground.Set("assoc",
 		Func("assoc", "[obj & kvs]", Assoc),
 		`assoc(iate) keys with values in a clone of a scope`,
@@ -541,11 +822,22 @@ func init() {
 
 	Ground.Set("substring",
 		Func("substring", "[str start & end]", func(str String, start Int, endOptional ...Int) (String, error) {
+			runes := []rune(str)
+
 			switch len(endOptional) {
 			case 0:
-				return str[start:], nil
+				if start < 0 || int(start) > len(runes) {
+					return "", fmt.Errorf("substring: start %d out of range for length %d", start, len(runes))
+				}
+
+				return String(runes[start:]), nil
 			case 1:
-				return str[start:endOptional[0]], nil
+				end := endOptional[0]
+				if start < 0 || end < start || int(end) > len(runes) {
+					return "", fmt.Errorf("substring: range [%d:%d] out of range for length %d", start, end, len(runes))
+				}
+
+				return String(runes[start:end]), nil
 			default:
 				// TODO: test
 				return "", ArityError{
@@ -558,6 +850,7 @@ func init() {
 		`returns a portion of a string`,
 		`With one number supplied, returns the portion from the offset to the end.`,
 		`With two numbers supplied, returns the portion between the first offset and the last offset, exclusive.`,
+		`Offsets count characters, not bytes.`,
 		`=> (substring "abcdef" 2 4)`)
 
 	Ground.Set("trim",
@@ -724,13 +1017,122 @@ func init() {
 		`=> (with-insecure (.boom) true)`,
 		`=> (= (.boom) (with-insecure (.boom) false))`)
 
+	Ground.Set("with-timeout",
+		Func("with-timeout", "[thunk seconds]", func(thunk Thunk, seconds float64) Thunk {
+			return thunk.WithTimeout(secondsDuration(seconds))
+		}),
+		`returns thunk with a timeout, in seconds`,
+		`Running or reading the thunk will fail with a timeout error if it takes longer than the timeout.`,
+		`=> (with-timeout ($ sleep 10) 5)`,
+		`=> (with-timeout ($ sleep 10) 0.5)`)
+
+	Ground.Set("with-retry",
+		Func("with-retry", "[thunk attempts backoff]", func(thunk Thunk, attempts int, backoff float64) Thunk {
+			return thunk.WithRetry(attempts, secondsDuration(backoff))
+		}),
+		`returns thunk configured to retry if it fails`,
+		`Running or reading the thunk will try up to the given number of attempts, waiting for the backoff, in seconds, between attempts. The backoff grows exponentially with each attempt.`,
+		`If every attempt fails, the error from the last attempt is raised.`,
+		`=> (with-retry ($ curl "https://example.com") 3 1)`)
+
+	Ground.Set("with-cpu",
+		Func("with-cpu", "[thunk cpus]", (Thunk).WithCPU),
+		`returns thunk with a limit on the number of CPUs it may use`,
+		`Fractional values may be given, e.g. 0.5 for half of one CPU.`,
+		`Raises an error when the thunk runs if the runtime cannot enforce the limit.`,
+		`=> (with-cpu ($ make) 2)`,
+		`=> (with-cpu ($ make) 0.5)`)
+
+	Ground.Set("with-memory",
+		Func("with-memory", "[thunk bytes]", (Thunk).WithMemory),
+		`returns thunk with a limit on the memory it may use, in bytes`,
+		`Raises an error when the thunk runs if the runtime cannot enforce the limit.`,
+		`=> (with-memory ($ make) (* 512 1024 1024))`)
+
+	Ground.Set("with-pids-limit",
+		Func("with-pids-limit", "[thunk limit]", (Thunk).WithPidsLimit),
+		`returns thunk with a limit on the number of processes it may run`,
+		`Raises an error when the thunk runs if the runtime cannot enforce the limit.`,
+		`=> (with-pids-limit ($ make) 100)`)
+
+	Ground.Set("with-network",
+		Func("with-network", "[thunk mode]", (Thunk).WithNetwork),
+		`returns thunk with the given network mode`,
+		`The :default mode runs the thunk in the runtime's network sandbox. The :none mode runs it with no network access. The :host mode runs it in the host's network and requires the thunk to be insecure.`,
+		`Raises an error when the thunk runs if the runtime cannot configure the network.`,
+		`=> (with-network ($ make test) :none)`,
+		`=> (-> ($ nc -l 8080) (with-insecure true) (with-network :host))`)
+
+	Ground.Set("with-user",
+		Func("with-user", "[thunk user]", (Thunk).WithUser),
+		`returns thunk with the user its command runs as`,
+		`The user may be a name, a uid, or a user and group separated by a colon, e.g. "nobody" or "1000:1000".`,
+		`When the thunk is exported or published, the user will be set in the OCI image config.`,
+		`=> (with-user ($ id) "nobody")`,
+		`=> (with-user ($ id) "1000:1000")`)
+
 	Ground.Set("with-label",
 		Func("with-label", "[thunk name val]", (Thunk).WithLabel),
 		`returns thunk with the label set to val`,
 		`Labels are used to set metadata on a thunk, and are not used by the thunk itself.`,
-		`When the thunk is exported or published, labels will be included in the OCI image.`,
+		`When the thunk is exported or published, labels will be included in the OCI image. To set a label only on the image, see [with-image-label].`,
 		`=> (with-label ($ sleep 10) :at (now 10))`)
 
+	Ground.Set("with-workdir",
+		Func("with-workdir", "[thunk dir]", (Thunk).WithWorkdir),
+		`returns thunk with the working directory set in its OCI image config`,
+		`The working directory only applies to containers run from the exported or published image. It does not affect the thunk's own command; see (with-dir).`,
+		`=> (with-workdir (from (linux/alpine) ($ true)) "/app")`)
+
+	Ground.Set("with-annotation",
+		Func("with-annotation", "[thunk name val]", (Thunk).WithAnnotation),
+		`returns thunk with an annotation set on its OCI image manifest`,
+		`=> (with-annotation (from (linux/alpine) ($ true)) :org.opencontainers.image.source "https://github.com/vito/bass")`)
+
+	Ground.Set("with-image-label",
+		Func("with-image-label", "[thunk name val]", (Thunk).WithImageLabel),
+		`returns thunk with a label set in its OCI image config`,
+		`Unlike [with-label], the label only applies to the exported or published image. It does not change the thunk's hash, so it doesn't bust the cache.`,
+		`=> (with-image-label (from (linux/alpine) ($ true)) :org.opencontainers.image.version "1.0")`)
+
+	Ground.Set("with-stop-signal",
+		Func("with-stop-signal", "[thunk signal]", (Thunk).WithStopSignal),
+		`returns thunk with the stop signal set in its OCI image config`,
+		`=> (with-stop-signal (from (linux/alpine) ($ true)) "SIGINT")`)
+
+	Ground.Set("with-healthcheck",
+		Func("with-healthcheck", "[thunk cmd & opts]", func(thunk Thunk, cmd []string, opts ...*Scope) (Thunk, error) {
+			var hcOpts struct {
+				Interval    float64 `json:"interval,omitempty"`
+				Timeout     float64 `json:"timeout,omitempty"`
+				StartPeriod float64 `json:"start-period,omitempty"`
+				Retries     int     `json:"retries,omitempty"`
+			}
+
+			if len(opts) > 0 {
+				if err := opts[0].Decode(&hcOpts); err != nil {
+					return thunk, fmt.Errorf("healthcheck opts: %w", err)
+				}
+			}
+
+			return thunk.WithHealthcheck(ThunkHealthcheck{
+				Command:     cmd,
+				Interval:    secondsDuration(hcOpts.Interval),
+				Timeout:     secondsDuration(hcOpts.Timeout),
+				StartPeriod: secondsDuration(hcOpts.StartPeriod),
+				Retries:     hcOpts.Retries,
+			})
+		}),
+		`returns thunk with a healthcheck set in its OCI image config`,
+		`The command is run in containers run from the image to check that they are healthy.`,
+		`Options may be passed as a scope with :interval, :timeout, and :start-period in seconds and the number of :retries.`,
+		`=> (with-healthcheck (from (linux/alpine) ($ true)) ["wget" "-q" "--spider" "http://localhost"] {:interval 30 :retries 3})`)
+
+	Ground.Set("with-volume",
+		Func("with-volume", "[thunk path]", (Thunk).WithVolume),
+		`returns thunk with a volume added to its OCI image config`,
+		`=> (with-volume (from (linux/alpine) ($ true)) "/var/lib/data")`)
+
 	Ground.Set("with-port",
 		Func("with-port", "[thunk sym int]", (Thunk).WithPort),
 		`returns thunk with a named port appended to its ports`,
@@ -785,6 +1187,16 @@ func init() {
 		`resolve an image reference to its most exact form`,
 		`=> (resolve {:platform {:os "linux"} :repository "golang" :tag "latest"})`)
 
+	Ground.Set("debug",
+		Func("debug", "[thunk]", func(ctx context.Context, thunk Thunk) error {
+			return thunk.Run(WithDebugShell(ctx))
+		}),
+		`runs a thunk, opening an interactive shell in its container if it fails`,
+		`The shell starts in the failed command's filesystem state, with the same mounts, env, and working directory. The thunk's error is raised once the shell exits.`,
+		`If the failure is in a thunk that the given thunk depends on, the shell is opened for that thunk instead.`,
+		`Runtimes which do not support debug shells just raise the error.`,
+		`=> (debug (from (linux/alpine) ($ sh -c "exit 1")))`)
+
 	Ground.Set("start",
 		Func("start", "[thunk handler]", func(ctx context.Context, thunk Thunk, handler Combiner) (Combiner, error) {
 			return thunk.Start(ctx, handler)
@@ -839,6 +1251,20 @@ func init() {
 		`=> (next (read file-thunk/file :json))`,
 	)
 
+	Ground.Set("run-result",
+		Func("run-result", "[thunk]", func(ctx context.Context, thunk Thunk) (*Scope, error) {
+			result, err := thunk.Result(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			return result.Scope(), nil
+		}),
+		`runs a thunk and returns its exit code and output`,
+		`Returns a scope with :exit-code, :stdout, and :stderr. Unlike (run), a non-zero exit code does not raise an error.`,
+		`=> (run-result (from (linux/alpine) ($ sh -c "echo out; echo err >&2; exit 3")))`,
+	)
+
 	Ground.Set("cache-dir",
 		Func("cache-dir", "[id & mode]", func(id string, mode ...Symbol) (CachePath, error) {
 			cache := NewCacheDir(id)
@@ -891,30 +1317,63 @@ func init() {
 
 	Ground.Set("publish",
 		Func("publish", "[src ref]", func(ctx context.Context, thunk Thunk, ref string) (ImageRef, error) {
-			r, err := reference.ParseDockerRef(ref)
+			imageRef, err := parseTaggedRef(ref)
 			if err != nil {
 				return ImageRef{}, err
 			}
 
-			nt, ok := r.(reference.NamedTagged)
-			if !ok {
-				return ImageRef{}, fmt.Errorf("ref must be named and tagged, have %T: %s", r, ref)
-			}
-
-			return thunk.Publish(ctx, ImageRef{
-				Repository: ImageRepository{Static: nt.Name()},
-				Tag:        nt.Tag(),
-			})
+			return thunk.Publish(ctx, imageRef)
 		}),
 		`publishes the thunk to a container registry`,
 		`Returns a fully qualified image reference.`,
 		`=> (publish (from (linux/golang) ($ go version)) "basslang/publish-demo")`)
 
+	Ground.Set("publish-index",
+		Func("publish-index", "[thunks ref]", func(ctx context.Context, thunks []Thunk, ref string) (ImageRef, error) {
+			imageRef, err := parseTaggedRef(ref)
+			if err != nil {
+				return ImageRef{}, err
+			}
+
+			return PublishIndex(ctx, imageRef, thunks)
+		}),
+		`publishes a multi-platform image index to a container registry`,
+		`Each thunk must have a different platform. They are all published by the runtime for the first thunk's platform, which must be able to build for the others.`,
+		`Returns a fully qualified image reference.`,
+		`=> (def alpine-arm64 {:platform {:os "linux" :architecture "arm64"} :repository "alpine" :tag "latest"})`,
+		`=> (publish-index [(from (linux/alpine) ($ true)) (from alpine-arm64 ($ true))] "basslang/publish-demo")`)
+
 	Ground.Set("export",
-		Func("export", "[thunk]", func(ctx context.Context, thunk Thunk) (Readable, error) {
+		Func("export", "[thunk & dest-and-opts]", func(ctx context.Context, thunk Thunk, args ...Value) (Value, error) {
+			var dest *HostPath
+			if len(args) > 0 {
+				var hp HostPath
+				if err := args[0].Decode(&hp); err == nil {
+					dest = &hp
+					args = args[1:]
+				}
+			}
+
+			opts, err := parseExportOpts(args)
+			if err != nil {
+				return nil, err
+			}
+
+			if dest != nil {
+				if err := thunk.ExportTo(ctx, *dest, opts); err != nil {
+					return nil, err
+				}
+
+				return *dest, nil
+			}
+
+			if opts.Format == ExportOCIDir {
+				return nil, fmt.Errorf("export format %s requires a host directory", opts.Format)
+			}
+
 			r, w := io.Pipe()
 			go func() {
-				w.CloseWithError(thunk.Export(ctx, w))
+				w.CloseWithError(thunk.Export(ctx, w, opts))
 			}()
 
 			return NewFSPath(
@@ -922,11 +1381,15 @@ func init() {
 				NewFileOrDirPath(NewFilePath("image.tar")),
 			), nil
 		}),
-		`returns a virtual file containing the thunk as an OCI tarball`,
-		`Note that the file can only be read once. You can either (read) it with the :tar protocol or (write) it to a host path.`,
+		`exports the thunk as an image`,
+		`With no destination, returns a virtual file containing the image tarball. Note that the file can only be read once. You can either (read) it with the :tar protocol or (write) it to a host path.`,
+		`With a host path destination, writes the image to the path and returns it.`,
+		`Options are passed as alternating keywords and values. The :format may be :oci (the default) for an OCI tarball, :oci-dir for an OCI image layout directory, :docker for a tarball that can be loaded with 'docker load', or :rootfs for a tarball of the root filesystem. :tags is a list of repository tags for the :docker format.`,
 		`=> (export (from (linux/alpine) ($ echo "Hello, world!")))`,
 		`=> (write (export (from (linux/alpine) ($ echo "Hello, world!"))) *dir*/image.tar)`,
-		`=> (next (read (export (from (linux/alpine) ($ echo "Hello, world!"))) :tar))`)
+		`=> (next (read (export (from (linux/alpine) ($ echo "Hello, world!"))) :tar))`,
+		`=> (export (from (linux/alpine) ($ echo "Hello, world!")) *dir*/image/ :format :oci-dir)`,
+		`=> (export (from (linux/alpine) ($ echo "Hello, world!")) *dir*/image.tar :format :docker :tags ["hello:latest"])`)
 
 	Ground.Set("only-globs", Func("only-globs", "[path & globs]", func(path Globbable, paths ...FilesystemPath) Globbable {
 		globs := make([]string, len(paths))
@@ -991,11 +1454,13 @@ var primPreds = []primPred{
 	}},
 
 	{"number?", func(val Value) bool {
-		var x Int
-		return val.Decode(&x) == nil
+		var i Int
+		var f Float
+		return val.Decode(&i) == nil || val.Decode(&f) == nil
 	}, []string{
 		`returns true if the value is a number`,
 		`=> (number? 123)`,
+		`=> (number? 1.5)`,
 		`=> (number? "123")`,
 	}},
 
@@ -1183,11 +1648,181 @@ func do(ctx context.Context, cont Cont, scope *Scope, body []Value) ReadyCont {
 	return body[0].Eval(ctx, scope, next)
 }
 
+// numbers decodes the values as numbers for arithmetic.
+//
+// If any of the values is a Float, all of them are returned as floats and
+// isFloat is true. Otherwise they are all returned as ints.
+func numbers(vals []Value) (ints []int, floats []float64, isFloat bool, err error) {
+	for _, val := range vals {
+		var f Float
+		if val.Decode(&f) == nil {
+			isFloat = true
+			break
+		}
+	}
+
+	if isFloat {
+		floats = make([]float64, len(vals))
+		for i, val := range vals {
+			if err := val.Decode(&floats[i]); err != nil {
+				return nil, nil, false, err
+			}
+		}
+
+		return nil, floats, true, nil
+	}
+
+	ints = make([]int, len(vals))
+	for i, val := range vals {
+		if err := val.Decode(&ints[i]); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	return ints, nil, false, nil
+}
+
+type number interface {
+	~int | ~float64
+}
+
+func sum[T number](nums []T) T {
+	var sum T
+	for _, num := range nums {
+		sum += num
+	}
+
+	return sum
+}
+
+func product[T number](nums []T) T {
+	var mul T = 1
+	for _, num := range nums {
+		mul *= num
+	}
+
+	return mul
+}
+
+func difference[T number](nums []T) T {
+	if len(nums) == 1 {
+		return -nums[0]
+	}
+
+	sub := nums[0]
+	for _, num := range nums[1:] {
+		sub -= num
+	}
+
+	return sub
+}
+
+// compareNums returns true if ok returns true for the comparison of each
+// number with the number that follows it.
+func compareNums(vals []Value, ok func(int) bool) (bool, error) {
+	ints, floats, isFloat, err := numbers(vals)
+	if err != nil {
+		return false, err
+	}
+
+	for i := 1; i < len(vals); i++ {
+		var c int
+		if isFloat {
+			c = cmp.Compare(floats[i-1], floats[i])
+		} else {
+			c = cmp.Compare(ints[i-1], ints[i])
+		}
+
+		if !ok(c) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// parseTaggedRef parses a named and tagged image reference, e.g.
+// "basslang/bass:latest".
+func parseTaggedRef(ref string) (ImageRef, error) {
+	r, err := reference.ParseDockerRef(ref)
+	if err != nil {
+		return ImageRef{}, err
+	}
+
+	nt, ok := r.(reference.NamedTagged)
+	if !ok {
+		return ImageRef{}, fmt.Errorf("ref must be named and tagged, have %T: %s", r, ref)
+	}
+
+	return ImageRef{
+		Repository: ImageRepository{Static: nt.Name()},
+		Tag:        nt.Tag(),
+	}, nil
+}
+
+func parseExportOpts(kvs []Value) (ExportOpts, error) {
+	if len(kvs)%2 != 0 {
+		return ExportOpts{}, fmt.Errorf("export options must be keyword-value pairs")
+	}
+
+	scope, err := Assoc(NewEmptyScope(), kvs...)
+	if err != nil {
+		return ExportOpts{}, err
+	}
+
+	var raw struct {
+		Format Symbol   `json:"format,omitempty"`
+		Tags   []string `json:"tags,omitempty"`
+	}
+	if err := scope.Decode(&raw); err != nil {
+		return ExportOpts{}, fmt.Errorf("export opts: %w", err)
+	}
+
+	format, err := ParseExportFormat(string(raw.Format))
+	if err != nil {
+		return ExportOpts{}, err
+	}
+
+	return ExportOpts{
+		Format: format,
+		Tags:   raw.Tags,
+	}, nil
+}
+
+// secondsDuration converts a number of seconds into a time.Duration.
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// nextInterruptible calls Next on the source, closing the source if the
+// context is canceled so that a Next blocked on reading returns.
+//
+// Not all sources respect context cancelation, e.g. those blocked on reading
+// a thunk's output. Once interrupted, the source can no longer be read.
+func nextInterruptible(ctx context.Context, source PipeSource) (Value, error) {
+	if ctx.Err() != nil {
+		return nil, ErrInterrupted
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = source.Close()
+	})
+
+	val, err := source.Next(ctx)
+	if !stop() {
+		// closed while reading; the value can't be trusted
+		return nil, ErrInterrupted
+	}
+
+	return val, err
+}
+
 func zapField(k Symbol, v Value) (zap.Field, error) {
 	name := k.String()
 
 	var str string
 	var num int
+	var flt float64
 	var bol bool
 	var am zapcore.ArrayMarshaler
 	var om zapcore.ObjectMarshaler
@@ -1195,6 +1830,8 @@ func zapField(k Symbol, v Value) (zap.Field, error) {
 		return zap.String(name, str), nil
 	} else if v.Decode(&num) == nil {
 		return zap.Int(name, num), nil
+	} else if v.Decode(&flt) == nil {
+		return zap.Float64(name, flt), nil
 	} else if v.Decode(&bol) == nil {
 		return zap.Bool(name, bol), nil
 	} else if v.Decode(&am) == nil {
@@ -9,6 +9,7 @@ import (
 	"io"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -243,6 +244,7 @@ func TestGroundPrimitivePredicates(t *testing.T) {
 			Name: "number?",
 			Trues: []bass.Value{
 				bass.Int(0),
+				bass.Float(0.5),
 			},
 			Falses: []bass.Value{
 				bass.Bool(true),
@@ -516,6 +518,46 @@ func TestGroundNumeric(t *testing.T) {
 			Bass:   "(min 5 3 7 2 4)",
 			Result: bass.Int(2),
 		},
+		{
+			Name:   "+ floats",
+			Bass:   "(+ 1 2.5 0.25)",
+			Result: bass.Float(3.75),
+		},
+		{
+			Name:   "- floats",
+			Bass:   "(- 1 0.25)",
+			Result: bass.Float(0.75),
+		},
+		{
+			Name:   "- unary float",
+			Bass:   "(- 1.5)",
+			Result: bass.Float(-1.5),
+		},
+		{
+			Name:   "* floats",
+			Bass:   "(* 2 1.5)",
+			Result: bass.Float(3),
+		},
+		{
+			Name:   "quot floats",
+			Bass:   "(quot 1 4.0)",
+			Result: bass.Float(0.25),
+		},
+		{
+			Name:   "max floats",
+			Bass:   "(max 1 7.5 7 5 4)",
+			Result: bass.Float(7.5),
+		},
+		{
+			Name:   "min floats",
+			Bass:   "(min 5 3 7 2.5 4)",
+			Result: bass.Float(2.5),
+		},
+		{
+			Name:        "+ non-number",
+			Bass:        `(+ 1 "2")`,
+			ErrContains: "cannot decode",
+		},
 	} {
 		test.Run(t)
 	}
@@ -725,6 +767,36 @@ func TestGroundComparison(t *testing.T) {
 			Bass:   "(<= 1 2 2)",
 			Result: bass.Bool(true),
 		},
+		{
+			Name:   "> floats",
+			Bass:   "(> 2 1.5 1)",
+			Result: bass.Bool(true),
+		},
+		{
+			Name:   "> floats eq",
+			Bass:   "(> 2 2.0 1)",
+			Result: bass.Bool(false),
+		},
+		{
+			Name:   ">= floats eq",
+			Bass:   "(>= 2 2.0 1.5)",
+			Result: bass.Bool(true),
+		},
+		{
+			Name:   "< floats",
+			Bass:   "(< 1 1.5 2)",
+			Result: bass.Bool(true),
+		},
+		{
+			Name:   "<= floats",
+			Bass:   "(<= 1.5 1.5 1)",
+			Result: bass.Bool(false),
+		},
+		{
+			Name:   "= int float",
+			Bass:   "(= 1 1.0)",
+			Result: bass.Bool(false),
+		},
 	} {
 		test.Run(t)
 	}
@@ -747,6 +819,90 @@ func TestGroundConstructors(t *testing.T) {
 			Bass:   "(quote abc)",
 			Result: bass.Symbol("abc"),
 		},
+		{
+			Name: "quasiquote",
+			Bass: "`(abc [def] {:ghi 1})",
+			Result: bass.NewList(
+				bass.Symbol("abc"),
+				bass.NewConsList(bass.Symbol("def")),
+				bass.Bind{bass.Keyword("ghi"), bass.Int(1)},
+			),
+		},
+		{
+			Name: "quasiquote unquote",
+			Bass: "(def x 42) `(abc [~x] {:ghi ~(+ x 1)})",
+			Result: bass.NewList(
+				bass.Symbol("abc"),
+				bass.NewConsList(bass.Int(42)),
+				bass.Bind{bass.Keyword("ghi"), bass.Int(43)},
+			),
+		},
+		{
+			Name: "quasiquote unquote-splicing",
+			Bass: "(def xs [2 3]) `(1 ~@xs 4 [~@xs] ~@[])",
+			Result: bass.NewList(
+				bass.Int(1),
+				bass.Int(2),
+				bass.Int(3),
+				bass.Int(4),
+				bass.NewConsList(bass.Int(2), bass.Int(3)),
+			),
+		},
+		{
+			Name:   "quasiquote unquote rest",
+			Bass:   "(def xs [2 3]) `(1 & ~xs)",
+			Result: bass.NewList(bass.Int(1), bass.Int(2), bass.Int(3)),
+		},
+		{
+			Name:   "quasiquote eval",
+			Bass:   "(def args [6 7]) (eval `(* ~@args) (current-scope))",
+			Result: bass.Int(42),
+		},
+		{
+			Name: "quasiquote nested",
+			Bass: "(def x 42) `(a `(b ~(c ~x)))",
+			Result: bass.NewList(
+				bass.Symbol("a"),
+				bass.NewList(
+					bass.Symbol("quasiquote"),
+					bass.NewList(
+						bass.Symbol("b"),
+						bass.NewList(
+							bass.Symbol("unquote"),
+							bass.NewList(bass.Symbol("c"), bass.Int(42)),
+						),
+					),
+				),
+			),
+		},
+		{
+			Name: "quasiquote nested unquote-splicing",
+			Bass: "(def xs [2 3]) `(a `(b ~@xs ~@(c ~@xs)))",
+			Result: bass.NewList(
+				bass.Symbol("a"),
+				bass.NewList(
+					bass.Symbol("quasiquote"),
+					bass.NewList(
+						bass.Symbol("b"),
+						bass.NewList(bass.Symbol("unquote-splicing"), bass.Symbol("xs")),
+						bass.NewList(
+							bass.Symbol("unquote-splicing"),
+							bass.NewList(bass.Symbol("c"), bass.Int(2), bass.Int(3)),
+						),
+					),
+				),
+			),
+		},
+		{
+			Name:   "quasiquote nested eval",
+			Bass:   "(def x 6) (def c 7) (eval `(eval `(* ~c ~~x) (current-scope)) (current-scope))",
+			Result: bass.Int(42),
+		},
+		{
+			Name:        "quasiquote unquote-splicing non-list",
+			Bass:        "`(1 ~@2)",
+			ErrContains: "unquote-splicing",
+		},
 		{
 			Name:  "op",
 			Scope: scope,
@@ -807,6 +963,123 @@ func TestGroundConstructors(t *testing.T) {
 				bass.Symbol("since"), bass.Bindings{"day": bass.Int(1)}.Scope(),
 			),
 		},
+		{
+			Name:   "try success",
+			Bass:   `(try (+ 1 2) (fn [_] :nope))`,
+			Result: bass.Int(3),
+		},
+		{
+			Name:   "try error message",
+			Bass:   `(try (error "oh no!") (fn [err] (:message err)))`,
+			Result: bass.String("oh no!"),
+		},
+		{
+			Name:   "try error fields",
+			Bass:   `(try (error "oh no!" :exit-code 2) (fn [err] (:exit-code err)))`,
+			Result: bass.Int(2),
+		},
+		{
+			Name:   "try go error",
+			Bass:   `(try (next (list->source [])) (fn [err] (:message err)))`,
+			Result: bass.String("end of source"),
+		},
+		{
+			Name:   "try handler not evaluated",
+			Bass:   `(try 42 (error "evaluated"))`,
+			Result: bass.Int(42),
+		},
+		{
+			Name:     "try re-raise",
+			Bass:     `(try (error "oh no!") (fn [err] ((:error err))))`,
+			ErrEqual: bass.NewError("oh no!"),
+		},
+		{
+			Name:     "try handler error",
+			Bass:     `(try (error "oh no!") (fn [_] (error "still no!")))`,
+			ErrEqual: bass.NewError("still no!"),
+		},
+		{
+			Name:   "timeout success",
+			Bass:   `(timeout 1 (+ 1 2))`,
+			Result: bass.Int(3),
+		},
+		{
+			Name: "timeout blocked next",
+			Bind: bass.Bindings{
+				"blocked": blockingSource(),
+			},
+			Bass:     `(timeout 0.01 (next blocked))`,
+			ErrEqual: bass.TimeoutError{Timeout: 10 * time.Millisecond},
+		},
+		{
+			Name: "timeout try",
+			Bind: bass.Bindings{
+				"blocked": blockingSource(),
+			},
+			Bass:   `(try (timeout 0.01 (next blocked)) (fn [err] (:message err)))`,
+			Result: bass.String("timed out after 10ms"),
+		},
+		{
+			Name:     "timeout error",
+			Bass:     `(timeout 1 (error "oh no!"))`,
+			ErrEqual: bass.NewError("oh no!"),
+		},
+		{
+			Name: "timeout cancels on return",
+			Bind: bass.Bindings{
+				"capture": bass.Func("capture", "[]", func(ctx context.Context) bass.Value {
+					return bass.Func("canceled?", "[]", func() bool {
+						return ctx.Err() != nil
+					})
+				}),
+			},
+			Bass:   `((timeout 1 (capture)))`,
+			Result: bass.Bool(true),
+		},
+		{
+			Name: "pmap concurrent",
+			Bind: bass.Bindings{
+				"barrier": barrierFunc(3),
+			},
+			Bass: "(pmap (fn [x] (barrier) x) [1 2 3] 3)",
+			Result: bass.NewList(
+				bass.Int(1),
+				bass.Int(2),
+				bass.Int(3),
+			),
+		},
+		{
+			Name: "pmap cancels siblings",
+			Bind: bass.Bindings{
+				"hang": bass.Func("hang", "[]", func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}),
+			},
+			Bass:     `(pmap (fn [x] (if (= x 2) (error "oh no!") (hang))) [1 2 3] 3)`,
+			ErrEqual: bass.NewError("oh no!"),
+		},
+		{
+			Name: "retry success",
+			Bind: bass.Bindings{
+				"flaky": flakyFunc(2),
+			},
+			Bass:   `(retry 3 0 (flaky))`,
+			Result: bass.Int(3),
+		},
+		{
+			Name: "retry exhausted",
+			Bind: bass.Bindings{
+				"flaky": flakyFunc(3),
+			},
+			Bass:     `(retry 3 0.001 (flaky))`,
+			ErrEqual: bass.NewError("attempt 3 failed"),
+		},
+		{
+			Name:        "retry no attempts",
+			Bass:        `(retry 0 0 :ok)`,
+			ErrContains: "attempts must be at least 1",
+		},
 		{
 			Name:   "now minute",
 			Bass:   `(now 60)`,
@@ -1446,6 +1719,34 @@ func TestGroundStdlib(t *testing.T) {
 				bass.Int(6),
 			),
 		},
+		{
+			Name: "pmap",
+			Bass: "(pmap (fn [x] (* x 2)) [1 2 3])",
+			Result: bass.NewList(
+				bass.Int(2),
+				bass.Int(4),
+				bass.Int(6),
+			),
+		},
+		{
+			Name: "pmap limit",
+			Bass: "(pmap (fn [x] (* x 2)) [1 2 3] 1)",
+			Result: bass.NewList(
+				bass.Int(2),
+				bass.Int(4),
+				bass.Int(6),
+			),
+		},
+		{
+			Name:   "pmap empty",
+			Bass:   "(pmap (fn [x] (* x 2)) [])",
+			Result: bass.Empty{},
+		},
+		{
+			Name:        "pmap bad limit",
+			Bass:        "(pmap (fn [x] x) [1 2 3] 0)",
+			ErrContains: "limit must be at least 1",
+		},
 		{
 			Name:   "cond first",
 			Bass:   "(cond true 1 unevaluated unevaluated)",
@@ -1757,6 +2058,16 @@ func TestGroundStrings(t *testing.T) {
 			Bass:   `(substring "abcde" 1 3)`,
 			Result: bass.String("bc"),
 		},
+		{
+			Name:   "substring unicode",
+			Bass:   `(substring "héllo✨" 1 6)`,
+			Result: bass.String("éllo✨"),
+		},
+		{
+			Name:        "substring out of range",
+			Bass:        `(substring "abc" 1 4)`,
+			ErrContains: "out of range",
+		},
 		{
 			Name: "substring extra arg",
 			Bass: `(substring "abcde" 1 3 5)`,
@@ -1776,6 +2087,26 @@ func TestGroundStrings(t *testing.T) {
 			Bass:   `(json {:a 1 :b true :multi-word "hello world!\n"})`,
 			Result: bass.String(`{"a":1,"b":true,"multi-word":"hello world!\n"}`),
 		},
+		{
+			Name:   "yaml",
+			Bass:   `(yaml {:a 1 :b [true 2.5] :multi-word "hello"})`,
+			Result: bass.String("a: 1\nb:\n    - true\n    - 2.5\nmulti-word: hello\n"),
+		},
+		{
+			Name:   "yaml round trip",
+			Bass:   `(next (read (mkfile ./config.yml (yaml {:a 1 :b {:c "d"}})) :yaml))`,
+			Result: bass.Bindings{"a": bass.Int(1), "b": bass.Bindings{"c": bass.String("d")}.Scope()}.Scope(),
+		},
+		{
+			Name:   "toml",
+			Bass:   `(toml {:name "bass" :deps {:go "1.20"}})`,
+			Result: bass.String("name = 'bass'\n\n[deps]\ngo = '1.20'\n"),
+		},
+		{
+			Name:   "toml round trip",
+			Bass:   `(next (read (mkfile ./config.toml (toml {:n 1 :xs [1 2]})) :toml))`,
+			Result: bass.Bindings{"n": bass.Int(1), "xs": bass.NewList(bass.Int(1), bass.Int(2))}.Scope(),
+		},
 		{
 			Name:   "join",
 			Bass:   `(use (.strings)) (strings:join ", " ["hello" "world"])`,
@@ -1806,6 +2137,183 @@ func TestGroundStrings(t *testing.T) {
 			Bass:   `(use (.strings)) (strings:length "hello")`,
 			Result: bass.Int(5),
 		},
+		{
+			Name:   "length unicode",
+			Bass:   `(use (.strings)) (strings:length "héllo✨")`,
+			Result: bass.Int(6),
+		},
+		{
+			Name:   "lower-case",
+			Bass:   `(use (.strings)) (strings:lower-case "HALLELUJAH")`,
+			Result: bass.String("hallelujah"),
+		},
+		{
+			Name:   "starts-with? yes",
+			Bass:   `(use (.strings)) (strings:starts-with? "v1.2.3" "v")`,
+			Result: bass.Bool(true),
+		},
+		{
+			Name:   "starts-with? no",
+			Bass:   `(use (.strings)) (strings:starts-with? "1.2.3" "v")`,
+			Result: bass.Bool(false),
+		},
+		{
+			Name:   "ends-with? yes",
+			Bass:   `(use (.strings)) (strings:ends-with? "image.tar" ".tar")`,
+			Result: bass.Bool(true),
+		},
+		{
+			Name:   "ends-with? no",
+			Bass:   `(use (.strings)) (strings:ends-with? "image.tgz" ".tar")`,
+			Result: bass.Bool(false),
+		},
+		{
+			Name:   "trim-prefix",
+			Bass:   `(use (.strings)) [(strings:trim-prefix "v1.2.3" "v") (strings:trim-prefix "1.2.3" "v")]`,
+			Result: bass.NewList(bass.String("1.2.3"), bass.String("1.2.3")),
+		},
+		{
+			Name:   "trim-suffix",
+			Bass:   `(use (.strings)) (strings:trim-suffix "image.tar" ".tar")`,
+			Result: bass.String("image"),
+		},
+		{
+			Name:   "replace",
+			Bass:   `(use (.strings)) (strings:replace "foo/bar/baz" "/" "-")`,
+			Result: bass.String("foo-bar-baz"),
+		},
+		{
+			Name:   "index-of",
+			Bass:   `(use (.strings)) [(strings:index-of "héllo" "l") (strings:index-of "hello" "x")]`,
+			Result: bass.NewList(bass.Int(2), bass.Int(-1)),
+		},
+		{
+			Name:   "repeat",
+			Bass:   `(use (.strings)) (strings:repeat "ab" 3)`,
+			Result: bass.String("ababab"),
+		},
+		{
+			Name:        "repeat negative",
+			Bass:        `(use (.strings)) (strings:repeat "ab" -1)`,
+			ErrContains: "negative repeat count",
+		},
+		{
+			Name:        "repeat too large",
+			Bass:        `(use (.strings)) (strings:repeat "ab" 100000000)`,
+			ErrContains: "string-repeat: result would exceed",
+		},
+		{
+			Name:   "pad-left",
+			Bass:   `(use (.strings)) [(strings:pad-left "42" 5 "0") (strings:pad-left "42" 4) (strings:pad-left "12345" 3)]`,
+			Result: bass.NewList(bass.String("00042"), bass.String("  42"), bass.String("12345")),
+		},
+		{
+			Name:   "pad-right",
+			Bass:   `(use (.strings)) [(strings:pad-right "ab" 5 "xy") (strings:pad-right "é" 3)]`,
+			Result: bass.NewList(bass.String("abxyx"), bass.String("é  ")),
+		},
+		{
+			Name:        "pad-left too large",
+			Bass:        `(use (.strings)) (strings:pad-left "42" 100000000)`,
+			ErrContains: "string-pad-left: padding would exceed",
+		},
+		{
+			Name:        "pad-right empty",
+			Bass:        `(use (.strings)) (strings:pad-right "42" 5 "")`,
+			ErrContains: "string-pad-right: empty padding",
+		},
+		{
+			Name: "lines",
+			Bass: `(use (.strings)) (strings:lines "one\ntwo\r\nthree\n")`,
+			Result: bass.NewList(
+				bass.String("one"),
+				bass.String("two"),
+				bass.String("three"),
+			),
+		},
+		{
+			Name:   "lines empty",
+			Bass:   `(use (.strings)) (strings:lines "")`,
+			Result: bass.Empty{},
+		},
+		{
+			Name:   "format",
+			Bass:   `(use (.strings)) (strings:format "%s:%d %.1f %v %s" "localhost" 6379 1.25 true [1 2])`,
+			Result: bass.String("localhost:6379 1.2 true (1 2)"),
+		},
+		{
+			Name:        "format missing arg",
+			Bass:        `(use (.strings)) (strings:format "%s:%d" "localhost")`,
+			ErrContains: `bad format "%s:%d" for 1 arguments: localhost:%!d(MISSING)`,
+		},
+		{
+			Name:        "format extra arg",
+			Bass:        `(use (.strings)) (strings:format "%s" "a" "b")`,
+			ErrContains: `%!(EXTRA string=b)`,
+		},
+		{
+			Name:        "format bad verb",
+			Bass:        `(use (.strings)) (strings:format "%d" "a")`,
+			ErrContains: `%!d(string=a)`,
+		},
+		{
+			Name: "regexp match",
+			Bass: `(use (.regexp)) (regexp:match "bass v1.2.3" "v(\\d+)\\.(\\d+)")`,
+			Result: bass.NewList(
+				bass.String("v1.2"),
+				bass.String("1"),
+				bass.String("2"),
+			),
+		},
+		{
+			Name: "regexp match named",
+			Bass: `(use (.regexp)) (regexp:match "bass v1.2.3" "v(?P<major>\\d+)\\.(?P<minor>\\d+)")`,
+			Result: bass.Bindings{
+				"major": bass.String("1"),
+				"minor": bass.String("2"),
+			}.Scope(),
+		},
+		{
+			Name:   "regexp match none",
+			Bass:   `(use (.regexp)) (regexp:match "bass" "\\d+")`,
+			Result: bass.Null{},
+		},
+		{
+			Name: "regexp find-all",
+			Bass: `(use (.regexp)) (regexp:find-all "a=1 b=2" "(\\w)=(\\d)")`,
+			Result: bass.NewList(
+				bass.NewList(bass.String("a=1"), bass.String("a"), bass.String("1")),
+				bass.NewList(bass.String("b=2"), bass.String("b"), bass.String("2")),
+			),
+		},
+		{
+			Name: "regexp find-all named",
+			Bass: `(use (.regexp)) (regexp:find-all "a=1 b=2" "(?P<key>\\w)=(?P<val>\\d)")`,
+			Result: bass.NewList(
+				bass.Bindings{"key": bass.String("a"), "val": bass.String("1")}.Scope(),
+				bass.Bindings{"key": bass.String("b"), "val": bass.String("2")}.Scope(),
+			),
+		},
+		{
+			Name:   "regexp find-all none",
+			Bass:   `(use (.regexp)) (regexp:find-all "abc" "\\d")`,
+			Result: bass.Empty{},
+		},
+		{
+			Name:   "regexp replace",
+			Bass:   `(use (.regexp)) (regexp:replace "hello world" "(\\w+) (?P<second>\\w+)" "${second} $1")`,
+			Result: bass.String("world hello"),
+		},
+		{
+			Name:   "regexp split",
+			Bass:   `(use (.regexp)) (regexp:split "a, b,c" ",\\s*")`,
+			Result: bass.NewList(bass.String("a"), bass.String("b"), bass.String("c")),
+		},
+		{
+			Name:        "regexp invalid",
+			Bass:        `(use (.regexp)) (regexp:match "abc" "(")`,
+			ErrContains: "missing closing )",
+		},
 	} {
 		t.Run(example.Name, example.Run)
 	}
@@ -2156,6 +2664,27 @@ func TestGroundDebug(t *testing.T) {
 	}
 }
 
+func TestGroundTryTrace(t *testing.T) {
+	is := is.New(t)
+
+	trace := &bass.Trace{}
+	ctx := bass.WithTrace(context.Background(), trace)
+
+	res, err := bass.EvalString(ctx, bass.NewStandardScope(), `
+(defn fail [] (error "oh no!"))
+(defn forms [err] (map (fn [frame] (:form frame)) (:trace err)))
+(defn caught [] (try (fail) forms))
+(caught)
+`, bass.NewInMemoryFile("test", ""))
+	is.NoErr(err)
+
+	// frames leading up to the (try) are left out
+	basstest.Equal(t, res, bass.NewList(
+		bass.NewList(bass.Symbol("fail")),
+		bass.NewList(bass.Symbol("error"), bass.String("oh no!")),
+	))
+}
+
 func TestGroundCase(t *testing.T) {
 	for _, example := range []BasicExample{
 		{
@@ -2312,6 +2841,118 @@ func TestGroundThunks(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "with-timeout",
+			Bass: `(with-timeout ($ sleep 10) 1.5)`,
+			Result: bass.Thunk{
+				Args: []bass.Value{
+					bass.String("sleep"),
+					bass.Int(10),
+				},
+				Timeout: 1500 * time.Millisecond,
+			},
+		},
+		{
+			Name: "with-retry",
+			Bass: `(with-retry ($ curl "https://example.com") 3 0.5)`,
+			Result: bass.Thunk{
+				Args: []bass.Value{
+					bass.String("curl"),
+					bass.String("https://example.com"),
+				},
+				Retry: &bass.ThunkRetry{
+					Attempts: 3,
+					Backoff:  500 * time.Millisecond,
+				},
+			},
+		},
+		{
+			Name: "with-cpu with-memory with-pids-limit",
+			Bass: `(-> ($ make) (with-cpu 0.5) (with-memory 1024) (with-pids-limit 100))`,
+			Result: bass.Thunk{
+				Args: []bass.Value{
+					bass.String("make"),
+				},
+				Resources: &bass.ThunkResources{
+					CPU:    0.5,
+					Memory: 1024,
+					Pids:   100,
+				},
+			},
+		},
+		{
+			Name:        "with-cpu invalid",
+			Bass:        `(with-cpu ($ make) 0)`,
+			ErrContains: "cpu limit must be positive",
+		},
+		{
+			Name:        "with-memory invalid",
+			Bass:        `(with-memory ($ make) -1)`,
+			ErrContains: "memory limit must be positive",
+		},
+		{
+			Name: "with-network",
+			Bass: `(with-network ($ make) :none)`,
+			Result: bass.Thunk{
+				Args: []bass.Value{
+					bass.String("make"),
+				},
+				Network: bass.NetworkModeNone,
+			},
+		},
+		{
+			Name: "with-user",
+			Bass: `(with-user ($ id) "1000:1000")`,
+			Result: bass.Thunk{
+				Args: []bass.Value{
+					bass.String("id"),
+				},
+				User: "1000:1000",
+			},
+		},
+		{
+			Name: "with image config",
+			Bass: `(-> ($ serve)
+			           (with-workdir "/app")
+			           (with-annotation :org.opencontainers.image.source "https://example.com")
+			           (with-image-label :org.opencontainers.image.version "1.0")
+			           (with-stop-signal "SIGINT")
+			           (with-healthcheck ["check" "--quick"] {:interval 30 :start-period 1.5 :retries 3})
+			           (with-volume "/data")
+			           (with-volume "/cache"))`,
+			Result: bass.Thunk{
+				Args: []bass.Value{
+					bass.String("serve"),
+				},
+				ImageConfig: &bass.ThunkImageConfig{
+					WorkingDir: "/app",
+					Annotations: bass.Bindings{
+						"org.opencontainers.image.source": bass.String("https://example.com"),
+					}.Scope(),
+					Labels: bass.Bindings{
+						"org.opencontainers.image.version": bass.String("1.0"),
+					}.Scope(),
+					StopSignal: "SIGINT",
+					Healthcheck: &bass.ThunkHealthcheck{
+						Command:     []string{"check", "--quick"},
+						Interval:    30 * time.Second,
+						StartPeriod: 1500 * time.Millisecond,
+						Retries:     3,
+					},
+					Volumes: []string{"/data", "/cache"},
+				},
+			},
+		},
+		{
+			Name:        "with-healthcheck empty",
+			Bass:        `(with-healthcheck ($ serve) [])`,
+			ErrContains: "healthcheck command must not be empty",
+		},
+		{
+			Name:        "with-network invalid",
+			Bass:        `(with-network ($ make) :bridge)`,
+			ErrContains: "invalid network mode: bridge",
+		},
 		{
 			Name: "thunk-args",
 			Bass: `(thunk-args ($ foo abc))`,
@@ -2403,3 +3044,53 @@ func TestGroundThunks(t *testing.T) {
 		t.Run(example.Name, example.Run)
 	}
 }
+
+// blockingSource returns a source which blocks reading until it is closed.
+func blockingSource() *bass.Source {
+	r, _ := io.Pipe()
+
+	src, err := bass.LineProtocol{}.DecodeStream(context.Background(), r)
+	if err != nil {
+		panic(err)
+	}
+
+	return &bass.Source{src}
+}
+
+// flakyFunc returns a function which fails the given number of times before
+// returning the number of the attempt that succeeded.
+func flakyFunc(failures int) bass.Applicative {
+	attempt := 0
+	return bass.Func("flaky", "[]", func() (int, error) {
+		attempt++
+		if attempt <= failures {
+			return 0, bass.NewError(fmt.Sprintf("attempt %d failed", attempt))
+		}
+
+		return attempt, nil
+	})
+}
+
+// barrierFunc returns a function which blocks until it has been called
+// concurrently the given number of times.
+func barrierFunc(n int) bass.Applicative {
+	wg := new(sync.WaitGroup)
+	wg.Add(n)
+
+	return bass.Func("barrier", "[]", func() error {
+		wg.Done()
+
+		waited := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(waited)
+		}()
+
+		select {
+		case <-waited:
+			return nil
+		case <-time.After(10 * time.Second):
+			return fmt.Errorf("timed out waiting for %d concurrent calls", n)
+		}
+	})
+}
@@ -1,11 +1,13 @@
 package bass
 
 import (
+	"archive/tar"
 	"context"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -47,6 +49,7 @@ func ParseHostPath(path string) HostPath {
 type Filesystem interface {
 	FS(root string) (fs.FS, error)
 	Write(path string, r io.Reader) error
+	WriteTar(dir string, r io.Reader) error
 }
 
 // The filesystem for host paths. Re-assign it to DiscardFilesystem to disable
@@ -84,6 +87,63 @@ func (HostFilesystem) Write(path string, r io.Reader) error {
 	return os.Rename(atomic, path)
 }
 
+// WriteTar unpacks a tar stream into the directory, creating it if needed.
+//
+// Only directories and regular files are supported.
+func (HostFilesystem) WriteTar(dir string, r io.Reader) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		// NB: clean as an absolute path so entries can't escape the dir
+		dest := filepath.Join(dir, filepath.FromSlash(path.Clean("/"+hdr.Name)))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(dest, hdr.FileInfo().Mode().Perm())
+		case tar.TypeReg:
+			err = writeTarFile(dest, hdr.FileInfo().Mode().Perm(), tr)
+		default:
+			err = fmt.Errorf("unsupported entry type %q", hdr.Typeflag)
+		}
+		if err != nil {
+			return fmt.Errorf("unpack %s: %w", hdr.Name, err)
+		}
+	}
+}
+
+func writeTarFile(dest string, perm fs.FileMode, r io.Reader) error {
+	err := os.MkdirAll(filepath.Dir(dest), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
 type DiscardFilesystem struct{}
 
 var _ Filesystem = DiscardFilesystem{}
@@ -92,6 +152,8 @@ func (DiscardFilesystem) FS(root string) (fs.FS, error) { return os.DirFS(root),
 
 func (DiscardFilesystem) Write(path string, r io.Reader) error { return nil }
 
+func (DiscardFilesystem) WriteTar(dir string, r io.Reader) error { return nil }
+
 func (value HostPath) String() string {
 	return fmt.Sprintf("<host: %s>/%s", value.ContextDir, strings.TrimPrefix(value.Path.String(), "./"))
 }
@@ -268,6 +330,16 @@ func (path HostPath) Write(ctx context.Context, src io.Reader) error {
 	return FS.Write(abs, src)
 }
 
+// WriteTar unpacks a tar stream into the directory at the path.
+func (path HostPath) WriteTar(ctx context.Context, src io.Reader) error {
+	abs, _, err := path.checkEscape()
+	if err != nil {
+		return err
+	}
+
+	return FS.WriteTar(abs, src)
+}
+
 func (value HostPath) Dir() HostPath {
 	cp := value
 
@@ -30,6 +30,9 @@ func (value Int) Decode(dest any) error {
 	case *int:
 		*x = int(value)
 		return nil
+	case *float64:
+		*x = float64(value)
+		return nil
 	default:
 		return DecodeError{
 			Source:      value,
@@ -1,11 +1,14 @@
 package bass
 
 import (
+	"container/list"
 	"context"
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/vito/bass/pkg/zapctx"
 )
@@ -22,11 +25,103 @@ func init() {
 	Internal.Set("string-split",
 		Func("string-split", "[delim str]", strings.Split))
 
-	Internal.Set("string-length",
-		Func("string-length", "[str]", func(s string) int {
-			return len(s)
+	Internal.Set("string-lower-case",
+		Func("string-lower-case", "[str]", strings.ToLower))
+
+	Internal.Set("string-starts-with",
+		Func("string-starts-with", "[str prefix]", strings.HasPrefix))
+
+	Internal.Set("string-ends-with",
+		Func("string-ends-with", "[str suffix]", strings.HasSuffix))
+
+	Internal.Set("string-trim-prefix",
+		Func("string-trim-prefix", "[str prefix]", strings.TrimPrefix))
+
+	Internal.Set("string-trim-suffix",
+		Func("string-trim-suffix", "[str suffix]", strings.TrimSuffix))
+
+	Internal.Set("string-replace",
+		Func("string-replace", "[str old new]", strings.ReplaceAll))
+
+	Internal.Set("string-index-of",
+		Func("string-index-of", "[str substr]", func(s, substr string) int {
+			idx := strings.Index(s, substr)
+			if idx == -1 {
+				return -1
+			}
+
+			return utf8.RuneCountInString(s[:idx])
+		}))
+
+	Internal.Set("string-repeat",
+		Func("string-repeat", "[str count]", func(s string, count int) (string, error) {
+			if count < 0 {
+				return "", fmt.Errorf("negative repeat count: %d", count)
+			}
+
+			if count > 0 && len(s) > maxStringSize/count {
+				return "", fmt.Errorf("string-repeat: result would exceed %d bytes", maxStringSize)
+			}
+
+			return strings.Repeat(s, count), nil
+		}))
+
+	Internal.Set("string-pad-left",
+		Func("string-pad-left", "[str width & pad]", func(s string, width int, pad ...string) (string, error) {
+			padding, err := stringPadding("string-pad-left", s, width, pad...)
+			if err != nil {
+				return "", err
+			}
+
+			return padding + s, nil
+		}))
+
+	Internal.Set("string-pad-right",
+		Func("string-pad-right", "[str width & pad]", func(s string, width int, pad ...string) (string, error) {
+			padding, err := stringPadding("string-pad-right", s, width, pad...)
+			if err != nil {
+				return "", err
+			}
+
+			return s + padding, nil
+		}))
+
+	Internal.Set("string-lines",
+		Func("string-lines", "[str]", func(s string) []string {
+			s = strings.TrimSuffix(s, "\n")
+			if s == "" {
+				return []string{}
+			}
+
+			lines := strings.Split(s, "\n")
+			for i, line := range lines {
+				lines[i] = strings.TrimSuffix(line, "\r")
+			}
+
+			return lines
+		}))
+
+	Internal.Set("string-format",
+		Func("string-format", "[fmt & args]", func(format string, args ...Value) (string, error) {
+			vals := make([]any, len(args))
+			for i, arg := range args {
+				vals[i] = formatArg(arg)
+			}
+
+			str := fmt.Sprintf(format, vals...)
+
+			// fmt reports bad verbs and missing or extra arguments inline, e.g.
+			// %!d(string=hi) or %!(EXTRA int=1)
+			if strings.Contains(str, "%!") {
+				return "", fmt.Errorf("string-format: bad format %q for %d arguments: %s", format, len(args), str)
+			}
+
+			return str, nil
 		}))
 
+	Internal.Set("string-length",
+		Func("string-length", "[str]", utf8.RuneCountInString))
+
 	Internal.Set("time-measure",
 		Op("time-measure", "[form]", func(ctx context.Context, cont Cont, scope *Scope, form Value) ReadyCont {
 			before := Clock.Now()
@@ -37,6 +132,56 @@ func init() {
 			}))
 		}))
 
+	Internal.Set("regexp-match",
+		Func("regexp-match", "[str re]", func(str, pattern string) (Value, error) {
+			re, err := compileRegexp(pattern)
+			if err != nil {
+				return nil, err
+			}
+
+			matches := re.FindStringSubmatch(str)
+			if matches == nil {
+				return Null{}, nil
+			}
+
+			return regexpMatch(re, matches), nil
+		}))
+
+	Internal.Set("regexp-find-all",
+		Func("regexp-find-all", "[str re]", func(str, pattern string) (List, error) {
+			re, err := compileRegexp(pattern)
+			if err != nil {
+				return nil, err
+			}
+
+			var vals []Value
+			for _, matches := range re.FindAllStringSubmatch(str, -1) {
+				vals = append(vals, regexpMatch(re, matches))
+			}
+
+			return NewList(vals...), nil
+		}))
+
+	Internal.Set("regexp-replace",
+		Func("regexp-replace", "[str re replacement]", func(str, pattern, repl string) (string, error) {
+			re, err := compileRegexp(pattern)
+			if err != nil {
+				return "", err
+			}
+
+			return re.ReplaceAllString(str, repl), nil
+		}))
+
+	Internal.Set("regexp-split",
+		Func("regexp-split", "[str re]", func(str, pattern string) ([]string, error) {
+			re, err := compileRegexp(pattern)
+			if err != nil {
+				return nil, err
+			}
+
+			return re.Split(str, -1), nil
+		}))
+
 	Internal.Set("regexp-case",
 		Op("regexp-case", "[str & re-fn-pairs]", func(ctx context.Context, cont Cont, scope *Scope, haystackForm Value, pairs ...Value) ReadyCont {
 			if len(pairs)%2 == 1 {
@@ -91,3 +236,142 @@ func init() {
 			}))
 		}))
 }
+
+// maxStringSize bounds the size of strings built by repeating another, so
+// that a typo'd count doesn't exhaust memory.
+const maxStringSize = 64 * 1024 * 1024
+
+// stringPadding returns the padding needed to bring str up to width
+// characters, repeating pad (a space by default) and truncating it to fit.
+func stringPadding(name string, str string, width int, pad ...string) (string, error) {
+	var p string
+	switch len(pad) {
+	case 0:
+		p = " "
+	case 1:
+		p = pad[0]
+	default:
+		return "", ArityError{
+			Name: name,
+			Need: 3,
+			Have: 2 + len(pad),
+		}
+	}
+
+	if p == "" {
+		return "", fmt.Errorf("%s: empty padding", name)
+	}
+
+	missing := width - utf8.RuneCountInString(str)
+	if missing <= 0 {
+		return "", nil
+	}
+
+	if missing > maxStringSize {
+		return "", fmt.Errorf("%s: padding would exceed %d characters", name, maxStringSize)
+	}
+
+	padRunes := []rune(p)
+	copies := (missing + len(padRunes) - 1) / len(padRunes)
+	if len(p) > maxStringSize/copies {
+		return "", fmt.Errorf("%s: padding would exceed %d bytes", name, maxStringSize)
+	}
+
+	padding := []rune(strings.Repeat(p, copies))
+	return string(padding[:missing]), nil
+}
+
+// formatArg converts a value to its Go equivalent so that it works with
+// printf verbs, falling back to the value itself for %s and %v.
+func formatArg(val Value) any {
+	var s string
+	if err := val.Decode(&s); err == nil {
+		return s
+	}
+
+	var i int
+	if err := val.Decode(&i); err == nil {
+		return i
+	}
+
+	var f float64
+	if err := val.Decode(&f); err == nil {
+		return f
+	}
+
+	var b bool
+	if err := val.Decode(&b); err == nil {
+		return b
+	}
+
+	return val
+}
+
+// maxRegexps is the number of compiled regexps kept by compileRegexp.
+const maxRegexps = 256
+
+var regexps = map[string]*list.Element{}
+var regexpsLRU = list.New()
+var regexpsL = new(sync.Mutex)
+
+// compileRegexp compiles the pattern, caching the result so that patterns
+// used in a loop are only compiled once.
+//
+// Only the most recently used patterns are kept, since they may be built at
+// runtime.
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	regexpsL.Lock()
+	defer regexpsL.Unlock()
+
+	if elem, found := regexps[pattern]; found {
+		regexpsLRU.MoveToFront(elem)
+		return elem.Value.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	regexps[pattern] = regexpsLRU.PushFront(re)
+
+	if regexpsLRU.Len() > maxRegexps {
+		oldest := regexpsLRU.Back()
+		regexpsLRU.Remove(oldest)
+		delete(regexps, oldest.Value.(*regexp.Regexp).String())
+	}
+
+	return re, nil
+}
+
+// regexpMatch converts a match into a list of the full match followed by
+// each capture group, or a scope if the regexp has any named groups.
+func regexpMatch(re *regexp.Regexp, matches []string) Value {
+	names := re.SubexpNames()
+
+	named := false
+	for _, name := range names {
+		if name != "" {
+			named = true
+			break
+		}
+	}
+
+	if !named {
+		vals := make([]Value, len(matches))
+		for i, m := range matches {
+			vals[i] = String(m)
+		}
+
+		return NewList(vals...)
+	}
+
+	scope := NewEmptyScope()
+	for i, m := range matches {
+		if names[i] != "" {
+			scope.Set(Symbol(names[i]), String(m))
+		}
+	}
+
+	return scope
+}
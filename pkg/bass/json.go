@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 )
 
 func NewDecoder(r io.Reader) *Decoder {
@@ -66,14 +67,7 @@ func decodeValue(dec *json.Decoder) (Value, error) {
 		return String(x), nil
 
 	case json.Number:
-		i, err := x.Int64()
-		if err != nil {
-			// TODO: make sure there's a test or justification for this, just
-			// matching current impl for now
-			return String(x.String()), nil
-		} else {
-			return Int(i), nil
-		}
+		return numberValue(x), nil
 
 	case json.Delim:
 		switch x {
@@ -139,3 +133,22 @@ func decodeValue(dec *json.Decoder) (Value, error) {
 		return nil, fmt.Errorf("impossible: unknown delimiter: %s", x)
 	}
 }
+
+// numberValue converts a JSON number into an Int if it is an integer, or a
+// Float if it has a fraction or exponent.
+//
+// Integers which do not fit in an Int are returned as a String so that they
+// are not silently rounded.
+func numberValue(num json.Number) Value {
+	if i, err := num.Int64(); err == nil {
+		return Int(i)
+	}
+
+	if strings.ContainsAny(num.String(), ".eE") {
+		if f, err := num.Float64(); err == nil {
+			return Float(f)
+		}
+	}
+
+	return String(num.String())
+}
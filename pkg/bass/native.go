@@ -0,0 +1,121 @@
+package bass
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ToNative converts a value into plain Go maps, slices, and scalars by way of
+// its JSON encoding, for passing to encoders for other formats.
+//
+// Integers are converted to int64 and all other numbers to float64.
+func ToNative(val Value) (any, error) {
+	payload, err := MarshalJSON(val)
+	if err != nil {
+		return nil, err
+	}
+
+	var dest any
+	err = NewRawDecoder(bytes.NewBuffer(payload)).Decode(&dest)
+	if err != nil {
+		return nil, err
+	}
+
+	return toNativeNumbers(dest), nil
+}
+
+func toNativeNumbers(val any) any {
+	switch x := val.(type) {
+	case map[string]any:
+		for k, v := range x {
+			x[k] = toNativeNumbers(v)
+		}
+
+		return x
+	case []any:
+		for i, v := range x {
+			x[i] = toNativeNumbers(v)
+		}
+
+		return x
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+
+		f, err := x.Float64()
+		if err != nil {
+			// not representable; leave it as a string
+			return x.String()
+		}
+
+		return f
+	default:
+		return val
+	}
+}
+
+// FromNative converts a value decoded by a non-JSON decoder, e.g. YAML or
+// TOML, into a Value.
+//
+// Timestamps are converted to RFC3339 strings, as with JSON.
+func FromNative(src any) (Value, error) {
+	switch x := src.(type) {
+	case Value:
+		return x, nil
+	case int64:
+		return Int(x), nil
+	case uint64:
+		if x > math.MaxInt64 {
+			return Float(x), nil
+		}
+
+		return Int(x), nil
+	case time.Time:
+		return String(x.Format(time.RFC3339Nano)), nil
+	case map[string]any:
+		scope := NewEmptyScope()
+		for k, v := range x {
+			val, err := FromNative(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+
+			scope.Set(SymbolFromJSONKey(k), val)
+		}
+
+		return scope, nil
+	case map[any]any:
+		scope := NewEmptyScope()
+		for k, v := range x {
+			val, err := FromNative(v)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", k, err)
+			}
+
+			scope.Set(SymbolFromJSONKey(fmt.Sprint(k)), val)
+		}
+
+		return scope, nil
+	case []any:
+		vals := make([]Value, len(x))
+		for i, v := range x {
+			val, err := FromNative(v)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+
+			vals[i] = val
+		}
+
+		return NewList(vals...), nil
+	case fmt.Stringer:
+		// e.g. TOML local dates and times
+		return String(x.String()), nil
+	default:
+		return ValueOf(src)
+	}
+}
@@ -38,6 +38,8 @@ func FromProto(val *proto.Value) (Value, error) {
 		return Bool(x.Bool.Value), nil
 	case *proto.Value_Int:
 		return Int(x.Int.Value), nil
+	case *proto.Value_Float:
+		return Float(x.Float.Value), nil
 	case *proto.Value_String_:
 		return String(x.String_.Value), nil
 	case *proto.Value_Secret:
@@ -144,6 +146,10 @@ func (value Int) MarshalProto() (proto.Message, error) {
 	return &proto.Int{Value: int64(value)}, nil
 }
 
+func (value Float) MarshalProto() (proto.Message, error) {
+	return &proto.Float{Value: float64(value)}, nil
+}
+
 func (value String) MarshalProto() (proto.Message, error) {
 	return &proto.String{Value: string(value)}, nil
 }
@@ -357,6 +363,24 @@ func (value Thunk) MarshalProto() (proto.Message, error) {
 	pThunk.DefaultArgs = value.DefaultArgs
 	pThunk.ClearDefaultArgs = value.ClearDefaultArgs
 	pThunk.UseEntrypoint = value.UseEntrypoint
+	pThunk.TimeoutNs = int64(value.Timeout)
+	pThunk.Network = proto.NetworkMode(value.Network)
+	pThunk.User = value.User
+
+	if value.Resources != nil {
+		pThunk.Resources = &proto.ThunkResources{
+			Cpu:    value.Resources.CPU,
+			Memory: value.Resources.Memory,
+			Pids:   value.Resources.Pids,
+		}
+	}
+
+	if value.Retry != nil {
+		pThunk.Retry = &proto.ThunkRetry{
+			Attempts:  int32(value.Retry.Attempts),
+			BackoffNs: int64(value.Retry.Backoff),
+		}
+	}
 
 	for i, v := range value.Stdin {
 		pv, err := MarshalProto(v)
@@ -421,6 +445,15 @@ func (value Thunk) MarshalProto() (proto.Message, error) {
 		}
 	}
 
+	if value.ImageConfig != nil {
+		cfg, err := value.ImageConfig.MarshalProto()
+		if err != nil {
+			return nil, fmt.Errorf("image config: %w", err)
+		}
+
+		pThunk.ImageConfig = cfg.(*proto.ThunkImageConfig)
+	}
+
 	for _, port := range value.Ports {
 		pThunk.Ports = append(pThunk.Ports, &proto.ThunkPort{
 			Name: port.Name,
@@ -473,3 +506,59 @@ func (value CommandPath) MarshalProto() (proto.Message, error) {
 		Name: value.Command,
 	}, nil
 }
+
+func (value ThunkImageConfig) MarshalProto() (proto.Message, error) {
+	pCfg := &proto.ThunkImageConfig{
+		WorkingDir: value.WorkingDir,
+		StopSignal: value.StopSignal,
+		Volumes:    value.Volumes,
+	}
+
+	if value.Annotations != nil {
+		err := value.Annotations.Each(func(sym Symbol, val Value) error {
+			pv, err := MarshalProto(val)
+			if err != nil {
+				return fmt.Errorf("%s: %w", sym, err)
+			}
+
+			pCfg.Annotations = append(pCfg.Annotations, &proto.Binding{
+				Symbol: string(sym),
+				Value:  pv,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("annotations: %w", err)
+		}
+	}
+
+	if value.Labels != nil {
+		err := value.Labels.Each(func(sym Symbol, val Value) error {
+			pv, err := MarshalProto(val)
+			if err != nil {
+				return fmt.Errorf("%s: %w", sym, err)
+			}
+
+			pCfg.Labels = append(pCfg.Labels, &proto.Binding{
+				Symbol: string(sym),
+				Value:  pv,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("labels: %w", err)
+		}
+	}
+
+	if hc := value.Healthcheck; hc != nil {
+		pCfg.Healthcheck = &proto.ThunkHealthcheck{
+			Command:       hc.Command,
+			IntervalNs:    int64(hc.Interval),
+			TimeoutNs:     int64(hc.Timeout),
+			StartPeriodNs: int64(hc.StartPeriod),
+			Retries:       int32(hc.Retries),
+		}
+	}
+
+	return pCfg, nil
+}
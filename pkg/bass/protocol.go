@@ -5,12 +5,15 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/pelletier/go-toml/v2"
 	"github.com/vito/bass/pkg/internal"
+	"gopkg.in/yaml.v3"
 )
 
 // Protocol determines how response data is parsed from a thunk's response.
@@ -33,6 +36,9 @@ var Protocols = map[Symbol]Protocol{
 	"lines":      LineProtocol{},
 	"unix-table": UnixTableProtocol{},
 	"tar":        TarProtocol{},
+	"yaml":       YAMLProtocol{},
+	"toml":       TOMLProtocol{},
+	"csv":        CSVProtocol{},
 }
 
 type TarProtocol struct{}
@@ -238,3 +244,115 @@ func (src *rawSource) Next(ctx context.Context) (Value, error) {
 
 	return String(buf.String()), nil
 }
+
+// YAMLProtocol decodes a stream of YAML documents, separated by ---.
+//
+// Mappings are decoded as scopes and sequences as lists.
+type YAMLProtocol struct{}
+
+var _ Protocol = YAMLProtocol{}
+
+// DecodeStream returns a pipe source decoding from r.
+func (YAMLProtocol) DecodeStream(ctx context.Context, rc io.ReadCloser) (PipeSource, error) {
+	return yamlSource{yaml.NewDecoder(rc), rc}, nil
+}
+
+type yamlSource struct {
+	dec *yaml.Decoder
+	io.Closer
+}
+
+func (src yamlSource) String() string {
+	return "<yaml source>"
+}
+
+func (src yamlSource) Next(ctx context.Context) (Value, error) {
+	var doc any
+	err := src.dec.Decode(&doc)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEndOfSource
+		}
+
+		return nil, fmt.Errorf("next yaml document: %w", err)
+	}
+
+	return FromNative(doc)
+}
+
+// TOMLProtocol decodes the entire stream as a single TOML document, which is
+// emitted as a scope.
+type TOMLProtocol struct{}
+
+var _ Protocol = TOMLProtocol{}
+
+// DecodeStream returns a pipe source decoding from r.
+func (TOMLProtocol) DecodeStream(ctx context.Context, rc io.ReadCloser) (PipeSource, error) {
+	return &tomlSource{ReadCloser: rc}, nil
+}
+
+type tomlSource struct {
+	io.ReadCloser
+	eos bool
+}
+
+func (src *tomlSource) String() string {
+	return "<toml source>"
+}
+
+func (src *tomlSource) Next(ctx context.Context) (Value, error) {
+	if src.eos {
+		return nil, ErrEndOfSource
+	}
+
+	var doc map[string]any
+	if err := toml.NewDecoder(src).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode toml: %w", err)
+	}
+
+	src.eos = true
+
+	return FromNative(doc)
+}
+
+// CSVProtocol decodes comma-separated records.
+//
+// Each record is emitted as a list of strings, including the header, if any.
+// Records are not required to have the same number of fields.
+type CSVProtocol struct{}
+
+var _ Protocol = CSVProtocol{}
+
+// DecodeStream returns a pipe source decoding from r.
+func (CSVProtocol) DecodeStream(ctx context.Context, rc io.ReadCloser) (PipeSource, error) {
+	r := csv.NewReader(rc)
+	r.FieldsPerRecord = -1
+	return csvSource{r, rc}, nil
+}
+
+type csvSource struct {
+	r *csv.Reader
+	io.Closer
+}
+
+func (src csvSource) String() string {
+	return "<csv source>"
+}
+
+func (src csvSource) Next(ctx context.Context) (Value, error) {
+	record, err := src.r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEndOfSource
+		}
+
+		return nil, fmt.Errorf("next csv record: %w", err)
+	}
+
+	fields := make([]Value, len(record))
+	for i := range record {
+		fields[i] = String(record[i])
+	}
+
+	return NewList(fields...), nil
+}
@@ -66,6 +66,45 @@ func TestProtocols(t *testing.T) {
 				bass.Bindings{"b": bass.String("two")}.Scope(),
 			),
 		},
+		{
+			Name: "yaml",
+			Bind: bass.Bindings{
+				"yaml-body": bass.String("a: 1\nb: [two, 3.5]\n---\n- true\n- null\n"),
+			},
+			Bass: `(take-all (read (mkfile ./foo yaml-body) :yaml))`,
+			Result: bass.NewList(
+				bass.Bindings{
+					"a": bass.Int(1),
+					"b": bass.NewList(bass.String("two"), bass.Float(3.5)),
+				}.Scope(),
+				bass.NewList(bass.Bool(true), bass.Null{}),
+			),
+		},
+		{
+			Name: "toml",
+			Bind: bass.Bindings{
+				"toml-body": bass.String("name = \"bass\"\n\n[deps]\ngo = \"1.20\"\nmajor = 1\n"),
+			},
+			Bass: `(take-all (read (mkfile ./foo toml-body) :toml))`,
+			Result: bass.NewList(
+				bass.Bindings{
+					"name": bass.String("bass"),
+					"deps": bass.Bindings{
+						"go":    bass.String("1.20"),
+						"major": bass.Int(1),
+					}.Scope(),
+				}.Scope(),
+			),
+		},
+		{
+			Name: "csv",
+			Bass: `(take-all (read (mkfile ./foo "name,score\nalice,1\n\"bob, jr\",2,extra\n") :csv))`,
+			Result: bass.NewList(
+				bass.NewList(bass.String("name"), bass.String("score")),
+				bass.NewList(bass.String("alice"), bass.String("1")),
+				bass.NewList(bass.String("bob, jr"), bass.String("2"), bass.String("extra")),
+			),
+		},
 		{
 			Name: "tar",
 			Bass: `(collect (fn [f] {:meta (meta f) :content (-> f (read :raw) next)}) (read (mkfile ./foo tar-body) :tar))`,
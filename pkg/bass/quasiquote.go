@@ -0,0 +1,146 @@
+package bass
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// QuasiquoteSymbol is the symbol the reader uses for `form.
+	QuasiquoteSymbol = Symbol("quasiquote")
+
+	// UnquoteSymbol is the symbol the reader uses for ~form.
+	UnquoteSymbol = Symbol("unquote")
+
+	// UnquoteSplicingSymbol is the symbol the reader uses for ~@form.
+	UnquoteSplicingSymbol = Symbol("unquote-splicing")
+)
+
+// Quasiquote returns the form without evaluating it, except for any
+// (unquote) forms, which are replaced by their value, and any
+// (unquote-splicing) forms, whose value must be a list and is spliced into
+// the enclosing list.
+//
+// Quasiquotes may be nested. Each inner (quasiquote) form increases the
+// depth and each (unquote) decreases it; only forms unquoted back to the
+// outermost level are evaluated.
+func Quasiquote(ctx context.Context, scope *Scope, form Value, cont Cont) ReadyCont {
+	return quasiquote(ctx, scope, form, 0, cont)
+}
+
+func quasiquote(ctx context.Context, scope *Scope, form Value, depth int, cont Cont) ReadyCont {
+	if inner, ok := Unquoted(form, UnquoteSymbol); ok {
+		if depth == 0 {
+			return inner.Eval(ctx, scope, cont)
+		}
+
+		return quasiquoteWrapped(ctx, scope, UnquoteSymbol, inner, depth-1, cont)
+	}
+
+	if inner, ok := Unquoted(form, QuasiquoteSymbol); ok {
+		return quasiquoteWrapped(ctx, scope, QuasiquoteSymbol, inner, depth+1, cont)
+	}
+
+	switch x := form.(type) {
+	case Annotate:
+		return quasiquote(ctx, scope, x.Value, depth, Continue(func(res Value) Value {
+			x.Value = res
+			return cont.Call(x, nil)
+		}))
+	case Pair:
+		return quasiquotePair(ctx, scope, x.A, x.D, depth, func(a, d Value) Value {
+			return Pair{a, d}
+		}, cont)
+	case Cons:
+		return quasiquotePair(ctx, scope, x.A, x.D, depth, func(a, d Value) Value {
+			return Cons{a, d}
+		}, cont)
+	case Bind:
+		return quasiquote(ctx, scope, NewList(x...), depth, Continue(func(res Value) Value {
+			var list List
+			if err := res.Decode(&list); err != nil {
+				return cont.Call(nil, err)
+			}
+
+			vals, err := ToSlice(list)
+			if err != nil {
+				return cont.Call(nil, err)
+			}
+
+			return cont.Call(Bind(vals), nil)
+		}))
+	}
+
+	return cont.Call(form, nil)
+}
+
+// quasiquoteWrapped quasiquotes the inner form of a nested (sym inner) form
+// at the given depth, keeping it wrapped.
+func quasiquoteWrapped(ctx context.Context, scope *Scope, sym Symbol, inner Value, depth int, cont Cont) ReadyCont {
+	return quasiquote(ctx, scope, inner, depth, Continue(func(res Value) Value {
+		return cont.Call(NewList(sym, res), nil)
+	}))
+}
+
+// Unquoted returns the inner form if the form is a (sym form) list.
+func Unquoted(form Value, sym Symbol) (Value, bool) {
+	var pair Pair
+	if err := form.Decode(&pair); err != nil {
+		return nil, false
+	}
+
+	var head Symbol
+	if err := pair.A.Decode(&head); err != nil || head != sym {
+		return nil, false
+	}
+
+	var rest Pair
+	if err := pair.D.Decode(&rest); err != nil {
+		return nil, false
+	}
+
+	var empty Empty
+	if err := rest.D.Decode(&empty); err != nil {
+		return nil, false
+	}
+
+	return rest.A, true
+}
+
+func quasiquotePair(ctx context.Context, scope *Scope, a, d Value, depth int, mk func(Value, Value) Value, cont Cont) ReadyCont {
+	if inner, ok := Unquoted(a, UnquoteSplicingSymbol); ok {
+		if depth > 0 {
+			return quasiquoteWrapped(ctx, scope, UnquoteSplicingSymbol, inner, depth-1, Continue(func(qa Value) Value {
+				return quasiquote(ctx, scope, d, depth, Continue(func(qd Value) Value {
+					return cont.Call(mk(qa, qd), nil)
+				}))
+			}))
+		}
+
+		return inner.Eval(ctx, scope, Continue(func(spliced Value) Value {
+			var list List
+			if err := spliced.Decode(&list); err != nil {
+				return cont.Call(nil, fmt.Errorf("%s: %w", UnquoteSplicingSymbol, err))
+			}
+
+			vals, err := ToSlice(list)
+			if err != nil {
+				return cont.Call(nil, fmt.Errorf("%s: %w", UnquoteSplicingSymbol, err))
+			}
+
+			return quasiquote(ctx, scope, d, depth, Continue(func(rest Value) Value {
+				for i := len(vals) - 1; i >= 0; i-- {
+					rest = mk(vals[i], rest)
+				}
+
+				return cont.Call(rest, nil)
+			}))
+		}))
+	}
+
+	return quasiquote(ctx, scope, a, depth, Continue(func(qa Value) Value {
+		return quasiquote(ctx, scope, d, depth, Continue(func(qd Value) Value {
+			return cont.Call(mk(qa, qd), nil)
+		}))
+	}))
+}
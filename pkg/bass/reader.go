@@ -52,7 +52,7 @@ var (
 func NewReader(src io.Reader, file Readable) *Reader {
 	r := slurpreader.New(
 		src,
-		slurpreader.WithNumReader(readInt),
+		slurpreader.WithNumReader(readNumber),
 		slurpreader.WithSymbolReader(readSymbol),
 	)
 
@@ -77,8 +77,8 @@ func NewReader(src io.Reader, file Readable) *Reader {
 	// skip '# ' as a comment too for e.g. Dockerfile frontends
 	r.SetMacro(' ', true, readShebang)
 	r.SetMacro('\'', false, nil)
-	r.SetMacro('~', false, nil)
-	r.SetMacro('`', false, nil)
+	r.SetMacro('~', false, reader.readUnquote)
+	r.SetMacro('`', false, reader.readQuasiquote)
 	r.SetMacro(':', false, nil)
 
 	return reader
@@ -279,7 +279,7 @@ func readPath(segments []string) (Value, error) {
 	return path, nil
 }
 
-func readInt(rd *slurpreader.Reader, init rune) (slurpcore.Any, error) {
+func readNumber(rd *slurpreader.Reader, init rune) (slurpcore.Any, error) {
 	beginPos := rd.Position()
 
 	numStr, err := rd.Token(init)
@@ -287,12 +287,17 @@ func readInt(rd *slurpreader.Reader, init rune) (slurpcore.Any, error) {
 		return nil, err
 	}
 
-	v, err := strconv.ParseInt(numStr, 0, 64)
-	if err != nil {
-		return nil, annotateErr(rd, slurpreader.ErrNumberFormat, beginPos, numStr)
+	i, err := strconv.ParseInt(numStr, 0, 64)
+	if err == nil {
+		return Int(i), nil
+	}
+
+	f, err := strconv.ParseFloat(numStr, 64)
+	if err == nil {
+		return Float(f), nil
 	}
 
-	return Int(v), nil
+	return nil, annotateErr(rd, slurpreader.ErrNumberFormat, beginPos, numStr)
 }
 
 func readString(rd *slurpreader.Reader, init rune) (slurpcore.Any, error) {
@@ -503,6 +508,37 @@ func (reader *Reader) readCommented(rd *slurpreader.Reader, _ rune) (slurpcore.A
 	return annotated, nil
 }
 
+func (reader *Reader) readQuasiquote(rd *slurpreader.Reader, _ rune) (slurpcore.Any, error) {
+	form, err := reader.readAnnotate()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewList(QuasiquoteSymbol, form), nil
+}
+
+func (reader *Reader) readUnquote(rd *slurpreader.Reader, _ rune) (slurpcore.Any, error) {
+	sym := UnquoteSymbol
+
+	next, err := rd.NextRune()
+	if err != nil {
+		return nil, err
+	}
+
+	if next == '@' {
+		sym = UnquoteSplicingSymbol
+	} else {
+		rd.Unread(next)
+	}
+
+	form, err := reader.readAnnotate()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewList(sym, form), nil
+}
+
 func desugarMeta(v Value) (Bind, error) {
 	var bind Bind
 	if err := v.Decode(&bind); err == nil {
@@ -38,6 +38,18 @@ func TestReader(t *testing.T) {
 			Source: "42",
 			Result: bass.Int(42),
 		},
+		{
+			Source: "4.2",
+			Result: bass.Float(4.2),
+		},
+		{
+			Source: "-0.5",
+			Result: bass.Float(-0.5),
+		},
+		{
+			Source: "1e3",
+			Result: bass.Float(1000),
+		},
 
 		{
 			Source: "hello",
@@ -107,6 +119,60 @@ func TestReader(t *testing.T) {
 			Source: `()`,
 			Result: bass.Empty{},
 		},
+		{
+			Source: "`foo",
+			Result: bass.NewList(
+				bass.Symbol("quasiquote"),
+				bass.Symbol("foo"),
+			),
+		},
+		{
+			Source: "~foo",
+			Result: bass.NewList(
+				bass.Symbol("unquote"),
+				bass.Symbol("foo"),
+			),
+		},
+		{
+			Source: "~@foo",
+			Result: bass.NewList(
+				bass.Symbol("unquote-splicing"),
+				bass.Symbol("foo"),
+			),
+		},
+		{
+			Source: "`(foo ~bar ~@baz)",
+			Result: bass.NewList(
+				bass.Symbol("quasiquote"),
+				bass.NewList(
+					bass.Symbol("foo"),
+					bass.NewList(bass.Symbol("unquote"), bass.Symbol("bar")),
+					bass.NewList(bass.Symbol("unquote-splicing"), bass.Symbol("baz")),
+				),
+			),
+		},
+		{
+			Source: "`(a `(b ~(c ~x)))",
+			Result: bass.NewList(
+				bass.Symbol("quasiquote"),
+				bass.NewList(
+					bass.Symbol("a"),
+					bass.NewList(
+						bass.Symbol("quasiquote"),
+						bass.NewList(
+							bass.Symbol("b"),
+							bass.NewList(
+								bass.Symbol("unquote"),
+								bass.NewList(
+									bass.Symbol("c"),
+									bass.NewList(bass.Symbol("unquote"), bass.Symbol("x")),
+								),
+							),
+						),
+					),
+				),
+			),
+		},
 		{
 			Source: `(foo & bar)`,
 			Result: bass.Pair{
@@ -276,19 +342,11 @@ func TestReader(t *testing.T) {
 			Result: bass.Int(42),
 		},
 
-		// quote, syntax-quote, and unquote are not special forms
+		// quote is not a special form
 		{
 			Source: `'`,
 			Result: bass.Symbol("'"),
 		},
-		{
-			Source: "`",
-			Result: bass.Symbol("`"),
-		},
-		{
-			Source: `~`,
-			Result: bass.Symbol("~"),
-		},
 	} {
 		example.Run(t)
 	}
@@ -0,0 +1,31 @@
+package bass
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vito/is"
+)
+
+func TestCompileRegexpEvicts(t *testing.T) {
+	is := is.New(t)
+
+	first, err := compileRegexp("evict-0")
+	is.NoErr(err)
+
+	again, err := compileRegexp("evict-0")
+	is.NoErr(err)
+	is.True(again == first)
+
+	for i := 1; i <= maxRegexps; i++ {
+		_, err := compileRegexp(fmt.Sprintf("evict-%d", i))
+		is.NoErr(err)
+	}
+
+	regexpsL.Lock()
+	is.Equal(len(regexps), maxRegexps)
+	is.Equal(regexpsLRU.Len(), maxRegexps)
+	_, found := regexps["evict-0"]
+	is.True(!found)
+	regexpsL.Unlock()
+}
@@ -0,0 +1,72 @@
+package bass
+
+import (
+	"context"
+)
+
+func init() {
+	Ground.Set("with-registry-auth",
+		Annotated{
+			Value: Op("with-registry-auth", "[host username secret form]", func(ctx context.Context, cont Cont, scope *Scope, hostForm, usernameForm, secretForm, form Value) ReadyCont {
+				return hostForm.Eval(ctx, scope, Continue(func(hostVal Value) Value {
+					return usernameForm.Eval(ctx, scope, Continue(func(usernameVal Value) Value {
+						return secretForm.Eval(ctx, scope, Continue(func(secretVal Value) Value {
+							var auth RegistryAuth
+							if err := hostVal.Decode(&auth.Host); err != nil {
+								return cont.Call(nil, err)
+							}
+
+							if err := usernameVal.Decode(&auth.Username); err != nil {
+								return cont.Call(nil, err)
+							}
+
+							if err := secretVal.Decode(&auth.Secret); err != nil {
+								return cont.Call(nil, err)
+							}
+
+							return form.Eval(WithRegistryAuth(ctx, auth), scope, cont)
+						}))
+					}))
+				}))
+			}),
+			Meta: Bindings{"indent": Bool(true)}.Scope(),
+		},
+		`evaluates a form with credentials for a container registry`,
+		`The credentials are used when publishing to the registry host, taking precedence over any configured for the runtime, e.g. in ~/.docker/config.json.`,
+		`The secret is typically a token created with (mask).`,
+		`=> (with-registry-auth "ghcr.io" "bass" (mask "hunter2" :ghcr-token) (publish (from (linux/alpine) ($ true)) "ghcr.io/vito/bass-demo"))`)
+}
+
+// RegistryAuth is a username and secret for authenticating to a container
+// registry.
+type RegistryAuth struct {
+	// The registry host, e.g. ghcr.io.
+	Host string
+
+	// The username to authenticate as.
+	Username string
+
+	// The password or token.
+	Secret Secret
+}
+
+type registryAuthKey struct{}
+
+// WithRegistryAuth returns a context carrying credentials for a registry.
+//
+// Credentials set by a later call take precedence for the same host.
+func WithRegistryAuth(ctx context.Context, auth RegistryAuth) context.Context {
+	auths := RegistryAuthsFromContext(ctx)
+
+	cp := make([]RegistryAuth, len(auths), len(auths)+1)
+	copy(cp, auths)
+
+	return context.WithValue(ctx, registryAuthKey{}, append(cp, auth))
+}
+
+// RegistryAuthsFromContext returns the credentials set by WithRegistryAuth,
+// in the order they were set.
+func RegistryAuthsFromContext(ctx context.Context) []RegistryAuth {
+	auths, _ := ctx.Value(registryAuthKey{}).([]RegistryAuth)
+	return auths
+}
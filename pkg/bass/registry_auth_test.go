@@ -0,0 +1,66 @@
+package bass_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/runtimes"
+	"github.com/vito/is"
+)
+
+func TestWithRegistryAuth(t *testing.T) {
+	is := is.New(t)
+
+	var published [][]bass.RegistryAuth
+	ctx := bass.WithRuntimePool(context.Background(), &runtimes.Pool{
+		Runtimes: []runtimes.Assoc{
+			{
+				Platform: fakePlatform,
+				Runtime: &FakeRuntime{
+					PublishFunc: func(ctx context.Context, ref bass.ImageRef, thunk bass.Thunk) (bass.ImageRef, error) {
+						published = append(published, bass.RegistryAuthsFromContext(ctx))
+						ref.Digest = "sha256:cab9dd9ec4a7ee3f5e1a7ef49cdb4dc13a4bfa3cb0bbc39b2ff2e6a87f3b1c56"
+						return ref, nil
+					},
+				},
+			},
+		},
+	})
+
+	scope := bass.NewStandardScope()
+	scope.Set("thunk", bass.Thunk{
+		Image: &bass.ThunkImage{
+			Ref: &bass.ImageRef{
+				Platform: fakePlatform,
+			},
+		},
+		Args: []bass.Value{bass.CommandPath{"true"}},
+	})
+
+	_, err := bass.EvalString(ctx, scope, `
+		(with-registry-auth "ghcr.io" "outer" (mask "outer-pass" :outer)
+			(with-registry-auth "ghcr.io" "inner" (mask "inner-pass" :inner)
+				(publish thunk "ghcr.io/example/image:latest")))
+
+		(publish thunk "ghcr.io/example/image:latest")
+	`, bass.NewInMemoryFile("test", ""))
+	is.NoErr(err)
+
+	is.Equal(len(published), 2)
+
+	auths := published[0]
+	is.Equal(len(auths), 2)
+	is.Equal(auths[0].Host, "ghcr.io")
+	is.Equal(auths[0].Username, "outer")
+	is.Equal(string(auths[0].Secret.Reveal()), "outer-pass")
+	is.Equal(auths[1].Host, "ghcr.io")
+	is.Equal(auths[1].Username, "inner")
+	is.Equal(string(auths[1].Secret.Reveal()), "inner-pass")
+
+	// credentials only apply within the form
+	is.Equal(len(published[1]), 0)
+
+	_, err = bass.EvalString(ctx, scope, `(with-registry-auth "ghcr.io" "user" "not-a-secret" (publish thunk "ghcr.io/example/image:latest"))`, bass.NewInMemoryFile("test", ""))
+	is.True(err != nil)
+}
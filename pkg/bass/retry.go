@@ -0,0 +1,55 @@
+package bass
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/vito/progrock"
+)
+
+// Do calls f according to the retry configuration.
+func (retry ThunkRetry) Do(ctx context.Context, name string, f func(context.Context) error) error {
+	return Retry(ctx, name, retry.Attempts, retry.Backoff, f)
+}
+
+// Retry calls f until it succeeds or it has been called the given number of
+// times, waiting between attempts with an exponential backoff starting from
+// the given interval.
+//
+// Each attempt is recorded as its own progress group. The error from the last
+// attempt is returned.
+func Retry(ctx context.Context, name string, attempts int, interval time.Duration, f func(context.Context) error) error {
+	if attempts < 1 {
+		return fmt.Errorf("retry %s: attempts must be at least 1, have %d", name, attempts)
+	}
+
+	exp := backoff.NewExponentialBackOff()
+	exp.InitialInterval = interval
+	exp.MaxElapsedTime = 0 // bounded by attempts instead
+
+	var rec *progrock.Recorder
+
+	attempt := 0
+	return backoff.RetryNotify(func() error {
+		attempt++
+
+		var attemptCtx context.Context
+		attemptCtx, rec = progrock.WithGroup(ctx, fmt.Sprintf("%s [attempt %d/%d]", name, attempt, attempts))
+		defer rec.Complete()
+
+		err := f(attemptCtx)
+		if err != nil && (errors.Is(err, ErrInterrupted) || ctx.Err() != nil) {
+			return backoff.Permanent(err)
+		}
+
+		return err
+	}, backoff.WithContext(backoff.WithMaxRetries(exp, uint64(attempts-1)), ctx), func(err error, wait time.Duration) {
+		rec.Warn(
+			fmt.Sprintf("attempt %d/%d failed; retrying in %s", attempt, attempts, wait.Round(time.Millisecond)),
+			progrock.WithMessageLabels(progrock.Labelf("error", "%s", err)),
+		)
+	})
+}
@@ -54,6 +54,10 @@ func (runs *Runs) Err() error {
 }
 
 func (runs *Runs) record(err error) {
+	if err == nil {
+		return
+	}
+
 	runs.errsL.Lock()
 	if runs.errs != nil {
 		runs.errs = multierror.Append(runs.errs, err)
@@ -3,6 +3,7 @@ package bass
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"time"
 )
@@ -16,10 +17,13 @@ type Runtime interface {
 	Resolve(context.Context, ImageRef) (Thunk, error)
 	Run(context.Context, Thunk) error
 	Read(context.Context, io.Writer, Thunk) error
-	Export(context.Context, io.Writer, Thunk) error
+	RunResult(context.Context, Thunk) (RunResult, error)
+	Export(context.Context, io.Writer, Thunk, ExportOpts) error
 	Publish(context.Context, ImageRef, Thunk) (ImageRef, error)
+	PublishIndex(context.Context, ImageRef, []Thunk) (ImageRef, error)
 	ExportPath(context.Context, io.Writer, ThunkPath) error
 	Prune(context.Context, PruneOpts) error
+	Caches(context.Context) ([]CacheInfo, error)
 	Close() error
 }
 
@@ -35,6 +39,98 @@ type PruneOpts struct {
 
 	// Keep
 	KeepBytes int64
+
+	// Only prune caches with the given IDs, as returned by Runtime.Caches.
+	IDs []string
+}
+
+// CacheInfo describes a cache directory retained by a runtime, i.e. one
+// created by mounting a CachePath.
+type CacheInfo struct {
+	// The runtime's identifier for the cache.
+	//
+	// Runtimes are not required to use the CachePath ID, since not all of them
+	// can map their caches back to it.
+	ID string
+
+	// A human-readable description of the cache.
+	Description string
+
+	// The size of the cache in bytes.
+	Size int64
+
+	// When the cache was last used, or the zero value if it never was.
+	LastUsedAt time.Time
+
+	// The number of times the cache has been used.
+	UsageCount int
+}
+
+// ExportFormat is the format in which a thunk's image is exported.
+type ExportFormat string
+
+const (
+	// ExportOCI is an OCI image layout tarball.
+	ExportOCI ExportFormat = "oci"
+
+	// ExportOCIDir is an OCI image layout directory.
+	//
+	// Runtimes are not given this format; the caller exports an OCI tarball
+	// and unpacks it into the directory.
+	ExportOCIDir ExportFormat = "oci-dir"
+
+	// ExportDocker is a tarball that can be loaded with 'docker load', tagged
+	// with ExportOpts.Tags.
+	ExportDocker ExportFormat = "docker"
+
+	// ExportRootfs is a tarball of the image's root filesystem.
+	ExportRootfs ExportFormat = "rootfs"
+)
+
+// ParseExportFormat parses an export format, defaulting to ExportOCI for an
+// empty string.
+func ParseExportFormat(str string) (ExportFormat, error) {
+	switch format := ExportFormat(str); format {
+	case "":
+		return ExportOCI, nil
+	case ExportOCI, ExportOCIDir, ExportDocker, ExportRootfs:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown export format: %s", str)
+	}
+}
+
+// ExportOpts configures how a thunk's image is exported.
+type ExportOpts struct {
+	// The format to export. Defaults to ExportOCI.
+	Format ExportFormat
+
+	// Repository tags for the image, e.g. "example/image:latest". Only used by
+	// ExportDocker.
+	Tags []string
+}
+
+// RunResult is the outcome of running a thunk's command to completion,
+// whether or not it succeeded.
+type RunResult struct {
+	// The exit code of the command.
+	ExitCode int
+
+	// The output written to stdout.
+	Stdout []byte
+
+	// The output written to stderr.
+	Stderr []byte
+}
+
+// Scope returns the result as a scope with :exit-code, :stdout, and :stderr
+// bindings.
+func (result RunResult) Scope() *Scope {
+	return Bindings{
+		"exit-code": Int(result.ExitCode),
+		"stdout":    String(result.Stdout),
+		"stderr":    String(result.Stderr),
+	}.Scope()
 }
 
 type poolKey struct{}
@@ -1,9 +1,11 @@
 package bass
 
 import (
+	"bytes"
 	"context"
 	"encoding/base32"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -11,6 +13,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/vito/bass/pkg/proto"
 	"github.com/vito/bass/std"
@@ -18,6 +21,9 @@ import (
 	"github.com/zeebo/xxh3"
 	"google.golang.org/protobuf/encoding/protojson"
 	gproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/reflect/protorange"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 type Thunk struct {
@@ -99,6 +105,67 @@ type Thunk struct {
 	// Note that Bass thunks don't actually use the default args themselves.
 	DefaultArgs      []string `json:"default_args,omitempty"`
 	ClearDefaultArgs bool     `json:"clear_default_args,omitempty"`
+
+	// Timeout limits how long the thunk may run when it is run or read. A
+	// zero value means no limit.
+	//
+	// The timeout does not apply when the thunk is built as a dependency of
+	// another thunk.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// Retry configures running or reading the thunk again if it fails.
+	Retry *ThunkRetry `json:"retry,omitempty"`
+
+	// Resources configures limits on the resources the thunk's command may
+	// use. Runtimes which cannot enforce a limit return an error.
+	Resources *ThunkResources `json:"resources,omitempty"`
+
+	// Network configures the network the thunk's command runs in.
+	//
+	// NetworkModeHost requires Insecure to be set. Runtimes which cannot
+	// configure the network return an error.
+	Network NetworkMode `json:"network,omitempty"`
+
+	// User configures the user the thunk's command runs as, either as a name
+	// or as uid:gid. Either side may be a name or a numeric ID.
+	//
+	// The user is also set in the image config when the thunk is published.
+	User string `json:"user,omitempty"`
+
+	// ImageConfig configures fields of the OCI image config which only apply
+	// when the thunk is exported or published.
+	//
+	// Runtimes which cannot set a field return an error.
+	ImageConfig *ThunkImageConfig `json:"image_config,omitempty"`
+}
+
+// NetworkMode determines the network available to a thunk's command.
+type NetworkMode int
+
+const (
+	// NetworkModeDefault runs the command in the runtime's default network
+	// sandbox.
+	NetworkModeDefault NetworkMode = iota
+
+	// NetworkModeNone runs the command with no network access.
+	NetworkModeNone
+
+	// NetworkModeHost runs the command in the host's network namespace.
+	NetworkModeHost
+)
+
+// String returns the mode's keyword name, e.g. "none".
+func (mode NetworkMode) String() string {
+	switch mode {
+	case NetworkModeDefault:
+		return "default"
+	case NetworkModeNone:
+		return "none"
+	case NetworkModeHost:
+		return "host"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(mode))
+	}
 }
 
 type ThunkPort struct {
@@ -111,6 +178,75 @@ type ThunkTLS struct {
 	Key  FilePath `json:"key"`
 }
 
+type ThunkRetry struct {
+	// Attempts is the maximum number of times to run the thunk.
+	Attempts int `json:"attempts"`
+
+	// Backoff is the time to wait after the first failed attempt. It grows
+	// exponentially with each subsequent attempt.
+	Backoff time.Duration `json:"backoff,omitempty"`
+}
+
+type ThunkResources struct {
+	// CPU is the number of CPUs the command may use, e.g. 0.5 for half of one
+	// CPU.
+	CPU float64 `json:"cpu,omitempty"`
+
+	// Memory is the maximum amount of memory the command may use, in bytes.
+	Memory int64 `json:"memory,omitempty"`
+
+	// Pids is the maximum number of processes the command may run.
+	Pids int64 `json:"pids,omitempty"`
+}
+
+type ThunkImageConfig struct {
+	// WorkingDir is the working directory of containers run from the image.
+	//
+	// Unlike Dir, it does not affect the thunk's own command.
+	WorkingDir string `json:"working_dir,omitempty"`
+
+	// Annotations are arbitrary metadata set on the image manifest.
+	Annotations *Scope `json:"annotations,omitempty"`
+
+	// Labels are set on the image config.
+	//
+	// Unlike the thunk's own Labels, they do not affect the thunk's hash, so
+	// they don't bust the cache.
+	Labels *Scope `json:"labels,omitempty"`
+
+	// StopSignal is the signal sent to containers run from the image to make
+	// them exit, e.g. SIGINT.
+	StopSignal string `json:"stop_signal,omitempty"`
+
+	// Healthcheck configures how to check that containers run from the image
+	// are healthy.
+	Healthcheck *ThunkHealthcheck `json:"healthcheck,omitempty"`
+
+	// Volumes are paths to which containers run from the image write data that
+	// should outlive them.
+	Volumes []string `json:"volumes,omitempty"`
+}
+
+type ThunkHealthcheck struct {
+	// Command is run in the container to check its health. It is healthy if
+	// the command exits 0.
+	Command []string `json:"command"`
+
+	// Interval is the time to wait between checks.
+	Interval time.Duration `json:"interval,omitempty"`
+
+	// Timeout is the time to wait for a check before considering it failed.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// StartPeriod is the time to wait for the container to start before
+	// failed checks count towards Retries.
+	StartPeriod time.Duration `json:"start_period,omitempty"`
+
+	// Retries is the number of consecutive failed checks before the container
+	// is considered unhealthy.
+	Retries int `json:"retries,omitempty"`
+}
+
 func (thunk *Thunk) UnmarshalProto(msg proto.Message) error {
 	p, ok := msg.(*proto.Thunk)
 	if !ok {
@@ -140,6 +276,24 @@ func (thunk *Thunk) UnmarshalProto(msg proto.Message) error {
 	thunk.DefaultArgs = p.DefaultArgs
 	thunk.ClearDefaultArgs = p.ClearDefaultArgs
 	thunk.UseEntrypoint = p.UseEntrypoint
+	thunk.Timeout = time.Duration(p.TimeoutNs)
+	thunk.Network = NetworkMode(p.Network)
+	thunk.User = p.User
+
+	if p.Resources != nil {
+		thunk.Resources = &ThunkResources{
+			CPU:    p.Resources.Cpu,
+			Memory: p.Resources.Memory,
+			Pids:   p.Resources.Pids,
+		}
+	}
+
+	if p.Retry != nil {
+		thunk.Retry = &ThunkRetry{
+			Attempts: int(p.Retry.Attempts),
+			Backoff:  time.Duration(p.Retry.BackoffNs),
+		}
+	}
 
 	for i, stdin := range p.Stdin {
 		val, err := FromProto(stdin)
@@ -192,6 +346,50 @@ func (thunk *Thunk) UnmarshalProto(msg proto.Message) error {
 		}
 	}
 
+	if p.ImageConfig != nil {
+		thunk.ImageConfig = &ThunkImageConfig{
+			WorkingDir: p.ImageConfig.WorkingDir,
+			StopSignal: p.ImageConfig.StopSignal,
+			Volumes:    p.ImageConfig.Volumes,
+		}
+
+		if len(p.ImageConfig.Annotations) > 0 {
+			thunk.ImageConfig.Annotations = NewEmptyScope()
+
+			for _, bnd := range p.ImageConfig.Annotations {
+				val, err := FromProto(bnd.Value)
+				if err != nil {
+					return fmt.Errorf("unmarshal proto annotation[%s]: %w", bnd.Symbol, err)
+				}
+
+				thunk.ImageConfig.Annotations.Set(Symbol(bnd.Symbol), val)
+			}
+		}
+
+		if len(p.ImageConfig.Labels) > 0 {
+			thunk.ImageConfig.Labels = NewEmptyScope()
+
+			for _, bnd := range p.ImageConfig.Labels {
+				val, err := FromProto(bnd.Value)
+				if err != nil {
+					return fmt.Errorf("unmarshal proto image label[%s]: %w", bnd.Symbol, err)
+				}
+
+				thunk.ImageConfig.Labels.Set(Symbol(bnd.Symbol), val)
+			}
+		}
+
+		if hc := p.ImageConfig.Healthcheck; hc != nil {
+			thunk.ImageConfig.Healthcheck = &ThunkHealthcheck{
+				Command:     hc.Command,
+				Interval:    time.Duration(hc.IntervalNs),
+				Timeout:     time.Duration(hc.TimeoutNs),
+				StartPeriod: time.Duration(hc.StartPeriodNs),
+				Retries:     int(hc.Retries),
+			}
+		}
+	}
+
 	if len(p.Ports) > 0 {
 		for _, port := range p.Ports {
 			thunk.Ports = append(thunk.Ports, ThunkPort{
@@ -225,6 +423,14 @@ func MustThunk(cmd Path, stdin ...Value) Thunk {
 }
 
 func (thunk Thunk) Run(ctx context.Context) error {
+	if thunk.Retry != nil {
+		return thunk.Retry.Do(ctx, "run "+thunk.String(), thunk.run)
+	}
+
+	return thunk.run(ctx)
+}
+
+func (thunk Thunk) run(ctx context.Context) error {
 	platform := thunk.Platform()
 
 	if platform != nil {
@@ -233,9 +439,12 @@ func (thunk Thunk) Run(ctx context.Context) error {
 			return err
 		}
 
-		return runtime.Run(ctx, thunk)
+		return thunk.wrapErr(runtime.Run(ctx, thunk))
 	} else {
-		return Bass.Run(ctx, thunk, thunk.RunState(io.Discard))
+		ctx, done := thunk.TimeoutContext(ctx)
+		// NB: errors raised by Bass thunks are left as-is; they already carry
+		// their own trace
+		return done(Bass.Run(ctx, thunk, thunk.RunState(io.Discard)))
 	}
 }
 
@@ -279,6 +488,25 @@ func (thunk Thunk) RunDir() Path {
 }
 
 func (thunk Thunk) Read(ctx context.Context, w io.Writer) error {
+	if thunk.Retry != nil {
+		// buffer each attempt so that output from failed attempts is discarded
+		buf := new(bytes.Buffer)
+		err := thunk.Retry.Do(ctx, "read "+thunk.String(), func(ctx context.Context) error {
+			buf.Reset()
+			return thunk.read(ctx, buf)
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(w, buf)
+		return err
+	}
+
+	return thunk.read(ctx, w)
+}
+
+func (thunk Thunk) read(ctx context.Context, w io.Writer) error {
 	platform := thunk.Platform()
 
 	if platform != nil {
@@ -287,13 +515,106 @@ func (thunk Thunk) Read(ctx context.Context, w io.Writer) error {
 			return err
 		}
 
-		return runtime.Read(ctx, w, thunk)
+		return thunk.wrapErr(runtime.Read(ctx, w, thunk))
 	} else {
-		return Bass.Run(ctx, thunk, thunk.RunState(w))
+		ctx, done := thunk.TimeoutContext(ctx)
+		return done(Bass.Run(ctx, thunk, thunk.RunState(w)))
 	}
 }
 
-func (thunk Thunk) Export(ctx context.Context, w io.Writer) error {
+// Result runs the thunk and returns its exit code and output. Unlike Run,
+// a non-zero exit code is not an error.
+//
+// Bass thunks have no exit code; their output is returned with an exit code of
+// 0 and any error they raise is returned as-is.
+func (thunk Thunk) Result(ctx context.Context) (RunResult, error) {
+	platform := thunk.Platform()
+
+	if platform != nil {
+		runtime, err := RuntimeFromContext(ctx, *platform)
+		if err != nil {
+			return RunResult{}, err
+		}
+
+		result, err := runtime.RunResult(ctx, thunk)
+		if err != nil {
+			return RunResult{}, thunk.wrapErr(err)
+		}
+
+		return result, nil
+	} else {
+		ctx, done := thunk.TimeoutContext(ctx)
+
+		buf := new(bytes.Buffer)
+		err := done(Bass.Run(ctx, thunk, thunk.RunState(buf)))
+		if err != nil {
+			return RunResult{}, err
+		}
+
+		return RunResult{Stdout: buf.Bytes()}, nil
+	}
+}
+
+// TimeoutContext returns a context bounded by the thunk's timeout, if it has
+// one, along with a function which must be called with the result of running
+// the thunk. The function releases the context and converts an error caused by
+// the timeout into a TimeoutError.
+//
+// Runtimes use this to honor the thunk's timeout when running or reading it.
+func (thunk Thunk) TimeoutContext(ctx context.Context) (context.Context, func(error) error) {
+	if thunk.Timeout <= 0 {
+		return ctx, func(err error) error { return err }
+	}
+
+	tctx, cancel := context.WithTimeoutCause(ctx, thunk.Timeout, TimeoutError{
+		Timeout: thunk.Timeout,
+	})
+
+	return tctx, func(err error) error {
+		defer cancel()
+		return timeoutErr(ctx, tctx, err)
+	}
+}
+
+// timeoutErr returns the TimeoutError which caused the bounded context to be
+// canceled, if any, in place of the given error. Errors are returned as-is if
+// the parent context was canceled.
+func timeoutErr(ctx, tctx context.Context, err error) error {
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	var timeoutErr TimeoutError
+	if errors.As(context.Cause(tctx), &timeoutErr) {
+		return timeoutErr
+	}
+
+	return err
+}
+
+// wrapErr records the thunk on a non-nil error returned by a runtime so that it
+// can be identified as the failing thunk. Errors which already identify a
+// thunk are left as-is.
+func (thunk Thunk) wrapErr(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var thunkErr ThunkError
+	if errors.As(err, &thunkErr) {
+		return err
+	}
+
+	return ThunkError{
+		Thunk: thunk,
+		Err:   err,
+	}
+}
+
+// Export writes the thunk's image to w as a tarball in the given format.
+//
+// ExportOCIDir is written as an OCI tarball for the caller to unpack.
+func (thunk Thunk) Export(ctx context.Context, w io.Writer, opts ExportOpts) error {
 	platform := thunk.Platform()
 
 	if platform != nil {
@@ -302,12 +623,42 @@ func (thunk Thunk) Export(ctx context.Context, w io.Writer) error {
 			return err
 		}
 
-		return runtime.Export(ctx, w, thunk)
+		if opts.Format == ExportOCIDir {
+			opts.Format = ExportOCI
+		}
+
+		return runtime.Export(ctx, w, thunk, opts)
 	} else {
 		return fmt.Errorf("cannot export Bass thunk")
 	}
 }
 
+// ExportTo exports the thunk's image to a path on the host.
+//
+// ExportOCIDir must be exported to a directory and all other formats must be
+// exported to a file.
+func (thunk Thunk) ExportTo(ctx context.Context, dest HostPath, opts ExportOpts) error {
+	isDir := dest.Path.FilesystemPath().IsDir()
+	if opts.Format == ExportOCIDir && !isDir {
+		return fmt.Errorf("export format %s requires a directory: %s", opts.Format, dest)
+	} else if opts.Format != ExportOCIDir && isDir {
+		return fmt.Errorf("export format %s requires a file: %s", opts.Format, dest)
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(thunk.Export(ctx, w, opts))
+	}()
+
+	defer r.Close()
+
+	if opts.Format == ExportOCIDir {
+		return dest.WriteTar(ctx, r)
+	}
+
+	return dest.Write(ctx, r)
+}
+
 func (thunk Thunk) Publish(ctx context.Context, ref ImageRef) (ImageRef, error) {
 	platform := thunk.Platform()
 
@@ -323,6 +674,38 @@ func (thunk Thunk) Publish(ctx context.Context, ref ImageRef) (ImageRef, error)
 	}
 }
 
+// PublishIndex publishes an image index with an image for each thunk's
+// platform.
+//
+// The thunks are all published by the runtime for the first thunk's platform,
+// so it must be able to build images for the other platforms too.
+func PublishIndex(ctx context.Context, ref ImageRef, thunks []Thunk) (ImageRef, error) {
+	if len(thunks) == 0 {
+		return ref, fmt.Errorf("cannot publish empty index")
+	}
+
+	seen := map[string]bool{}
+	for _, thunk := range thunks {
+		platform := thunk.Platform()
+		if platform == nil {
+			return ref, fmt.Errorf("cannot publish Bass thunk")
+		}
+
+		if seen[platform.String()] {
+			return ref, fmt.Errorf("multiple thunks for platform %s", platform)
+		}
+
+		seen[platform.String()] = true
+	}
+
+	runtime, err := RuntimeFromContext(ctx, *thunks[0].Platform())
+	if err != nil {
+		return ref, err
+	}
+
+	return runtime.PublishIndex(ctx, ref, thunks)
+}
+
 func (thunk Thunk) Proto() (*proto.Thunk, error) {
 	tp, err := thunk.MarshalProto()
 	if err != nil {
@@ -555,6 +938,141 @@ func (thunk Thunk) WithInsecure(insecure bool) Thunk {
 	return thunk
 }
 
+// WithTimeout sets the thunk's timeout.
+func (thunk Thunk) WithTimeout(timeout time.Duration) Thunk {
+	thunk.Timeout = timeout
+	return thunk
+}
+
+// WithRetry configures the thunk to be run up to the given number of attempts,
+// waiting with exponential backoff between attempts.
+func (thunk Thunk) WithRetry(attempts int, backoff time.Duration) Thunk {
+	thunk.Retry = &ThunkRetry{
+		Attempts: attempts,
+		Backoff:  backoff,
+	}
+	return thunk
+}
+
+// WithCPU limits the number of CPUs the thunk may use.
+func (thunk Thunk) WithCPU(cpus float64) (Thunk, error) {
+	if cpus <= 0 {
+		return thunk, fmt.Errorf("cpu limit must be positive, have %g", cpus)
+	}
+
+	thunk.Resources = thunk.Resources.copy()
+	thunk.Resources.CPU = cpus
+	return thunk, nil
+}
+
+// WithMemory limits the amount of memory the thunk may use, in bytes.
+func (thunk Thunk) WithMemory(bytes int) (Thunk, error) {
+	if bytes <= 0 {
+		return thunk, fmt.Errorf("memory limit must be positive, have %d", bytes)
+	}
+
+	thunk.Resources = thunk.Resources.copy()
+	thunk.Resources.Memory = int64(bytes)
+	return thunk, nil
+}
+
+// WithPidsLimit limits the number of processes the thunk may run.
+func (thunk Thunk) WithPidsLimit(pids int) (Thunk, error) {
+	if pids <= 0 {
+		return thunk, fmt.Errorf("pids limit must be positive, have %d", pids)
+	}
+
+	thunk.Resources = thunk.Resources.copy()
+	thunk.Resources.Pids = int64(pids)
+	return thunk, nil
+}
+
+// WithNetwork sets the network mode for the thunk: :none, :default, or :host.
+func (thunk Thunk) WithNetwork(mode Symbol) (Thunk, error) {
+	switch mode {
+	case "default":
+		thunk.Network = NetworkModeDefault
+	case "none":
+		thunk.Network = NetworkModeNone
+	case "host":
+		thunk.Network = NetworkModeHost
+	default:
+		return thunk, fmt.Errorf("invalid network mode: %s", mode)
+	}
+
+	return thunk, nil
+}
+
+// WithUser sets the user the thunk's command runs as.
+func (thunk Thunk) WithUser(user string) Thunk {
+	thunk.User = user
+	return thunk
+}
+
+// WithWorkdir sets the working directory of the thunk's image config.
+func (thunk Thunk) WithWorkdir(dir string) Thunk {
+	thunk.ImageConfig = thunk.ImageConfig.copy()
+	thunk.ImageConfig.WorkingDir = dir
+	return thunk
+}
+
+// WithAnnotation adds an annotation to the thunk's image manifest.
+func (thunk Thunk) WithAnnotation(key Symbol, val string) Thunk {
+	thunk.ImageConfig = thunk.ImageConfig.copy()
+
+	if thunk.ImageConfig.Annotations == nil {
+		thunk.ImageConfig.Annotations = NewEmptyScope()
+	} else {
+		thunk.ImageConfig.Annotations = thunk.ImageConfig.Annotations.Copy()
+	}
+
+	thunk.ImageConfig.Annotations.Set(key, String(val))
+	return thunk
+}
+
+// WithImageLabel adds a label to the thunk's image config without adding it
+// to the thunk's own labels.
+func (thunk Thunk) WithImageLabel(key Symbol, val string) Thunk {
+	thunk.ImageConfig = thunk.ImageConfig.copy()
+
+	if thunk.ImageConfig.Labels == nil {
+		thunk.ImageConfig.Labels = NewEmptyScope()
+	} else {
+		thunk.ImageConfig.Labels = thunk.ImageConfig.Labels.Copy()
+	}
+
+	thunk.ImageConfig.Labels.Set(key, String(val))
+	return thunk
+}
+
+// WithStopSignal sets the stop signal of the thunk's image config.
+func (thunk Thunk) WithStopSignal(signal string) Thunk {
+	thunk.ImageConfig = thunk.ImageConfig.copy()
+	thunk.ImageConfig.StopSignal = signal
+	return thunk
+}
+
+// WithHealthcheck sets the healthcheck of the thunk's image config.
+func (thunk Thunk) WithHealthcheck(healthcheck ThunkHealthcheck) (Thunk, error) {
+	if len(healthcheck.Command) == 0 {
+		return thunk, fmt.Errorf("healthcheck command must not be empty")
+	}
+
+	thunk.ImageConfig = thunk.ImageConfig.copy()
+	thunk.ImageConfig.Healthcheck = &healthcheck
+	return thunk, nil
+}
+
+// WithVolume adds a volume to the thunk's image config.
+func (thunk Thunk) WithVolume(path string) Thunk {
+	thunk.ImageConfig = thunk.ImageConfig.copy()
+	thunk.ImageConfig.Volumes = append(
+		append([]string{}, thunk.ImageConfig.Volumes...),
+		path,
+	)
+	return thunk
+}
+
 // WithDir sets the thunk's working directory.
 func (thunk Thunk) WithDir(dir ThunkDir) Thunk {
 	thunk.Dir = &dir
@@ -760,12 +1278,42 @@ func (thunk Thunk) CachePath(ctx context.Context, dest string) (string, error) {
 	return Cache(ctx, filepath.Join(dest, "thunk-outputs", hash), thunk)
 }
 
+// HashKey returns a hash of the thunk's proto form.
+//
+// Execution policies like timeouts and retries are left out, including those
+// of any thunks it embeds, so that they don't affect the thunk's identity.
+// Image labels are left out too, since they only apply to the image.
 func (thunk Thunk) HashKey() (uint64, error) {
 	msg, err := thunk.MarshalProto()
 	if err != nil {
 		return 0, err
 	}
 
+	err = protorange.Range(msg.ProtoReflect(), func(vs protopath.Values) error {
+		m, ok := vs.Index(-1).Value.Interface().(protoreflect.Message)
+		if !ok {
+			return nil
+		}
+
+		if pt, ok := m.Interface().(*proto.Thunk); ok {
+			pt.TimeoutNs = 0
+			pt.Retry = nil
+
+			if pt.ImageConfig != nil {
+				pt.ImageConfig.Labels = nil
+
+				if gproto.Size(pt.ImageConfig) == 0 {
+					pt.ImageConfig = nil
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
 	payload, err := gproto.Marshal(msg)
 	if err != nil {
 		return 0, err
@@ -781,3 +1329,25 @@ func b32(n uint64) string {
 		WithPadding(base32.NoPadding).
 		EncodeToString(sum[:])
 }
+
+// copy returns a copy of the resources so that they can be modified without
+// affecting other thunks, or empty resources if nil.
+func (res *ThunkResources) copy() *ThunkResources {
+	if res == nil {
+		return &ThunkResources{}
+	}
+
+	cp := *res
+	return &cp
+}
+
+// copy returns a copy of the image config so that it can be modified without
+// affecting other thunks, or an empty image config if nil.
+func (cfg *ThunkImageConfig) copy() *ThunkImageConfig {
+	if cfg == nil {
+		return &ThunkImageConfig{}
+	}
+
+	cp := *cfg
+	return &cp
+}
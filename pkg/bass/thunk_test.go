@@ -1,10 +1,20 @@
 package bass_test
 
 import (
+	"archive/tar"
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/bass/testdata"
+	"github.com/vito/bass/pkg/basstest"
+	"github.com/vito/bass/pkg/runtimes"
 	"github.com/vito/is"
 )
 
@@ -33,3 +43,324 @@ func TestThunkHash(t *testing.T) {
 	// always the same value
 	is.Equal(hash, "LCV6HSUTK70GE")
 }
+
+func TestThunkHashResources(t *testing.T) {
+	is := is.New(t)
+
+	thunk := bass.MustThunk(bass.FilePath{"run"})
+
+	hash, err := thunk.Hash()
+	is.NoErr(err)
+
+	limited, err := thunk.WithMemory(1024)
+	is.NoErr(err)
+
+	limitedHash, err := limited.Hash()
+	is.NoErr(err)
+	is.True(hash != limitedHash)
+
+	// only the limits that are set should affect the hash
+	is.Equal(limited.Resources, &bass.ThunkResources{Memory: 1024})
+	is.Equal(thunk.Resources, nil)
+}
+
+func TestThunkHashTimeout(t *testing.T) {
+	is := is.New(t)
+
+	thunk := bass.MustThunk(bass.FilePath{"run"})
+
+	hash, err := thunk.Hash()
+	is.NoErr(err)
+
+	timed, err := thunk.WithTimeout(time.Minute).Hash()
+	is.NoErr(err)
+	is.Equal(timed, hash)
+
+	// embedded thunks don't affect the hash either
+	wrapped, err := bass.MustThunk(bass.FilePath{"wrap"}).
+		WithArgs([]bass.Value{thunk}).
+		Hash()
+	is.NoErr(err)
+
+	wrappedTimed, err := bass.MustThunk(bass.FilePath{"wrap"}).
+		WithArgs([]bass.Value{thunk.WithTimeout(time.Minute)}).
+		Hash()
+	is.NoErr(err)
+	is.Equal(wrappedTimed, wrapped)
+}
+
+func TestThunkHashRetry(t *testing.T) {
+	is := is.New(t)
+
+	thunk := bass.MustThunk(bass.FilePath{"run"})
+
+	hash, err := thunk.Hash()
+	is.NoErr(err)
+
+	retried, err := thunk.WithRetry(3, time.Second).Hash()
+	is.NoErr(err)
+	is.Equal(retried, hash)
+}
+
+func TestThunkHashImageLabel(t *testing.T) {
+	is := is.New(t)
+
+	thunk := bass.MustThunk(bass.FilePath{"run"})
+
+	hash, err := thunk.Hash()
+	is.NoErr(err)
+
+	imageLabeled, err := thunk.WithImageLabel("version", "1.0").Hash()
+	is.NoErr(err)
+	is.Equal(imageLabeled, hash)
+
+	// unlike thunk labels
+	labeled, err := thunk.WithLabel("version", bass.String("1.0")).Hash()
+	is.NoErr(err)
+	is.True(labeled != hash)
+}
+
+func TestThunkResult(t *testing.T) {
+	is := is.New(t)
+
+	ctx := bass.WithRuntimePool(context.Background(), &runtimes.Pool{
+		Runtimes: []runtimes.Assoc{
+			{
+				Platform: fakePlatform,
+				Runtime: &FakeRuntime{
+					ResultFunc: func(ctx context.Context, thunk bass.Thunk) (bass.RunResult, error) {
+						return bass.RunResult{
+							ExitCode: 3,
+							Stdout:   []byte("out\n"),
+							Stderr:   []byte("err\n"),
+						}, nil
+					},
+				},
+			},
+		},
+	})
+
+	scope := bass.NewStandardScope()
+	scope.Set("thunk", bass.Thunk{
+		Image: &bass.ThunkImage{
+			Ref: &bass.ImageRef{
+				Platform: fakePlatform,
+			},
+		},
+		Args: []bass.Value{bass.CommandPath{"false"}},
+	})
+	scope.Set("inc", bass.Thunk{
+		Args:  []bass.Value{bass.NewFSPath(testdata.FS, bass.ParseFileOrDirPath("inc"))},
+		Stdin: []bass.Value{bass.Int(1), bass.Int(2)},
+	})
+
+	res, err := bass.EvalString(ctx, scope, "(run-result thunk)", bass.NewInMemoryFile("test", ""))
+	is.NoErr(err)
+	basstest.Equal(t, res, bass.Bindings{
+		"exit-code": bass.Int(3),
+		"stdout":    bass.String("out\n"),
+		"stderr":    bass.String("err\n"),
+	}.Scope())
+
+	// Bass thunks have no exit code or stderr
+	res, err = bass.EvalString(ctx, scope, "(run-result inc)", bass.NewInMemoryFile("test", ""))
+	is.NoErr(err)
+	basstest.Equal(t, res, bass.Bindings{
+		"exit-code": bass.Int(0),
+		"stdout":    bass.String("2\n3\n"),
+		"stderr":    bass.String(""),
+	}.Scope())
+}
+
+func TestPublishIndex(t *testing.T) {
+	ctx := bass.WithRuntimePool(context.Background(), &runtimes.Pool{
+		Runtimes: []runtimes.Assoc{
+			{
+				Platform: fakePlatform,
+				Runtime:  &FakeRuntime{},
+			},
+		},
+	})
+
+	scope := bass.NewStandardScope()
+	scope.Set("thunk", bass.Thunk{
+		Image: &bass.ThunkImage{
+			Ref: &bass.ImageRef{
+				Platform: fakePlatform,
+			},
+		},
+		Args: []bass.Value{bass.CommandPath{"true"}},
+	})
+	scope.Set("bass-thunk", bass.Thunk{
+		Args: []bass.Value{bass.NewFSPath(testdata.FS, bass.ParseFileOrDirPath("inc"))},
+	})
+
+	for _, example := range []struct {
+		Name string
+		Bass string
+		Err  string
+	}{
+		{
+			Name: "empty",
+			Bass: `(publish-index [] "example/image:latest")`,
+			Err:  "cannot publish empty index",
+		},
+		{
+			Name: "duplicate platform",
+			Bass: `(publish-index [thunk thunk] "example/image:latest")`,
+			Err:  "multiple thunks for platform fake",
+		},
+		{
+			Name: "bass thunk",
+			Bass: `(publish-index [bass-thunk] "example/image:latest")`,
+			Err:  "cannot publish Bass thunk",
+		},
+		{
+			Name: "untagged",
+			Bass: `(publish-index [thunk] "example/image@sha256:cab9dd9ec4a7ee3f5e1a7ef49cdb4dc13a4bfa3cb0bbc39b2ff2e6a87f3b1c56")`,
+			Err:  "ref must be named and tagged",
+		},
+		{
+			Name: "runtime",
+			Bass: `(publish-index [thunk] "example/image:latest")`,
+			Err:  "PublishIndex unimplemented",
+		},
+	} {
+		example := example
+		t.Run(example.Name, func(t *testing.T) {
+			is := is.New(t)
+
+			_, err := bass.EvalString(ctx, scope, example.Bass, bass.NewInMemoryFile("test", ""))
+			is.True(err != nil)
+			is.True(strings.Contains(err.Error(), example.Err))
+		})
+	}
+}
+
+func TestThunkExport(t *testing.T) {
+	var exported []bass.ExportOpts
+	ctx := bass.WithRuntimePool(context.Background(), &runtimes.Pool{
+		Runtimes: []runtimes.Assoc{
+			{
+				Platform: fakePlatform,
+				Runtime: &FakeRuntime{
+					ExportFunc: func(ctx context.Context, w io.Writer, thunk bass.Thunk, opts bass.ExportOpts) error {
+						exported = append(exported, opts)
+
+						tw := tar.NewWriter(w)
+						for name, content := range map[string]string{
+							"oci-layout":            `{"imageLayoutVersion":"1.0.0"}`,
+							"blobs/sha256/deadbeef": "blob",
+						} {
+							err := tw.WriteHeader(&tar.Header{
+								Typeflag: tar.TypeReg,
+								Name:     name,
+								Mode:     0644,
+								Size:     int64(len(content)),
+							})
+							if err != nil {
+								return err
+							}
+
+							_, err = tw.Write([]byte(content))
+							if err != nil {
+								return err
+							}
+						}
+
+						return tw.Close()
+					},
+				},
+			},
+		},
+	})
+
+	dir := t.TempDir()
+
+	scope := bass.NewStandardScope()
+	scope.Set("dir", bass.NewHostDir(dir))
+	scope.Set("thunk", bass.Thunk{
+		Image: &bass.ThunkImage{
+			Ref: &bass.ImageRef{
+				Platform: fakePlatform,
+			},
+		},
+		Args: []bass.Value{bass.CommandPath{"true"}},
+	})
+
+	t.Run("oci-dir", func(t *testing.T) {
+		is := is.New(t)
+
+		exported = nil
+
+		res, err := bass.EvalString(ctx, scope, `(export thunk dir/image/ :format :oci-dir)`, bass.NewInMemoryFile("test", ""))
+		is.NoErr(err)
+		basstest.Equal(t, res, bass.NewHostPath(dir, bass.ParseFileOrDirPath("image/")))
+		is.Equal(exported, []bass.ExportOpts{{Format: bass.ExportOCI}})
+
+		layout, err := os.ReadFile(filepath.Join(dir, "image", "oci-layout"))
+		is.NoErr(err)
+		is.Equal(string(layout), `{"imageLayoutVersion":"1.0.0"}`)
+
+		blob, err := os.ReadFile(filepath.Join(dir, "image", "blobs", "sha256", "deadbeef"))
+		is.NoErr(err)
+		is.Equal(string(blob), "blob")
+	})
+
+	t.Run("docker", func(t *testing.T) {
+		is := is.New(t)
+
+		exported = nil
+
+		_, err := bass.EvalString(ctx, scope, `(export thunk dir/image.tar :format :docker :tags ["example/image:latest"])`, bass.NewInMemoryFile("test", ""))
+		is.NoErr(err)
+		is.Equal(exported, []bass.ExportOpts{{
+			Format: bass.ExportDocker,
+			Tags:   []string{"example/image:latest"},
+		}})
+
+		_, err = os.Stat(filepath.Join(dir, "image.tar"))
+		is.NoErr(err)
+	})
+
+	for _, example := range []struct {
+		Name string
+		Bass string
+		Err  string
+	}{
+		{
+			Name: "unknown format",
+			Bass: `(export thunk dir/image.tar :format :zip)`,
+			Err:  "unknown export format: zip",
+		},
+		{
+			Name: "odd opts",
+			Bass: `(export thunk dir/image.tar :format)`,
+			Err:  "export options must be keyword-value pairs",
+		},
+		{
+			Name: "oci-dir to file",
+			Bass: `(export thunk dir/image.tar :format :oci-dir)`,
+			Err:  "export format oci-dir requires a directory",
+		},
+		{
+			Name: "tarball to dir",
+			Bass: `(export thunk dir/image/ :format :rootfs)`,
+			Err:  "export format rootfs requires a file",
+		},
+		{
+			Name: "oci-dir without dest",
+			Bass: `(export thunk :format :oci-dir)`,
+			Err:  "export format oci-dir requires a host directory",
+		},
+	} {
+		example := example
+		t.Run(example.Name, func(t *testing.T) {
+			is := is.New(t)
+
+			_, err := bass.EvalString(ctx, scope, example.Bass, bass.NewInMemoryFile("test", ""))
+			is.True(err != nil)
+			is.True(strings.Contains(err.Error(), example.Err))
+		})
+	}
+}
@@ -55,13 +55,10 @@ func ValueOf(src any) (Value, error) {
 		return Bool(x), nil
 	case int:
 		return Int(x), nil
+	case float64:
+		return Float(x), nil
 	case json.Number:
-		i, err := x.Int64()
-		if err != nil {
-			return String(x.String()), nil
-		}
-
-		return Int(i), nil
+		return numberValue(x), nil
 	case string:
 		return String(x), nil
 	case map[string]any:
@@ -22,6 +22,7 @@ var allConstValues = []bass.Value{
 	bass.Bool(true),
 	bass.Bool(false),
 	bass.Int(42),
+	bass.Float(4.2),
 	bass.String("hello"),
 	noopOp,
 	noopFn,
@@ -145,7 +146,23 @@ func TestValueOf(t *testing.T) {
 		},
 		{
 			json.Number(fmt.Sprintf("%.5f", math.Pi)),
-			bass.String("3.14159"),
+			bass.Float(3.14159),
+		},
+		{
+			json.Number("1e400"),
+			bass.String("1e400"),
+		},
+		{
+			json.Number("2e3"),
+			bass.Float(2000),
+		},
+		{
+			json.Number("18446744073709551616"),
+			bass.String("18446744073709551616"),
+		},
+		{
+			1.5,
+			bass.Float(1.5),
 		},
 		{
 			[]string{},
@@ -0,0 +1,640 @@
+// Package bassfmt formats Bass source code.
+//
+// Forms are read with the Bass reader and printed back with canonical
+// whitespace and indentation. Line breaks between forms are kept as written,
+// while runs of blank lines are collapsed into one and closing delimiters are
+// kept on the same line as the last form they close, unless followed by a
+// comment.
+//
+// Multiline lists are indented the same way as Vim's lispwords: calls to
+// combiners marked with ^:indent, and to def, are indented by 2 spaces, and
+// other calls align with their first argument.
+//
+// Comments and ^ metadata are preserved. Atoms such as strings, numbers, and
+// paths are printed exactly as they were written.
+package bassfmt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/hl"
+)
+
+// Format formats the source code of a Bass script.
+//
+// Returns a bass.ReadError if the source cannot be read.
+func Format(file bass.Readable, src []byte) ([]byte, error) {
+	reader := bass.NewReader(bytes.NewReader(src), file)
+
+	var forms []bass.Annotate
+	for {
+		val, err := reader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		form, ok := val.(bass.Annotate)
+		if !ok {
+			return nil, fmt.Errorf("format: expected annotated form, got %T", val)
+		}
+
+		forms = append(forms, form)
+	}
+
+	source := []rune(string(src))
+
+	f := &formatter{
+		src:       source,
+		lines:     lineOffsets(source),
+		lispWords: map[bass.Symbol]bool{},
+	}
+
+	for _, word := range groundLispWords() {
+		f.lispWords[word] = true
+	}
+
+	// def is a builtin without ^:indent, but reads better like defn
+	f.lispWords["def"] = true
+
+	for _, form := range forms {
+		f.collectLispWords(form)
+	}
+
+	f.module(forms)
+
+	return f.out.Bytes(), nil
+}
+
+var groundLispWordsOnce sync.Once
+var groundLispWordsVal []bass.Symbol
+
+func groundLispWords() []bass.Symbol {
+	groundLispWordsOnce.Do(func() {
+		groundLispWordsVal = hl.LispWords(bass.Ground)
+	})
+
+	return groundLispWordsVal
+}
+
+type formatter struct {
+	src   []rune
+	lines []int
+
+	// combiners whose multiline forms are indented by 2 spaces
+	lispWords map[bass.Symbol]bool
+
+	out bytes.Buffer
+	col int
+
+	// indentation to write before the next content on a new line, if any
+	pending int
+	newline bool
+}
+
+func lineOffsets(src []rune) []int {
+	lines := []int{0}
+	for i, r := range src {
+		if r == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+
+	return lines
+}
+
+// start returns the offset of the start of the form in the source.
+//
+// The range of a form read at the top level includes any shebang line
+// skipped before it, so it is skipped here too.
+func (f *formatter) start(form bass.Annotate) int {
+	start := f.offset(form.Range.Start)
+	for start < len(f.src) {
+		switch r := f.src[start]; {
+		case r == '#' && start+1 < len(f.src) && (f.src[start+1] == '!' || f.src[start+1] == ' '):
+			for start < len(f.src) && f.src[start] != '\n' {
+				start++
+			}
+		case unicode.IsSpace(r) || r == ',':
+			start++
+		default:
+			return start
+		}
+	}
+
+	return start
+}
+
+// offset converts a reader position to an offset in the source.
+func (f *formatter) offset(pos bass.Position) int {
+	if pos.Ln < 1 {
+		return 0
+	}
+
+	if pos.Ln > len(f.lines) {
+		return len(f.src)
+	}
+
+	offset := f.lines[pos.Ln-1] + pos.Col
+	if offset > len(f.src) {
+		return len(f.src)
+	}
+
+	return offset
+}
+
+// collectLispWords adds combiners defined in the source with ^:indent, e.g.
+// ^:indent (defn foo ...).
+func (f *formatter) collectLispWords(form bass.Annotate) {
+	if form.Meta != nil && hasIndentMeta(*form.Meta) {
+		elems, _, ok := listElems(form.Value)
+		if ok && len(elems) >= 2 {
+			var name bass.Symbol
+			if err := elems[1].Value.Decode(&name); err == nil {
+				f.lispWords[name] = true
+			}
+		}
+	}
+
+	elems, rest, ok := containerElems(form.Value)
+	if !ok {
+		return
+	}
+
+	for _, elem := range elems {
+		f.collectLispWords(elem)
+	}
+
+	if rest != nil {
+		f.collectLispWords(*rest)
+	}
+}
+
+func hasIndentMeta(meta bass.Bind) bool {
+	for i := 0; i+1 < len(meta); i += 2 {
+		var key bass.Keyword
+		if err := meta[i].Decode(&key); err != nil {
+			continue
+		}
+
+		var val bool
+		if err := meta[i+1].Decode(&val); err != nil {
+			continue
+		}
+
+		if key.Symbol() == hl.IndentMetaBinding && val {
+			return true
+		}
+	}
+
+	return false
+}
+
+// module writes the top-level forms along with any comments between them.
+func (f *formatter) module(forms []bass.Annotate) {
+	prevEnd := 0
+	for i, form := range forms {
+		g := f.gap(prevEnd, f.start(form), i > 0)
+
+		lines := g.lines
+		if i == 0 {
+			lines = trimBlanks(lines, true, false)
+		} else {
+			f.trailing(g)
+			f.breakLine(0)
+		}
+
+		f.commentLines(lines, 0)
+
+		f.form(form, g.metaNewline)
+
+		prevEnd = f.offset(form.Range.End)
+	}
+
+	g := f.gap(prevEnd, len(f.src), len(forms) > 0)
+	f.trailing(g)
+
+	lines := trimBlanks(g.lines, len(forms) == 0, true)
+	if len(forms) > 0 {
+		f.breakLine(0)
+	}
+
+	f.commentLines(lines, 0)
+}
+
+// form writes an annotated form, preceded by its meta.
+func (f *formatter) form(form bass.Annotate, metaNewline bool) {
+	col := f.column()
+
+	if form.Meta != nil && len(*form.Meta) > 0 {
+		f.meta(*form.Meta)
+
+		if metaNewline {
+			f.breakLine(col)
+		} else {
+			f.write(" ")
+		}
+	}
+
+	f.value(form)
+}
+
+// value writes the form's value without its meta.
+func (f *formatter) value(form bass.Annotate) {
+	if prefix, inner, ok := quoted(form.Value); ok {
+		f.write(prefix)
+		f.form(inner, false)
+		return
+	}
+
+	if elems, rest, ok := containerElems(form.Value); ok {
+		f.container(form, elems, rest)
+		return
+	}
+
+	start := f.start(form)
+	end := f.offset(form.Range.End)
+	f.write(string(f.src[start:end]))
+}
+
+// container writes a list, cons, or bind form.
+func (f *formatter) container(form bass.Annotate, elems []bass.Annotate, rest *bass.Annotate) {
+	var open, close string
+	var isList bool
+	switch form.Value.(type) {
+	case bass.Pair:
+		open, close = "(", ")"
+		isList = true
+	case bass.Cons:
+		open, close = "[", "]"
+	case bass.Bind:
+		open, close = "{", "}"
+	}
+
+	openCol := f.column()
+	f.write(open)
+
+	// align with the opening delimiter by default
+	indent := openCol + 1
+	alignArg := false
+	if isList && len(elems) > 0 && isAtom(elems[0]) {
+		// calls are indented by 2 spaces, unless an argument follows the
+		// combiner on the first line, in which case the rest of the
+		// arguments align with it (Vim lispwords style)
+		indent = openCol + 2
+
+		var sym bass.Symbol
+		isLispWord := elems[0].Meta == nil &&
+			elems[0].Value.Decode(&sym) == nil &&
+			f.lispWords[sym]
+
+		alignArg = !isLispWord
+	}
+
+	all := elems
+	if rest != nil {
+		all = append(append([]bass.Annotate{}, elems...), *rest)
+	}
+
+	prevEnd := f.start(form) + 1
+	for i, elem := range all {
+		g := f.gap(prevEnd, f.start(elem), true)
+
+		isRest := rest != nil && i == len(all)-1
+
+		if i == 0 {
+			// keep a comment following the opening delimiter on the same line
+			f.write(g.trailing)
+
+			lines := trimBlanks(g.lines, true, false)
+			if g.trailing != "" || len(lines) > 0 {
+				f.breakLine(indent)
+				f.commentLines(lines, indent)
+			}
+		} else if g.newline || g.trailing != "" || hasComments(g.lines) {
+			f.trailing(g)
+			f.breakLine(indent)
+			f.commentLines(g.lines, indent)
+		} else {
+			f.write(" ")
+		}
+
+		if isRest {
+			f.write("& ")
+		}
+
+		elemCol := f.column()
+
+		f.form(elem, g.metaNewline)
+
+		if i == 1 && alignArg && !g.newline && !isRest {
+			indent = elemCol
+		}
+
+		prevEnd = f.offset(elem.Range.End)
+	}
+
+	end := f.offset(form.Range.End) - 1
+	if end < prevEnd {
+		end = prevEnd
+	}
+
+	g := f.gap(prevEnd, end, true)
+	if g.trailing != "" || hasComments(g.lines) {
+		// a comment runs to the end of the line, so the closing delimiter has
+		// to go on the next one
+		f.trailing(g)
+		f.breakLine(indent)
+		f.commentLines(trimBlanks(g.lines, true, true), indent)
+	} else if g.newline && f.closerComment(end) {
+		// keep a comment on a line of its own closing delimiter from being
+		// pulled onto the last form, e.g. ") ; provide", along with any blank
+		// line before it
+		f.breakLine(indent)
+		f.commentLines(g.lines, indent)
+	}
+
+	f.write(close)
+}
+
+// closerComment returns true if the closing delimiter at the given offset is
+// followed by a comment on the same line, after any other closing
+// delimiters.
+func (f *formatter) closerComment(offset int) bool {
+	for i := offset + 1; i < len(f.src); i++ {
+		switch r := f.src[i]; {
+		case r == ';':
+			return true
+		case r == ')' || r == ']' || r == '}' || r == ',' || (unicode.IsSpace(r) && r != '\n'):
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// meta writes the metadata for a form.
+//
+// The reader merges stacked metadata into one binding, so shorthand forms
+// like ^:indent are printed back separately in the order they were written
+// while explicit bindings are printed as one ^{...} form.
+func (f *formatter) meta(meta bass.Bind) {
+	var chunks [][]bass.Value
+	var explicit []bass.Value
+	for i := 0; i+1 < len(meta); i += 2 {
+		key, val := meta[i], meta[i+1]
+		if _, ok := key.(bass.Annotate); ok {
+			explicit = append(explicit, key, val)
+			continue
+		}
+
+		if len(explicit) > 0 {
+			chunks = append(chunks, explicit)
+			explicit = nil
+		}
+
+		chunks = append(chunks, []bass.Value{key, val})
+	}
+
+	if len(explicit) > 0 {
+		chunks = append(chunks, explicit)
+	}
+
+	for i := len(chunks) - 1; i >= 0; i-- {
+		chunk := chunks[i]
+
+		f.write("^")
+
+		if _, ok := chunk[0].(bass.Annotate); ok {
+			f.write("{")
+			for j, val := range chunk {
+				if j > 0 {
+					f.write(" ")
+				}
+
+				f.metaValue(val)
+			}
+			f.write("}")
+		} else {
+			f.write(shorthandMeta(chunk[0], chunk[1]))
+		}
+
+		if i > 0 {
+			f.write(" ")
+		}
+	}
+}
+
+func (f *formatter) metaValue(val bass.Value) {
+	if form, ok := val.(bass.Annotate); ok {
+		f.form(form, false)
+	} else {
+		f.write(val.String())
+	}
+}
+
+// shorthandMeta returns the notation for metadata desugared by the reader.
+func shorthandMeta(key, val bass.Value) string {
+	var kw bass.Keyword
+	if err := key.Decode(&kw); err == nil && val.Equal(bass.Bool(true)) {
+		return kw.String()
+	}
+
+	return val.String()
+}
+
+// write writes text to the output, first indenting if on a new line.
+func (f *formatter) write(text string) {
+	if text == "" {
+		return
+	}
+
+	if f.newline {
+		f.out.WriteString(strings.Repeat(" ", f.pending))
+		f.col = f.pending
+		f.newline = false
+	}
+
+	f.out.WriteString(text)
+
+	if idx := strings.LastIndex(text, "\n"); idx != -1 {
+		f.col = len([]rune(text[idx+1:]))
+	} else {
+		f.col += len([]rune(text))
+	}
+}
+
+// breakLine starts a new line, indenting any content written to it.
+func (f *formatter) breakLine(indent int) {
+	f.out.WriteString("\n")
+	f.col = 0
+	f.pending = indent
+	f.newline = true
+}
+
+// column returns the column that the next content will be written to.
+func (f *formatter) column() int {
+	if f.newline {
+		return f.pending
+	}
+
+	return f.col
+}
+
+// trailing writes a comment that follows a form on the same line.
+func (f *formatter) trailing(g gap) {
+	if g.trailing != "" {
+		f.write(" ")
+		f.write(g.trailing)
+	}
+}
+
+// commentLines writes comments and blank lines, each followed by a line
+// break.
+func (f *formatter) commentLines(lines []string, indent int) {
+	for _, line := range lines {
+		f.write(line)
+		f.breakLine(indent)
+	}
+}
+
+func hasComments(lines []string) bool {
+	for _, line := range lines {
+		if line != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trimBlanks removes blank lines from the start and/or end of the lines.
+func trimBlanks(lines []string, start, end bool) []string {
+	for start && len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+
+	for end && len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// isAtom returns true if the form is not a list, cons, bind, or quoted form.
+func isAtom(form bass.Annotate) bool {
+	if _, _, ok := quoted(form.Value); ok {
+		return false
+	}
+
+	_, _, ok := containerElems(form.Value)
+	return !ok
+}
+
+// quoted returns the prefix and inner form for forms read from `form,
+// ~form, and ~@form.
+func quoted(val bass.Value) (string, bass.Annotate, bool) {
+	pair, ok := val.(bass.Pair)
+	if !ok {
+		return "", bass.Annotate{}, false
+	}
+
+	sym, ok := pair.A.(bass.Symbol)
+	if !ok {
+		return "", bass.Annotate{}, false
+	}
+
+	var prefix string
+	switch sym {
+	case bass.QuasiquoteSymbol:
+		prefix = "`"
+	case bass.UnquoteSymbol:
+		prefix = "~"
+	case bass.UnquoteSplicingSymbol:
+		prefix = "~@"
+	default:
+		return "", bass.Annotate{}, false
+	}
+
+	rest, ok := pair.D.(bass.Pair)
+	if !ok {
+		return "", bass.Annotate{}, false
+	}
+
+	inner, ok := rest.A.(bass.Annotate)
+	if !ok {
+		return "", bass.Annotate{}, false
+	}
+
+	return prefix, inner, true
+}
+
+// containerElems returns the forms in a list, cons, or bind read from
+// source, along with the form following & in a list or cons.
+//
+// Values desugared by the reader, like foo:bar, are not containers since
+// their elements are not annotated.
+func containerElems(val bass.Value) ([]bass.Annotate, *bass.Annotate, bool) {
+	if bind, ok := val.(bass.Bind); ok {
+		if len(bind) == 0 {
+			return nil, nil, false
+		}
+
+		elems := make([]bass.Annotate, len(bind))
+		for i, v := range bind {
+			form, ok := v.(bass.Annotate)
+			if !ok {
+				return nil, nil, false
+			}
+
+			elems[i] = form
+		}
+
+		return elems, nil, true
+	}
+
+	return listElems(val)
+}
+
+// listElems returns the forms in a list or cons read from source.
+func listElems(val bass.Value) ([]bass.Annotate, *bass.Annotate, bool) {
+	var elems []bass.Annotate
+	for {
+		var a, d bass.Value
+		switch x := val.(type) {
+		case bass.Pair:
+			a, d = x.A, x.D
+		case bass.Cons:
+			a, d = x.A, x.D
+		case bass.Empty:
+			return elems, nil, len(elems) > 0
+		case bass.Annotate:
+			if len(elems) == 0 {
+				return nil, nil, false
+			}
+
+			return elems, &x, true
+		default:
+			return nil, nil, false
+		}
+
+		form, ok := a.(bass.Annotate)
+		if !ok {
+			return nil, nil, false
+		}
+
+		elems = append(elems, form)
+		val = d
+	}
+}
@@ -0,0 +1,233 @@
+package bassfmt_test
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/bassfmt"
+	"github.com/vito/bass/std"
+	"github.com/vito/is"
+)
+
+type FormatExample struct {
+	Name   string
+	Source string
+	Result string
+}
+
+func TestFormat(t *testing.T) {
+	for _, example := range []FormatExample{
+		{
+			Name:   "empty",
+			Source: "",
+			Result: "",
+		},
+		{
+			Name:   "whitespace",
+			Source: "  (foo   1\t2)  \n\n\n",
+			Result: "(foo 1 2)\n",
+		},
+		{
+			Name:   "atoms verbatim",
+			Source: `(foo 0x10 1.50 "a\nb" ./foo/bar :kw foo:bar *env*:ABC)`,
+			Result: "(foo 0x10 1.50 \"a\\nb\" ./foo/bar :kw foo:bar *env*:ABC)\n",
+		},
+		{
+			Name:   "multiline string",
+			Source: "(foo \"a\n  b\"\n  c)",
+			Result: "(foo \"a\n  b\"\n     c)\n",
+		},
+		{
+			Name:   "align with first argument",
+			Source: "(foo a\n b\n    c)",
+			Result: "(foo a\n     b\n     c)\n",
+		},
+		{
+			Name:   "combiner alone",
+			Source: "(foo\na\nb)",
+			Result: "(foo\n  a\n  b)\n",
+		},
+		{
+			Name:   "lispwords",
+			Source: "(defn foo [x]\n(if x\n:yes\n      :no))",
+			Result: "(defn foo [x]\n  (if x\n    :yes\n    :no))\n",
+		},
+		{
+			Name:   "def",
+			Source: "(def foo\n      (bar))",
+			Result: "(def foo\n  (bar))\n",
+		},
+		{
+			Name:   "indent meta",
+			Source: "^:indent\n(defn with-foo [x & body]\n  body)\n\n(with-foo 1\n(bar))",
+			Result: "^:indent\n(defn with-foo [x & body]\n  body)\n\n(with-foo 1\n  (bar))\n",
+		},
+		{
+			Name:   "non-atom head",
+			Source: "((foo) a\n b)",
+			Result: "((foo) a\n b)\n",
+		},
+		{
+			Name:   "cons and bind",
+			Source: "[a\n   b]\n{:a 1\n   :b 2}",
+			Result: "[a\n b]\n{:a 1\n :b 2}\n",
+		},
+		{
+			Name:   "nested alignment",
+			Source: "(let [a 1\n b 2]\n (+ a\n b))",
+			Result: "(let [a 1\n      b 2]\n  (+ a\n     b))\n",
+		},
+		{
+			Name:   "closing delimiters",
+			Source: "(foo\n  (bar)\n  )",
+			Result: "(foo\n  (bar))\n",
+		},
+		{
+			Name:   "blank lines",
+			Source: "(a)\n\n\n\n(b)\n(c)\n\n(provide [x]\n\n  (def x 1)\n\n\n  (def y 2))",
+			Result: "(a)\n\n(b)\n(c)\n\n(provide [x]\n\n  (def x 1)\n\n  (def y 2))\n",
+		},
+		{
+			Name:   "comments",
+			Source: "; hello\n;\n;   world\n(foo) ; trailing\n\n; detached\n\n(bar)\n; end\n",
+			Result: "; hello\n;\n;   world\n(foo) ; trailing\n\n; detached\n\n(bar)\n; end\n",
+		},
+		{
+			Name:   "nested comments",
+			Source: "(foo a   ; one\n; two\n  b)",
+			Result: "(foo a ; one\n     ; two\n     b)\n",
+		},
+		{
+			Name:   "comment after opening delimiter",
+			Source: "{; first\n :a 1}",
+			Result: "{; first\n :a 1}\n",
+		},
+		{
+			Name:   "comment before closing delimiter",
+			Source: "(foo a ; trailing\n)",
+			Result: "(foo a ; trailing\n     )\n",
+		},
+		{
+			Name:   "comment after lone closing delimiter",
+			Source: "(provide [x]\n  (def x 1)\n  ) ; provide\n(foo a\n  (bar)) ; not alone",
+			Result: "(provide [x]\n  (def x 1)\n  ) ; provide\n(foo a\n     (bar)) ; not alone\n",
+		},
+		{
+			Name:   "blank line before lone closing delimiter",
+			Source: "(provide [x]\n  (def x 1)\n\n\n  ) ; provide",
+			Result: "(provide [x]\n  (def x 1)\n\n  ) ; provide\n",
+		},
+		{
+			Name:   "only comments",
+			Source: "\n; a\n\n; b\n\n",
+			Result: "; a\n\n; b\n",
+		},
+		{
+			Name:   "shebang",
+			Source: "#!/usr/bin/env bass\n\n(foo)",
+			Result: "#!/usr/bin/env bass\n\n(foo)\n",
+		},
+		{
+			Name:   "meta shorthand",
+			Source: "(foo ^:a   ^:b x ^sym y ^\"str\" z)",
+			Result: "(foo ^:a ^:b x ^sym y ^\"str\" z)\n",
+		},
+		{
+			Name:   "meta bind",
+			Source: "(foo ^{:a 1   :b ./x} x)",
+			Result: "(foo ^{:a 1 :b ./x} x)\n",
+		},
+		{
+			Name:   "meta with comment-like string",
+			Source: "^{:doc \"a ; b\"}\n(def x 1)",
+			Result: "^{:doc \"a ; b\"}\n(def x 1)\n",
+		},
+		{
+			Name:   "pairs",
+			Source: "(a  &   b)\n[a\n & b]",
+			Result: "(a & b)\n[a\n & b]\n",
+		},
+		{
+			Name:   "quasiquote",
+			Source: "`(a ~b ~@c\n d)",
+			Result: "`(a ~b ~@c\n    d)\n",
+		},
+	} {
+		example := example
+		t.Run(example.Name, func(t *testing.T) {
+			is := is.New(t)
+
+			res, err := bassfmt.Format(bass.NewInMemoryFile("test", example.Source), []byte(example.Source))
+			is.NoErr(err)
+			is.Equal(string(res), example.Result)
+
+			again, err := bassfmt.Format(bass.NewInMemoryFile("test", string(res)), res)
+			is.NoErr(err)
+			is.Equal(string(again), example.Result)
+		})
+	}
+}
+
+func TestFormatReadError(t *testing.T) {
+	is := is.New(t)
+
+	_, err := bassfmt.Format(bass.NewInMemoryFile("test", "(foo"), []byte("(foo"))
+
+	var readErr bass.ReadError
+	is.True(errors.As(err, &readErr))
+}
+
+func TestFormatStd(t *testing.T) {
+	files, err := fs.Glob(std.FS, "*.bass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		file := file
+		t.Run(file, func(t *testing.T) {
+			is := is.New(t)
+
+			src, err := fs.ReadFile(std.FS, file)
+			is.NoErr(err)
+
+			res, err := bassfmt.Format(bass.NewInMemoryFile(file, string(src)), src)
+			is.NoErr(err)
+
+			again, err := bassfmt.Format(bass.NewInMemoryFile(file, string(res)), res)
+			is.NoErr(err)
+			is.Equal(string(again), string(res)) // stable
+
+			before := readAll(t, string(src))
+			after := readAll(t, string(res))
+			is.Equal(len(after), len(before))
+
+			for i := range before {
+				is.True(after[i].Equal(before[i]))
+				is.Equal(after[i].Comment, before[i].Comment)
+			}
+		})
+	}
+}
+
+func readAll(t *testing.T, src string) []bass.Annotate {
+	is := is.New(t)
+
+	reader := bass.NewReader(strings.NewReader(src), bass.NewInMemoryFile("test", src))
+
+	var forms []bass.Annotate
+	for {
+		form, err := reader.Next()
+		if err != nil {
+			is.Equal(err.Error(), "EOF")
+			break
+		}
+
+		forms = append(forms, form.(bass.Annotate))
+	}
+
+	return forms
+}
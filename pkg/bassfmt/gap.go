@@ -0,0 +1,167 @@
+package bassfmt
+
+import (
+	"strings"
+	"unicode"
+)
+
+// gap is the source between two tokens, which the reader mostly discards.
+type gap struct {
+	// A comment on the same line as the preceding token.
+	trailing string
+
+	// Comments on their own lines, in order, with "" for a blank line. Runs
+	// of blank lines are collapsed into one.
+	lines []string
+
+	// Whether the next token starts on a new line.
+	newline bool
+
+	// Whether the metadata preceding the next token is followed by a new
+	// line.
+	metaNewline bool
+}
+
+// gap scans the source between two offsets for comments and line breaks.
+//
+// afterToken is true if the gap follows a token on the same line, in which
+// case a comment on that line is a trailing comment.
+//
+// Metadata and the & delimiter are skipped since they are printed from the
+// forms read.
+func (f *formatter) gap(start, end int, afterToken bool) gap {
+	var g gap
+
+	if start > end {
+		return g
+	}
+
+	src := f.src[start:end]
+
+	sameLine := afterToken
+	lineEmpty := true
+
+	for i := 0; i < len(src); {
+		r := src[i]
+
+		switch {
+		case r == '\n':
+			if !sameLine && lineEmpty {
+				g.blank()
+			}
+
+			sameLine = false
+			lineEmpty = true
+			g.newline = true
+			g.metaNewline = true
+			i++
+
+		case r == ';' || (r == '#' && i+1 < len(src) && (src[i+1] == '!' || src[i+1] == ' ')):
+			eol := i
+			for eol < len(src) && src[eol] != '\n' {
+				eol++
+			}
+
+			comment := strings.TrimRightFunc(string(src[i:eol]), unicode.IsSpace)
+			if sameLine {
+				g.trailing = comment
+			} else {
+				g.lines = append(g.lines, comment)
+			}
+
+			lineEmpty = false
+			i = eol
+
+		case unicode.IsSpace(r) || r == ',':
+			i++
+
+		case r == '^':
+			i = skipForm(src, i+1)
+			g.metaNewline = false
+			lineEmpty = false
+
+		default:
+			// the & delimiter
+			i = skipForm(src, i)
+			lineEmpty = false
+		}
+	}
+
+	return g
+}
+
+// blank records a blank line.
+func (g *gap) blank() {
+	if len(g.lines) > 0 && g.lines[len(g.lines)-1] == "" {
+		return
+	}
+
+	g.lines = append(g.lines, "")
+}
+
+// skipForm returns the offset following the form starting at the given
+// offset, accounting for strings and comments within it.
+func skipForm(src []rune, i int) int {
+	if i >= len(src) {
+		return i
+	}
+
+	switch src[i] {
+	case '"':
+		return skipString(src, i)
+
+	case '^':
+		// metadata for metadata
+		return skipForm(src, skipForm(src, i+1))
+
+	case '(', '[', '{':
+		depth := 0
+		for i < len(src) {
+			switch src[i] {
+			case '(', '[', '{':
+				depth++
+				i++
+			case ')', ']', '}':
+				depth--
+				i++
+				if depth == 0 {
+					return i
+				}
+			case '"':
+				i = skipString(src, i)
+			case ';':
+				for i < len(src) && src[i] != '\n' {
+					i++
+				}
+			default:
+				i++
+			}
+		}
+
+		return i
+
+	default:
+		for i < len(src) && !isDelimiter(src[i]) {
+			i++
+		}
+
+		return i
+	}
+}
+
+func skipString(src []rune, i int) int {
+	for i++; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+
+	return i
+}
+
+func isDelimiter(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`,;"()[]{}`, r)
+}
@@ -86,10 +86,12 @@ func WithProgress(ctx context.Context, f func(context.Context) error) (err error
 	var stopRendering func()
 	if tape != nil && fancy {
 		defer cleanupRecorder()
+		ctx = bass.WithTerminal(ctx, openZoomedTerminal)
 		err = ProgressUI.Run(ctx, tape, func(ctx context.Context, ui progrock.UIClient) error {
 			return f(ctx)
 		})
 	} else {
+		ctx = bass.WithTerminal(ctx, openRawTerminal)
 		err = f(ctx)
 	}
 
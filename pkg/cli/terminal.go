@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/muesli/cancelreader"
+	"github.com/opencontainers/go-digest"
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/midterm"
+	"github.com/vito/progrock"
+	"golang.org/x/term"
+)
+
+// zoomedTerminal is a bass.Terminal displayed in a zoomed vertex of the
+// progress UI, so that it does not fight with the UI over the TTY.
+type zoomedTerminal struct {
+	io.Reader
+	io.Writer
+
+	stdinW  *io.PipeWriter
+	vtx     *progrock.VertexRecorder
+	resized chan bass.TerminalSize
+}
+
+func openZoomedTerminal(ctx context.Context, name string) (bass.Terminal, error) {
+	stdinR, stdinW := io.Pipe()
+
+	zt := &zoomedTerminal{
+		Reader:  stdinR,
+		stdinW:  stdinW,
+		resized: make(chan bass.TerminalSize, 1),
+	}
+
+	dig := digest.Digest(fmt.Sprintf("terminal:%d", time.Now().UnixNano()))
+	zt.vtx = progrock.FromContext(ctx).Vertex(dig, name, progrock.Zoomed(func(vt *midterm.Terminal) io.Writer {
+		vt.OnResize(func(rows, cols int) {
+			// only the latest size matters
+			select {
+			case <-zt.resized:
+			default:
+			}
+
+			zt.resized <- bass.TerminalSize{Rows: rows, Cols: cols}
+		})
+
+		return stdinW
+	}))
+
+	zt.Writer = zt.vtx.Stdout()
+
+	return zt, nil
+}
+
+func (zt *zoomedTerminal) Resized() <-chan bass.TerminalSize {
+	return zt.resized
+}
+
+func (zt *zoomedTerminal) Close() error {
+	zt.vtx.Done(nil)
+	return zt.stdinW.Close()
+}
+
+// rawTerminal is a bass.Terminal that uses the TTY directly, for when the
+// progress UI is not running.
+type rawTerminal struct {
+	io.Reader
+	io.Writer
+
+	fd      int
+	state   *term.State
+	stdin   cancelreader.CancelReader
+	resized chan bass.TerminalSize
+}
+
+func openRawTerminal(ctx context.Context, name string) (bass.Terminal, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("%s: stdin is not a terminal", name)
+	}
+
+	cols, rows, err := term.GetSize(fd)
+	if err != nil {
+		return nil, fmt.Errorf("get terminal size: %w", err)
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("make terminal raw: %w", err)
+	}
+
+	// NB: read through a cancelable reader so that the shell stops receiving
+	// input once it's closed, without leaving a read blocked on stdin
+	stdin, err := cancelreader.NewReader(os.Stdin)
+	if err != nil {
+		_ = term.Restore(fd, state)
+		return nil, fmt.Errorf("read stdin: %w", err)
+	}
+
+	resized := make(chan bass.TerminalSize, 1)
+	resized <- bass.TerminalSize{Rows: rows, Cols: cols}
+
+	return &rawTerminal{
+		Reader:  stdin,
+		Writer:  os.Stderr,
+		fd:      fd,
+		state:   state,
+		stdin:   stdin,
+		resized: resized,
+	}, nil
+}
+
+func (rt *rawTerminal) Resized() <-chan bass.TerminalSize {
+	return rt.resized
+}
+
+func (rt *rawTerminal) Close() error {
+	rt.stdin.Cancel()
+	_ = rt.stdin.Close()
+	return term.Restore(rt.fd, rt.state)
+}
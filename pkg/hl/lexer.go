@@ -67,11 +67,14 @@ func bassRules() Rules {
 		{`^#!.*$`, CommentPreproc, nil},
 		{`;.*$`, CommentSingle, nil},
 		{`[\s]+`, Text, nil},
+		{`-?\d+(\.\d+)?[eE][-+]?\d+`, LiteralNumberFloat, nil},
+		{`-?\d+\.\d+`, LiteralNumberFloat, nil},
 		{`-?\d+`, LiteralNumberInteger, nil},
 		{`0x-?[abcdef\d]+`, LiteralNumberHex, nil},
 		{`"(\\\\|\\"|[^"])*"`, LiteralString, nil},
 		{`:[` + symChars + `]+`, LiteralStringSymbol, nil},
 		{"&", Operator, nil},
+		{"~@|~|`", Operator, nil},
 	}
 
 	scope := bass.NewRunScope(bass.Ground, bass.RunState{})
@@ -59,6 +59,8 @@ func (analyzer *LexicalAnalyzer) Analyze(ctx context.Context, form bass.Annotate
 		analyzer.analyzeDefop(ctx, pair, form.Range)
 	case "provide":
 		analyzer.analyzeProvide(ctx, pair, form.Range)
+	case bass.QuasiquoteSymbol:
+		analyzer.analyzeQuasiquote(ctx, pair, form.Range)
 	}
 }
 
@@ -298,6 +300,97 @@ func (analyzer *LexicalAnalyzer) analyzeProvide(ctx context.Context, pair bass.P
 	analyzer.analyzeContainedBinding(ctx, rest.A)
 }
 
+// analyzeQuasiquote discards bindings analyzed from the quasiquoted template,
+// since the template is data rather than code. Bindings within unquoted forms
+// are kept.
+func (analyzer *LexicalAnalyzer) analyzeQuasiquote(ctx context.Context, pair bass.Pair, bounds bass.Range) {
+	logger := zapctx.FromContext(ctx)
+	logger.Debug("analyzing quasiquote")
+
+	var rest bass.Pair
+	if err := pair.D.Decode(&rest); err != nil {
+		logger.Error("rest is not a pair", zap.Error(err))
+		return
+	}
+
+	unquoted := unquotedRanges(rest.A, 0, nil)
+
+	isTemplate := func(loc bass.Range) bool {
+		if !loc.IsWithin(bounds) {
+			return false
+		}
+
+		for _, r := range unquoted {
+			if loc.IsWithin(r) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	bindings := []LexicalBinding{}
+	for _, b := range analyzer.Bindings {
+		if isTemplate(b.Location) {
+			logger.Debug("discarding quasiquoted binding", zap.Any("binding", b.Binding))
+			continue
+		}
+
+		bindings = append(bindings, b)
+	}
+
+	analyzer.Bindings = bindings
+
+	contained := []ContainedBinding{}
+	for _, b := range analyzer.Contained {
+		if isTemplate(b.Location) {
+			logger.Debug("discarding quasiquoted binding", zap.Any("binding", b.Binding))
+			continue
+		}
+
+		contained = append(contained, b)
+	}
+
+	analyzer.Contained = contained
+}
+
+// unquotedRanges collects the ranges of all (unquote) and (unquote-splicing)
+// forms within a quasiquoted form which are evaluated, i.e. those unquoted
+// out of any nested quasiquotes.
+func unquotedRanges(form bass.Value, depth int, ranges []bass.Range) []bass.Range {
+	switch x := form.(type) {
+	case bass.Annotate:
+		inner, unquote := bass.Unquoted(x.Value, bass.UnquoteSymbol)
+		if !unquote {
+			inner, unquote = bass.Unquoted(x.Value, bass.UnquoteSplicingSymbol)
+		}
+
+		if unquote {
+			if depth == 0 {
+				return append(ranges, x.Range)
+			}
+
+			return unquotedRanges(inner, depth-1, ranges)
+		}
+
+		if inner, ok := bass.Unquoted(x.Value, bass.QuasiquoteSymbol); ok {
+			return unquotedRanges(inner, depth+1, ranges)
+		}
+
+		return unquotedRanges(x.Value, depth, ranges)
+	case bass.Pair:
+		return unquotedRanges(x.D, depth, unquotedRanges(x.A, depth, ranges))
+	case bass.Cons:
+		return unquotedRanges(x.D, depth, unquotedRanges(x.A, depth, ranges))
+	case bass.Bind:
+		for _, v := range x {
+			ranges = unquotedRanges(v, depth, ranges)
+		}
+	}
+
+	return ranges
+}
+
 func (analyzer *LexicalAnalyzer) analyzeBinding(ctx context.Context, form bass.Value, bounds bass.Range) {
 	logger := zapctx.FromContext(ctx)
 
@@ -0,0 +1,320 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/zapctx"
+	"go.uber.org/zap"
+)
+
+var diagnosticSource = "bass"
+
+// evaluatedOperands maps well-known operatives to the number of leading
+// operands to skip before the rest are evaluated as code.
+//
+// Operands of any other operative are left alone, since they may be data or
+// introduce bindings that the analyzer doesn't know about.
+var evaluatedOperands = map[bass.Symbol]int{
+	"if":      0,
+	"do":      0,
+	"cond":    0,
+	"and":     0,
+	"or":      0,
+	"when":    0,
+	"assert":  0,
+	"refute":  0,
+	"def":     1,
+	"fn":      1,
+	"provide": 1,
+	"defn":    2,
+	"op":      2,
+	"defop":   3,
+}
+
+func (h *langHandler) publishDiagnostics(ctx context.Context, uri DocumentURI, version int, diagnostics []Diagnostic) {
+	if h.conn == nil {
+		return
+	}
+
+	if diagnostics == nil {
+		// clear any previously published diagnostics
+		diagnostics = []Diagnostic{}
+	}
+
+	err := h.conn.Notify(ctx, "textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diagnostics,
+		Version:     version,
+	})
+	if err != nil {
+		zapctx.FromContext(ctx).Error("failed to publish diagnostics", zap.Error(err))
+	}
+}
+
+func readErrorDiagnostic(err bass.ReadError) Diagnostic {
+	r := toRange(err.Range)
+
+	// the reader doesn't always know where an error ends
+	if r.End.Line < r.Start.Line || (r.End.Line == r.Start.Line && r.End.Character < r.Start.Character) {
+		r.End = r.Start
+	}
+
+	return Diagnostic{
+		Range:    r,
+		Severity: DiagnosticError,
+		Source:   &diagnosticSource,
+		Message:  err.Error(),
+	}
+}
+
+// linter checks forms for unbound symbols and calls to builtins with the
+// wrong number of arguments.
+type linter struct {
+	scope    *bass.Scope
+	analyzer *LexicalAnalyzer
+
+	// Whether to report unbound symbols. This is only accurate for forms
+	// which have been evaluated, since otherwise their definitions may not be
+	// in the scope.
+	unbound bool
+
+	diagnostics []Diagnostic
+}
+
+func (l *linter) form(val bass.Value, loc bass.Range) {
+	switch x := val.(type) {
+	case bass.Annotate:
+		l.form(x.Value, x.Range)
+	case bass.Symbol:
+		l.symbol(x, loc)
+	case bass.Pair:
+		l.call(x, loc)
+	case bass.Cons:
+		l.form(x.A, loc)
+		l.form(x.D, loc)
+	case bass.Bind:
+		for _, v := range x {
+			l.form(v, loc)
+		}
+	case bass.ExtendPath:
+		l.form(x.Parent, loc)
+	}
+}
+
+func (l *linter) forms(vals []bass.Value, rest bass.Value, loc bass.Range) {
+	for _, val := range vals {
+		l.form(val, loc)
+	}
+
+	if rest != nil {
+		l.form(rest, loc)
+	}
+}
+
+func (l *linter) call(pair bass.Pair, loc bass.Range) {
+	head, headLoc := pair.A, loc
+	if annotated, ok := head.(bass.Annotate); ok {
+		head, headLoc = annotated.Value, annotated.Range
+	}
+
+	args, rest := operands(pair.D)
+
+	sym, ok := head.(bass.Symbol)
+	if !ok {
+		// keyword access, paths, and other combiners evaluate their operands
+		l.form(pair.A, loc)
+		l.forms(args, rest, loc)
+		return
+	}
+
+	if l.isBound(sym, headLoc) {
+		// probably a function argument; assume it's applicative
+		l.forms(args, rest, loc)
+		return
+	}
+
+	switch sym {
+	case "let":
+		if len(args) > 0 {
+			bindings, _ := operands(args[0])
+			for i := 1; i < len(bindings); i += 2 {
+				l.form(bindings[i], loc)
+			}
+
+			l.forms(args[1:], rest, loc)
+		}
+
+		return
+	case bass.QuasiquoteSymbol:
+		l.forms(unquoted(pair.D, nil), nil, loc)
+		return
+	}
+
+	val, found := l.scope.Get(sym)
+	if !found {
+		// don't check the operands; they may not be evaluated
+		l.symbol(sym, headLoc)
+		return
+	}
+
+	var app bass.Applicative
+	if err := val.Decode(&app); err != nil {
+		skip, known := evaluatedOperands[sym]
+		if known && len(args) >= skip {
+			l.forms(args[skip:], rest, loc)
+		}
+
+		return
+	}
+
+	var builtin *bass.Builtin
+	if err := app.Unwrap().Decode(&builtin); err == nil && rest == nil {
+		need, variadic := builtin.Arity()
+		if (variadic && len(args) < need) || (!variadic && len(args) != need) {
+			l.diagnostics = append(l.diagnostics, Diagnostic{
+				Range:    toRange(loc),
+				Severity: DiagnosticError,
+				Source:   &diagnosticSource,
+				Message: bass.ArityError{
+					Name:     builtin.Name,
+					Need:     need,
+					Variadic: variadic,
+					Have:     len(args),
+				}.Error(),
+			})
+		}
+	}
+
+	l.forms(args, rest, loc)
+}
+
+func (l *linter) symbol(sym bass.Symbol, loc bass.Range) {
+	if !l.unbound {
+		return
+	}
+
+	if _, found := l.scope.Get(sym); found {
+		return
+	}
+
+	if l.isBound(sym, loc) || l.isContained(sym) {
+		return
+	}
+
+	unbound := bass.UnboundError{
+		Symbol: sym,
+		Scope:  l.scope,
+	}
+
+	msg := unbound.Error()
+	if similar := unbound.Similar(); len(similar) > 0 {
+		msg += fmt.Sprintf("\n\ndid you mean %s, perchance?", similar[0])
+	}
+
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Range:    toRange(loc),
+		Severity: DiagnosticWarning,
+		Source:   &diagnosticSource,
+		Message:  msg,
+	})
+}
+
+// isBound returns true if the symbol is bound by an enclosing form.
+func (l *linter) isBound(sym bass.Symbol, loc bass.Range) bool {
+	for _, b := range l.analyzer.Bindings {
+		if b.Binding == sym && loc.IsWithin(b.Bounds) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isContained returns true if the symbol is defined at the top level of the
+// file, though it may have failed to evaluate.
+func (l *linter) isContained(sym bass.Symbol) bool {
+	for _, b := range l.analyzer.Contained {
+		if b.Binding == sym {
+			return true
+		}
+	}
+
+	return false
+}
+
+// operands returns the elements of a list, and the rest of the list if it
+// does not end in an empty list.
+func operands(val bass.Value) ([]bass.Value, bass.Value) {
+	if annotated, ok := val.(bass.Annotate); ok {
+		if _, isList := annotated.Value.(bass.List); isList {
+			val = annotated.Value
+		}
+	}
+
+	var vals []bass.Value
+	for {
+		switch x := val.(type) {
+		case bass.Pair:
+			vals = append(vals, x.A)
+			val = x.D
+		case bass.Cons:
+			vals = append(vals, x.A)
+			val = x.D
+		case bass.Empty:
+			return vals, nil
+		default:
+			return vals, val
+		}
+	}
+}
+
+// unquoted collects the forms within (unquote) and (unquote-splicing) forms
+// in a quasiquoted form.
+func unquoted(form bass.Value, forms []bass.Value) []bass.Value {
+	switch x := form.(type) {
+	case bass.Annotate:
+		if val, ok := bass.Unquoted(x.Value, bass.UnquoteSymbol); ok {
+			return append(forms, val)
+		}
+
+		if val, ok := bass.Unquoted(x.Value, bass.UnquoteSplicingSymbol); ok {
+			return append(forms, val)
+		}
+
+		return unquoted(x.Value, forms)
+	case bass.Pair:
+		return unquoted(x.D, unquoted(x.A, forms))
+	case bass.Cons:
+		return unquoted(x.D, unquoted(x.A, forms))
+	case bass.Bind:
+		for _, v := range x {
+			forms = unquoted(v, forms)
+		}
+	}
+
+	return forms
+}
+
+func toRange(r bass.Range) Range {
+	start := Position{
+		Line:      r.Start.Ln - 1,
+		Character: r.Start.Col,
+	}
+
+	end := Position{
+		Line:      r.End.Ln - 1,
+		Character: r.End.Col,
+	}
+
+	if start.Line < 0 {
+		start = Position{}
+	}
+
+	if end.Line < 0 {
+		end = start
+	}
+
+	return Range{Start: start, End: end}
+}
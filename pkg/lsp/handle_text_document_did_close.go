@@ -17,7 +17,7 @@ func (h *langHandler) handleTextDocumentDidClose(ctx context.Context, conn *json
 		return nil, err
 	}
 
-	if err := h.closeFile(params.TextDocument.URI); err != nil {
+	if err := h.closeFile(ctx, params.TextDocument.URI); err != nil {
 		return nil, err
 	}
 	return nil, nil
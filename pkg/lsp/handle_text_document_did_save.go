@@ -20,7 +20,7 @@ func (h *langHandler) handleTextDocumentDidSave(ctx context.Context, conn *jsonr
 	if params.Text != nil {
 		err = h.updateFile(ctx, params.TextDocument.URI, *params.Text, nil)
 	} else {
-		err = h.saveFile(params.TextDocument.URI)
+		err = h.saveFile(ctx, params.TextDocument.URI)
 	}
 	if err != nil {
 		return nil, err
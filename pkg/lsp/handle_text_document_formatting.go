@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 
 	"github.com/sourcegraph/jsonrpc2"
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/bassfmt"
 )
 
 func (h *langHandler) handleTextDocumentFormatting(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (result any, err error) {
@@ -22,5 +25,23 @@ func (h *langHandler) handleTextDocumentFormatting(ctx context.Context, conn *js
 }
 
 func (h *langHandler) formatRequest(uri DocumentURI, opt FormattingOptions) ([]TextEdit, error) {
-	return nil, fmt.Errorf("not implemented")
+	f, ok := h.files[uri]
+	if !ok {
+		return nil, fmt.Errorf("document not found: %v", uri)
+	}
+
+	fp, err := fromURI(uri)
+	if err != nil {
+		return nil, fmt.Errorf("file path from URI: %w", err)
+	}
+
+	source := bass.NewHostPath(filepath.Dir(fp), bass.ParseFileOrDirPath(filepath.Base(fp)))
+
+	// bass has one canonical style, so the tab size and such are ignored
+	formatted, err := bassfmt.Format(source, []byte(f.Text))
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputeEdits(uri, f.Text, string(formatted)), nil
 }
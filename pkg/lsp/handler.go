@@ -171,13 +171,19 @@ func matchRootPath(fname string, markers []string) string {
 	return ""
 }
 
-func (h *langHandler) closeFile(uri DocumentURI) error {
+func (h *langHandler) closeFile(ctx context.Context, uri DocumentURI) error {
 	delete(h.files, uri)
+	h.publishDiagnostics(ctx, uri, 0, nil)
 	return nil
 }
 
-func (h *langHandler) saveFile(uri DocumentURI) error {
-	return nil
+func (h *langHandler) saveFile(ctx context.Context, uri DocumentURI) error {
+	f, ok := h.files[uri]
+	if !ok {
+		return fmt.Errorf("document not found: %v", uri)
+	}
+
+	return h.updateFile(ctx, uri, f.Text, nil)
 }
 
 func (h *langHandler) openFile(uri DocumentURI, languageID string, version int) error {
@@ -223,23 +229,63 @@ func (h *langHandler) updateFile(ctx context.Context, uri DocumentURI, text stri
 	reader.Analyzer = analyzer
 	reader.Context = ctx
 
+	var forms []bass.Annotate
+	var diagnostics []Diagnostic
+	checkUnbound := true
 	for {
-		_, err := reader.Next()
+		form, err := reader.Next()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				break
 			}
 
-			return fmt.Errorf("read next: %w", err)
+			var readErr bass.ReadError
+			if !errors.As(err, &readErr) {
+				return fmt.Errorf("read next: %w", err)
+			}
+
+			diagnostics = append(diagnostics, readErrorDiagnostic(readErr))
+			break
+		}
+
+		annotated, ok := form.(bass.Annotate)
+		if !ok {
+			return fmt.Errorf("read next: unannotated form: %s", form)
 		}
+
+		forms = append(forms, annotated)
 	}
 
-	_, err = bass.EvalString(ctx, scope, text, source)
-	if err != nil {
-		cli.WriteError(ctx, err)
-		logger.Error("eval failed (this is fine)")
+	// evaluate one form at a time, carrying on past errors so that later
+	// forms can be checked too
+	for _, form := range forms {
+		_, err := bass.Trampoline(ctx, form.Eval(ctx, scope, bass.Identity))
+		if err != nil {
+			cli.WriteError(ctx, err)
+			logger.Error("eval failed (this is fine)")
+
+			var unboundErr bass.UnboundError
+			var arityErr bass.ArityError
+			if !errors.As(err, &unboundErr) && !errors.As(err, &arityErr) {
+				// the form may have failed to define or import bindings that it
+				// or later forms use, so they can't be trusted to be unbound
+				checkUnbound = false
+			}
+		}
+
+		lint := &linter{
+			scope:    scope,
+			analyzer: analyzer,
+			unbound:  checkUnbound,
+		}
+
+		lint.form(form, form.Range)
+
+		diagnostics = append(diagnostics, lint.diagnostics...)
 	}
 
+	h.publishDiagnostics(ctx, uri, f.Version, diagnostics)
+
 	logger.Info("initialized scope")
 
 	return nil
@@ -187,6 +187,15 @@ type Diagnostic struct {
 	RelatedInformation []DiagnosticRelatedInformation `json:"relatedInformation,omitempty"`
 }
 
+// DiagnosticError is
+const (
+	_ = iota
+	DiagnosticError
+	DiagnosticWarning
+	DiagnosticInformation
+	DiagnosticHint
+)
+
 // PublishDiagnosticsParams is
 type PublishDiagnosticsParams struct {
 	URI         DocumentURI  `json:"uri"`
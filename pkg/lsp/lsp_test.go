@@ -38,6 +38,19 @@ func TestNeovimCompletion(t *testing.T) {
 	testFile(t, sandboxNvim(t), "testdata/complete.bass")
 }
 
+func TestNeovimDiagnostics(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+		return
+	}
+
+	if checkNested(t) {
+		return
+	}
+
+	testDiagnostics(t, sandboxNvim(t), "testdata/diagnostics.bass")
+}
+
 func checkNested(t *testing.T) bool {
 	if os.Getenv("NVIM") != "" {
 		t.Skip("detected running from neovim; skipping to avoid hanging")
@@ -148,6 +161,69 @@ func testFile(t *testing.T, client *nvim.Nvim, file string) {
 	}
 }
 
+type diagnostic struct {
+	Lnum    int    `msgpack:"lnum"`
+	Message string `msgpack:"message"`
+}
+
+// testDiagnostics checks the diagnostics published for each line with a
+// "; diagnostic: " comment, which must contain the given message, or be
+// "none".
+func testDiagnostics(t *testing.T, client *nvim.Nvim, file string) {
+	is := is.New(t)
+
+	err := client.Command(`edit ` + file)
+	is.NoErr(err)
+
+	testBuf, err := client.CurrentBuffer()
+	is.NoErr(err)
+
+	var diagnostics []diagnostic
+	is.Eventually(func() bool { // wait for diagnostics to be published
+		err := client.ExecLua(`return vim.diagnostic.get(0)`, &diagnostics)
+		return err == nil && len(diagnostics) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lines, err := client.BufferLines(testBuf, 0, -1, true)
+	is.NoErr(err)
+
+	for i, lineb := range lines {
+		segs := strings.Split(string(lineb), "; diagnostic: ")
+		if len(segs) < 2 {
+			continue
+		}
+
+		expected := strings.TrimSpace(segs[1])
+
+		var messages []string
+		for _, d := range diagnostics {
+			if d.Lnum == i {
+				messages = append(messages, d.Message)
+			}
+		}
+
+		if expected == "none" {
+			if len(messages) > 0 {
+				t.Errorf("L%03d: expected no diagnostics, got %q", i+1, messages)
+			}
+
+			continue
+		}
+
+		var found bool
+		for _, msg := range messages {
+			if strings.Contains(msg, expected) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("L%03d: expected diagnostic %q, got %q", i+1, expected, messages)
+		}
+	}
+}
+
 func sandboxNvim(t *testing.T) *nvim.Nvim {
 	is := is.New(t)
 
@@ -20,6 +20,55 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type NetworkMode int32
+
+const (
+	NetworkMode_NETWORK_MODE_DEFAULT NetworkMode = 0
+	NetworkMode_NETWORK_MODE_NONE    NetworkMode = 1
+	NetworkMode_NETWORK_MODE_HOST    NetworkMode = 2
+)
+
+// Enum value maps for NetworkMode.
+var (
+	NetworkMode_name = map[int32]string{
+		0: "NETWORK_MODE_DEFAULT",
+		1: "NETWORK_MODE_NONE",
+		2: "NETWORK_MODE_HOST",
+	}
+	NetworkMode_value = map[string]int32{
+		"NETWORK_MODE_DEFAULT": 0,
+		"NETWORK_MODE_NONE":    1,
+		"NETWORK_MODE_HOST":    2,
+	}
+)
+
+func (x NetworkMode) Enum() *NetworkMode {
+	p := new(NetworkMode)
+	*p = x
+	return p
+}
+
+func (x NetworkMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NetworkMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_bass_proto_enumTypes[0].Descriptor()
+}
+
+func (NetworkMode) Type() protoreflect.EnumType {
+	return &file_bass_proto_enumTypes[0]
+}
+
+func (x NetworkMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NetworkMode.Descriptor instead.
+func (NetworkMode) EnumDescriptor() ([]byte, []int) {
+	return file_bass_proto_rawDescGZIP(), []int{0}
+}
+
 type ConcurrencyMode int32
 
 const (
@@ -53,11 +102,11 @@ func (x ConcurrencyMode) String() string {
 }
 
 func (ConcurrencyMode) Descriptor() protoreflect.EnumDescriptor {
-	return file_bass_proto_enumTypes[0].Descriptor()
+	return file_bass_proto_enumTypes[1].Descriptor()
 }
 
 func (ConcurrencyMode) Type() protoreflect.EnumType {
-	return &file_bass_proto_enumTypes[0]
+	return &file_bass_proto_enumTypes[1]
 }
 
 func (x ConcurrencyMode) Number() protoreflect.EnumNumber {
@@ -66,7 +115,7 @@ func (x ConcurrencyMode) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ConcurrencyMode.Descriptor instead.
 func (ConcurrencyMode) EnumDescriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{0}
+	return file_bass_proto_rawDescGZIP(), []int{1}
 }
 
 type Value struct {
@@ -92,6 +141,7 @@ type Value struct {
 	//	*Value_LogicalPath
 	//	*Value_ThunkAddr
 	//	*Value_CachePath
+	//	*Value_Float
 	Value isValue_Value `protobuf_oneof:"value"`
 }
 
@@ -246,6 +296,13 @@ func (x *Value) GetCachePath() *CachePath {
 	return nil
 }
 
+func (x *Value) GetFloat() *Float {
+	if x, ok := x.GetValue().(*Value_Float); ok {
+		return x.Float
+	}
+	return nil
+}
+
 type isValue_Value interface {
 	isValue_Value()
 }
@@ -314,6 +371,10 @@ type Value_CachePath struct {
 	CachePath *CachePath `protobuf:"bytes,16,opt,name=cache_path,json=cachePath,proto3,oneof"`
 }
 
+type Value_Float struct {
+	Float *Float `protobuf:"bytes,17,opt,name=float,proto3,oneof"`
+}
+
 func (*Value_Null) isValue_Value() {}
 
 func (*Value_Bool) isValue_Value() {}
@@ -346,26 +407,34 @@ func (*Value_ThunkAddr) isValue_Value() {}
 
 func (*Value_CachePath) isValue_Value() {}
 
+func (*Value_Float) isValue_Value() {}
+
 type Thunk struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Image            *ThunkImage   `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
-	Insecure         bool          `protobuf:"varint,2,opt,name=insecure,proto3" json:"insecure,omitempty"`
-	Args             []*Value      `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty"`
-	Stdin            []*Value      `protobuf:"bytes,5,rep,name=stdin,proto3" json:"stdin,omitempty"`
-	Env              []*Binding    `protobuf:"bytes,6,rep,name=env,proto3" json:"env,omitempty"`
-	Dir              *ThunkDir     `protobuf:"bytes,7,opt,name=dir,proto3" json:"dir,omitempty"`
-	Mounts           []*ThunkMount `protobuf:"bytes,8,rep,name=mounts,proto3" json:"mounts,omitempty"`
-	Labels           []*Binding    `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty"`
-	Ports            []*ThunkPort  `protobuf:"bytes,10,rep,name=ports,proto3" json:"ports,omitempty"`
-	Tls              *ThunkTLS     `protobuf:"bytes,11,opt,name=tls,proto3" json:"tls,omitempty"`
-	Entrypoint       []string      `protobuf:"bytes,12,rep,name=entrypoint,proto3" json:"entrypoint,omitempty"`
-	ClearEntrypoint  bool          `protobuf:"varint,13,opt,name=clear_entrypoint,json=clearEntrypoint,proto3" json:"clear_entrypoint,omitempty"`
-	DefaultArgs      []string      `protobuf:"bytes,14,rep,name=default_args,json=defaultArgs,proto3" json:"default_args,omitempty"`
-	ClearDefaultArgs bool          `protobuf:"varint,15,opt,name=clear_default_args,json=clearDefaultArgs,proto3" json:"clear_default_args,omitempty"`
-	UseEntrypoint    bool          `protobuf:"varint,16,opt,name=use_entrypoint,json=useEntrypoint,proto3" json:"use_entrypoint,omitempty"`
+	Image            *ThunkImage       `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Insecure         bool              `protobuf:"varint,2,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	Args             []*Value          `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty"`
+	Stdin            []*Value          `protobuf:"bytes,5,rep,name=stdin,proto3" json:"stdin,omitempty"`
+	Env              []*Binding        `protobuf:"bytes,6,rep,name=env,proto3" json:"env,omitempty"`
+	Dir              *ThunkDir         `protobuf:"bytes,7,opt,name=dir,proto3" json:"dir,omitempty"`
+	Mounts           []*ThunkMount     `protobuf:"bytes,8,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	Labels           []*Binding        `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty"`
+	Ports            []*ThunkPort      `protobuf:"bytes,10,rep,name=ports,proto3" json:"ports,omitempty"`
+	Tls              *ThunkTLS         `protobuf:"bytes,11,opt,name=tls,proto3" json:"tls,omitempty"`
+	Entrypoint       []string          `protobuf:"bytes,12,rep,name=entrypoint,proto3" json:"entrypoint,omitempty"`
+	ClearEntrypoint  bool              `protobuf:"varint,13,opt,name=clear_entrypoint,json=clearEntrypoint,proto3" json:"clear_entrypoint,omitempty"`
+	DefaultArgs      []string          `protobuf:"bytes,14,rep,name=default_args,json=defaultArgs,proto3" json:"default_args,omitempty"`
+	ClearDefaultArgs bool              `protobuf:"varint,15,opt,name=clear_default_args,json=clearDefaultArgs,proto3" json:"clear_default_args,omitempty"`
+	UseEntrypoint    bool              `protobuf:"varint,16,opt,name=use_entrypoint,json=useEntrypoint,proto3" json:"use_entrypoint,omitempty"`
+	TimeoutNs        int64             `protobuf:"varint,17,opt,name=timeout_ns,json=timeoutNs,proto3" json:"timeout_ns,omitempty"`
+	Retry            *ThunkRetry       `protobuf:"bytes,18,opt,name=retry,proto3" json:"retry,omitempty"`
+	Resources        *ThunkResources   `protobuf:"bytes,19,opt,name=resources,proto3" json:"resources,omitempty"`
+	Network          NetworkMode       `protobuf:"varint,20,opt,name=network,proto3,enum=bass.NetworkMode" json:"network,omitempty"`
+	User             string            `protobuf:"bytes,21,opt,name=user,proto3" json:"user,omitempty"`
+	ImageConfig      *ThunkImageConfig `protobuf:"bytes,22,opt,name=image_config,json=imageConfig,proto3" json:"image_config,omitempty"`
 }
 
 func (x *Thunk) Reset() {
@@ -505,6 +574,48 @@ func (x *Thunk) GetUseEntrypoint() bool {
 	return false
 }
 
+func (x *Thunk) GetTimeoutNs() int64 {
+	if x != nil {
+		return x.TimeoutNs
+	}
+	return 0
+}
+
+func (x *Thunk) GetRetry() *ThunkRetry {
+	if x != nil {
+		return x.Retry
+	}
+	return nil
+}
+
+func (x *Thunk) GetResources() *ThunkResources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *Thunk) GetNetwork() NetworkMode {
+	if x != nil {
+		return x.Network
+	}
+	return NetworkMode_NETWORK_MODE_DEFAULT
+}
+
+func (x *Thunk) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Thunk) GetImageConfig() *ThunkImageConfig {
+	if x != nil {
+		return x.ImageConfig
+	}
+	return nil
+}
+
 type ThunkAddr struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -678,6 +789,290 @@ func (x *ThunkTLS) GetKey() *FilePath {
 	return nil
 }
 
+type ThunkRetry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Attempts  int32 `protobuf:"varint,1,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	BackoffNs int64 `protobuf:"varint,2,opt,name=backoff_ns,json=backoffNs,proto3" json:"backoff_ns,omitempty"`
+}
+
+func (x *ThunkRetry) Reset() {
+	*x = ThunkRetry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bass_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ThunkRetry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThunkRetry) ProtoMessage() {}
+
+func (x *ThunkRetry) ProtoReflect() protoreflect.Message {
+	mi := &file_bass_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThunkRetry.ProtoReflect.Descriptor instead.
+func (*ThunkRetry) Descriptor() ([]byte, []int) {
+	return file_bass_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ThunkRetry) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *ThunkRetry) GetBackoffNs() int64 {
+	if x != nil {
+		return x.BackoffNs
+	}
+	return 0
+}
+
+type ThunkResources struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cpu    float64 `protobuf:"fixed64,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Memory int64   `protobuf:"varint,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	Pids   int64   `protobuf:"varint,3,opt,name=pids,proto3" json:"pids,omitempty"`
+}
+
+func (x *ThunkResources) Reset() {
+	*x = ThunkResources{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bass_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ThunkResources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThunkResources) ProtoMessage() {}
+
+func (x *ThunkResources) ProtoReflect() protoreflect.Message {
+	mi := &file_bass_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThunkResources.ProtoReflect.Descriptor instead.
+func (*ThunkResources) Descriptor() ([]byte, []int) {
+	return file_bass_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ThunkResources) GetCpu() float64 {
+	if x != nil {
+		return x.Cpu
+	}
+	return 0
+}
+
+func (x *ThunkResources) GetMemory() int64 {
+	if x != nil {
+		return x.Memory
+	}
+	return 0
+}
+
+func (x *ThunkResources) GetPids() int64 {
+	if x != nil {
+		return x.Pids
+	}
+	return 0
+}
+
+type ThunkImageConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WorkingDir  string            `protobuf:"bytes,1,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`
+	Annotations []*Binding        `protobuf:"bytes,2,rep,name=annotations,proto3" json:"annotations,omitempty"`
+	StopSignal  string            `protobuf:"bytes,3,opt,name=stop_signal,json=stopSignal,proto3" json:"stop_signal,omitempty"`
+	Healthcheck *ThunkHealthcheck `protobuf:"bytes,4,opt,name=healthcheck,proto3" json:"healthcheck,omitempty"`
+	Volumes     []string          `protobuf:"bytes,5,rep,name=volumes,proto3" json:"volumes,omitempty"`
+	Labels      []*Binding        `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty"`
+}
+
+func (x *ThunkImageConfig) Reset() {
+	*x = ThunkImageConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bass_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ThunkImageConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThunkImageConfig) ProtoMessage() {}
+
+func (x *ThunkImageConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_bass_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThunkImageConfig.ProtoReflect.Descriptor instead.
+func (*ThunkImageConfig) Descriptor() ([]byte, []int) {
+	return file_bass_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ThunkImageConfig) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+func (x *ThunkImageConfig) GetAnnotations() []*Binding {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *ThunkImageConfig) GetStopSignal() string {
+	if x != nil {
+		return x.StopSignal
+	}
+	return ""
+}
+
+func (x *ThunkImageConfig) GetHealthcheck() *ThunkHealthcheck {
+	if x != nil {
+		return x.Healthcheck
+	}
+	return nil
+}
+
+func (x *ThunkImageConfig) GetVolumes() []string {
+	if x != nil {
+		return x.Volumes
+	}
+	return nil
+}
+
+func (x *ThunkImageConfig) GetLabels() []*Binding {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type ThunkHealthcheck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Command       []string `protobuf:"bytes,1,rep,name=command,proto3" json:"command,omitempty"`
+	IntervalNs    int64    `protobuf:"varint,2,opt,name=interval_ns,json=intervalNs,proto3" json:"interval_ns,omitempty"`
+	TimeoutNs     int64    `protobuf:"varint,3,opt,name=timeout_ns,json=timeoutNs,proto3" json:"timeout_ns,omitempty"`
+	StartPeriodNs int64    `protobuf:"varint,4,opt,name=start_period_ns,json=startPeriodNs,proto3" json:"start_period_ns,omitempty"`
+	Retries       int32    `protobuf:"varint,5,opt,name=retries,proto3" json:"retries,omitempty"`
+}
+
+func (x *ThunkHealthcheck) Reset() {
+	*x = ThunkHealthcheck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bass_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ThunkHealthcheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThunkHealthcheck) ProtoMessage() {}
+
+func (x *ThunkHealthcheck) ProtoReflect() protoreflect.Message {
+	mi := &file_bass_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThunkHealthcheck.ProtoReflect.Descriptor instead.
+func (*ThunkHealthcheck) Descriptor() ([]byte, []int) {
+	return file_bass_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ThunkHealthcheck) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *ThunkHealthcheck) GetIntervalNs() int64 {
+	if x != nil {
+		return x.IntervalNs
+	}
+	return 0
+}
+
+func (x *ThunkHealthcheck) GetTimeoutNs() int64 {
+	if x != nil {
+		return x.TimeoutNs
+	}
+	return 0
+}
+
+func (x *ThunkHealthcheck) GetStartPeriodNs() int64 {
+	if x != nil {
+		return x.StartPeriodNs
+	}
+	return 0
+}
+
+func (x *ThunkHealthcheck) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
 type ThunkImage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -695,7 +1090,7 @@ type ThunkImage struct {
 func (x *ThunkImage) Reset() {
 	*x = ThunkImage{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[5]
+		mi := &file_bass_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -708,7 +1103,7 @@ func (x *ThunkImage) String() string {
 func (*ThunkImage) ProtoMessage() {}
 
 func (x *ThunkImage) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[5]
+	mi := &file_bass_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -721,7 +1116,7 @@ func (x *ThunkImage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ThunkImage.ProtoReflect.Descriptor instead.
 func (*ThunkImage) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{5}
+	return file_bass_proto_rawDescGZIP(), []int{9}
 }
 
 func (m *ThunkImage) GetImage() isThunkImage_Image {
@@ -806,7 +1201,7 @@ type ImageRef struct {
 func (x *ImageRef) Reset() {
 	*x = ImageRef{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[6]
+		mi := &file_bass_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -819,7 +1214,7 @@ func (x *ImageRef) String() string {
 func (*ImageRef) ProtoMessage() {}
 
 func (x *ImageRef) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[6]
+	mi := &file_bass_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -832,7 +1227,7 @@ func (x *ImageRef) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImageRef.ProtoReflect.Descriptor instead.
 func (*ImageRef) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{6}
+	return file_bass_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ImageRef) GetPlatform() *Platform {
@@ -922,7 +1317,7 @@ type ImageArchive struct {
 func (x *ImageArchive) Reset() {
 	*x = ImageArchive{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[7]
+		mi := &file_bass_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -935,7 +1330,7 @@ func (x *ImageArchive) String() string {
 func (*ImageArchive) ProtoMessage() {}
 
 func (x *ImageArchive) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[7]
+	mi := &file_bass_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -948,7 +1343,7 @@ func (x *ImageArchive) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImageArchive.ProtoReflect.Descriptor instead.
 func (*ImageArchive) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{7}
+	return file_bass_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ImageArchive) GetPlatform() *Platform {
@@ -987,7 +1382,7 @@ type ImageDockerBuild struct {
 func (x *ImageDockerBuild) Reset() {
 	*x = ImageDockerBuild{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[8]
+		mi := &file_bass_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1000,7 +1395,7 @@ func (x *ImageDockerBuild) String() string {
 func (*ImageDockerBuild) ProtoMessage() {}
 
 func (x *ImageDockerBuild) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[8]
+	mi := &file_bass_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1013,7 +1408,7 @@ func (x *ImageDockerBuild) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImageDockerBuild.ProtoReflect.Descriptor instead.
 func (*ImageDockerBuild) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{8}
+	return file_bass_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ImageDockerBuild) GetPlatform() *Platform {
@@ -1067,7 +1462,7 @@ type ImageBuildInput struct {
 func (x *ImageBuildInput) Reset() {
 	*x = ImageBuildInput{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[9]
+		mi := &file_bass_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1080,7 +1475,7 @@ func (x *ImageBuildInput) String() string {
 func (*ImageBuildInput) ProtoMessage() {}
 
 func (x *ImageBuildInput) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[9]
+	mi := &file_bass_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1093,7 +1488,7 @@ func (x *ImageBuildInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImageBuildInput.ProtoReflect.Descriptor instead.
 func (*ImageBuildInput) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{9}
+	return file_bass_proto_rawDescGZIP(), []int{13}
 }
 
 func (m *ImageBuildInput) GetInput() isImageBuildInput_Input {
@@ -1158,7 +1553,7 @@ type BuildArg struct {
 func (x *BuildArg) Reset() {
 	*x = BuildArg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[10]
+		mi := &file_bass_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1171,7 +1566,7 @@ func (x *BuildArg) String() string {
 func (*BuildArg) ProtoMessage() {}
 
 func (x *BuildArg) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[10]
+	mi := &file_bass_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1184,7 +1579,7 @@ func (x *BuildArg) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BuildArg.ProtoReflect.Descriptor instead.
 func (*BuildArg) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{10}
+	return file_bass_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *BuildArg) GetName() string {
@@ -1213,7 +1608,7 @@ type Platform struct {
 func (x *Platform) Reset() {
 	*x = Platform{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[11]
+		mi := &file_bass_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1226,7 +1621,7 @@ func (x *Platform) String() string {
 func (*Platform) ProtoMessage() {}
 
 func (x *Platform) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[11]
+	mi := &file_bass_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1239,7 +1634,7 @@ func (x *Platform) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Platform.ProtoReflect.Descriptor instead.
 func (*Platform) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{11}
+	return file_bass_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *Platform) GetOs() string {
@@ -1272,7 +1667,7 @@ type ThunkDir struct {
 func (x *ThunkDir) Reset() {
 	*x = ThunkDir{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[12]
+		mi := &file_bass_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1285,7 +1680,7 @@ func (x *ThunkDir) String() string {
 func (*ThunkDir) ProtoMessage() {}
 
 func (x *ThunkDir) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[12]
+	mi := &file_bass_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1298,7 +1693,7 @@ func (x *ThunkDir) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ThunkDir.ProtoReflect.Descriptor instead.
 func (*ThunkDir) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{12}
+	return file_bass_proto_rawDescGZIP(), []int{16}
 }
 
 func (m *ThunkDir) GetDir() isThunkDir_Dir {
@@ -1369,7 +1764,7 @@ type ThunkMountSource struct {
 func (x *ThunkMountSource) Reset() {
 	*x = ThunkMountSource{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[13]
+		mi := &file_bass_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1382,7 +1777,7 @@ func (x *ThunkMountSource) String() string {
 func (*ThunkMountSource) ProtoMessage() {}
 
 func (x *ThunkMountSource) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[13]
+	mi := &file_bass_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1395,7 +1790,7 @@ func (x *ThunkMountSource) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ThunkMountSource.ProtoReflect.Descriptor instead.
 func (*ThunkMountSource) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{13}
+	return file_bass_proto_rawDescGZIP(), []int{17}
 }
 
 func (m *ThunkMountSource) GetSource() isThunkMountSource_Source {
@@ -1486,7 +1881,7 @@ type ThunkMount struct {
 func (x *ThunkMount) Reset() {
 	*x = ThunkMount{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[14]
+		mi := &file_bass_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1499,7 +1894,7 @@ func (x *ThunkMount) String() string {
 func (*ThunkMount) ProtoMessage() {}
 
 func (x *ThunkMount) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[14]
+	mi := &file_bass_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1512,7 +1907,7 @@ func (x *ThunkMount) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ThunkMount.ProtoReflect.Descriptor instead.
 func (*ThunkMount) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{14}
+	return file_bass_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *ThunkMount) GetSource() *ThunkMountSource {
@@ -1540,7 +1935,7 @@ type Array struct {
 func (x *Array) Reset() {
 	*x = Array{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[15]
+		mi := &file_bass_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1553,7 +1948,7 @@ func (x *Array) String() string {
 func (*Array) ProtoMessage() {}
 
 func (x *Array) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[15]
+	mi := &file_bass_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1566,7 +1961,7 @@ func (x *Array) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Array.ProtoReflect.Descriptor instead.
 func (*Array) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{15}
+	return file_bass_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *Array) GetValues() []*Value {
@@ -1587,7 +1982,7 @@ type Object struct {
 func (x *Object) Reset() {
 	*x = Object{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[16]
+		mi := &file_bass_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1600,7 +1995,7 @@ func (x *Object) String() string {
 func (*Object) ProtoMessage() {}
 
 func (x *Object) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[16]
+	mi := &file_bass_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1613,7 +2008,7 @@ func (x *Object) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Object.ProtoReflect.Descriptor instead.
 func (*Object) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{16}
+	return file_bass_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *Object) GetBindings() []*Binding {
@@ -1635,7 +2030,7 @@ type Binding struct {
 func (x *Binding) Reset() {
 	*x = Binding{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[17]
+		mi := &file_bass_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1648,7 +2043,7 @@ func (x *Binding) String() string {
 func (*Binding) ProtoMessage() {}
 
 func (x *Binding) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[17]
+	mi := &file_bass_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1661,7 +2056,7 @@ func (x *Binding) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Binding.ProtoReflect.Descriptor instead.
 func (*Binding) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{17}
+	return file_bass_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *Binding) GetSymbol() string {
@@ -1687,7 +2082,7 @@ type Null struct {
 func (x *Null) Reset() {
 	*x = Null{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[18]
+		mi := &file_bass_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1700,7 +2095,7 @@ func (x *Null) String() string {
 func (*Null) ProtoMessage() {}
 
 func (x *Null) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[18]
+	mi := &file_bass_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1713,7 +2108,7 @@ func (x *Null) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Null.ProtoReflect.Descriptor instead.
 func (*Null) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{18}
+	return file_bass_proto_rawDescGZIP(), []int{22}
 }
 
 type Bool struct {
@@ -1727,7 +2122,7 @@ type Bool struct {
 func (x *Bool) Reset() {
 	*x = Bool{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[19]
+		mi := &file_bass_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1740,7 +2135,7 @@ func (x *Bool) String() string {
 func (*Bool) ProtoMessage() {}
 
 func (x *Bool) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[19]
+	mi := &file_bass_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1753,7 +2148,7 @@ func (x *Bool) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Bool.ProtoReflect.Descriptor instead.
 func (*Bool) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{19}
+	return file_bass_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *Bool) GetValue() bool {
@@ -1774,7 +2169,7 @@ type Int struct {
 func (x *Int) Reset() {
 	*x = Int{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[20]
+		mi := &file_bass_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1787,7 +2182,7 @@ func (x *Int) String() string {
 func (*Int) ProtoMessage() {}
 
 func (x *Int) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[20]
+	mi := &file_bass_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1800,7 +2195,7 @@ func (x *Int) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Int.ProtoReflect.Descriptor instead.
 func (*Int) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{20}
+	return file_bass_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *Int) GetValue() int64 {
@@ -1810,6 +2205,53 @@ func (x *Int) GetValue() int64 {
 	return 0
 }
 
+type Float struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value float64 `protobuf:"fixed64,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Float) Reset() {
+	*x = Float{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bass_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Float) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Float) ProtoMessage() {}
+
+func (x *Float) ProtoReflect() protoreflect.Message {
+	mi := &file_bass_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Float.ProtoReflect.Descriptor instead.
+func (*Float) Descriptor() ([]byte, []int) {
+	return file_bass_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Float) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
 type String struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1821,7 +2263,7 @@ type String struct {
 func (x *String) Reset() {
 	*x = String{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[21]
+		mi := &file_bass_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1834,7 +2276,7 @@ func (x *String) String() string {
 func (*String) ProtoMessage() {}
 
 func (x *String) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[21]
+	mi := &file_bass_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1847,7 +2289,7 @@ func (x *String) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use String.ProtoReflect.Descriptor instead.
 func (*String) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{21}
+	return file_bass_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *String) GetValue() string {
@@ -1870,7 +2312,7 @@ type CachePath struct {
 func (x *CachePath) Reset() {
 	*x = CachePath{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[22]
+		mi := &file_bass_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1883,7 +2325,7 @@ func (x *CachePath) String() string {
 func (*CachePath) ProtoMessage() {}
 
 func (x *CachePath) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[22]
+	mi := &file_bass_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1896,7 +2338,7 @@ func (x *CachePath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CachePath.ProtoReflect.Descriptor instead.
 func (*CachePath) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{22}
+	return file_bass_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *CachePath) GetId() string {
@@ -1931,7 +2373,7 @@ type Secret struct {
 func (x *Secret) Reset() {
 	*x = Secret{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[23]
+		mi := &file_bass_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1944,7 +2386,7 @@ func (x *Secret) String() string {
 func (*Secret) ProtoMessage() {}
 
 func (x *Secret) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[23]
+	mi := &file_bass_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1957,7 +2399,7 @@ func (x *Secret) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Secret.ProtoReflect.Descriptor instead.
 func (*Secret) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{23}
+	return file_bass_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *Secret) GetName() string {
@@ -1978,7 +2420,7 @@ type CommandPath struct {
 func (x *CommandPath) Reset() {
 	*x = CommandPath{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[24]
+		mi := &file_bass_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1991,7 +2433,7 @@ func (x *CommandPath) String() string {
 func (*CommandPath) ProtoMessage() {}
 
 func (x *CommandPath) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[24]
+	mi := &file_bass_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2004,7 +2446,7 @@ func (x *CommandPath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandPath.ProtoReflect.Descriptor instead.
 func (*CommandPath) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{24}
+	return file_bass_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *CommandPath) GetName() string {
@@ -2025,7 +2467,7 @@ type FilePath struct {
 func (x *FilePath) Reset() {
 	*x = FilePath{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[25]
+		mi := &file_bass_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2038,7 +2480,7 @@ func (x *FilePath) String() string {
 func (*FilePath) ProtoMessage() {}
 
 func (x *FilePath) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[25]
+	mi := &file_bass_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2051,7 +2493,7 @@ func (x *FilePath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FilePath.ProtoReflect.Descriptor instead.
 func (*FilePath) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{25}
+	return file_bass_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *FilePath) GetPath() string {
@@ -2074,7 +2516,7 @@ type DirPath struct {
 func (x *DirPath) Reset() {
 	*x = DirPath{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[26]
+		mi := &file_bass_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2087,7 +2529,7 @@ func (x *DirPath) String() string {
 func (*DirPath) ProtoMessage() {}
 
 func (x *DirPath) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[26]
+	mi := &file_bass_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2100,7 +2542,7 @@ func (x *DirPath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DirPath.ProtoReflect.Descriptor instead.
 func (*DirPath) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{26}
+	return file_bass_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *DirPath) GetPath() string {
@@ -2139,7 +2581,7 @@ type FilesystemPath struct {
 func (x *FilesystemPath) Reset() {
 	*x = FilesystemPath{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[27]
+		mi := &file_bass_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2152,7 +2594,7 @@ func (x *FilesystemPath) String() string {
 func (*FilesystemPath) ProtoMessage() {}
 
 func (x *FilesystemPath) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[27]
+	mi := &file_bass_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2165,7 +2607,7 @@ func (x *FilesystemPath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FilesystemPath.ProtoReflect.Descriptor instead.
 func (*FilesystemPath) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{27}
+	return file_bass_proto_rawDescGZIP(), []int{32}
 }
 
 func (m *FilesystemPath) GetPath() isFilesystemPath_Path {
@@ -2217,7 +2659,7 @@ type ThunkPath struct {
 func (x *ThunkPath) Reset() {
 	*x = ThunkPath{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[28]
+		mi := &file_bass_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2230,7 +2672,7 @@ func (x *ThunkPath) String() string {
 func (*ThunkPath) ProtoMessage() {}
 
 func (x *ThunkPath) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[28]
+	mi := &file_bass_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2243,7 +2685,7 @@ func (x *ThunkPath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ThunkPath.ProtoReflect.Descriptor instead.
 func (*ThunkPath) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{28}
+	return file_bass_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *ThunkPath) GetThunk() *Thunk {
@@ -2272,7 +2714,7 @@ type HostPath struct {
 func (x *HostPath) Reset() {
 	*x = HostPath{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[29]
+		mi := &file_bass_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2285,7 +2727,7 @@ func (x *HostPath) String() string {
 func (*HostPath) ProtoMessage() {}
 
 func (x *HostPath) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[29]
+	mi := &file_bass_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2298,7 +2740,7 @@ func (x *HostPath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostPath.ProtoReflect.Descriptor instead.
 func (*HostPath) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{29}
+	return file_bass_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *HostPath) GetContext() string {
@@ -2330,7 +2772,7 @@ type LogicalPath struct {
 func (x *LogicalPath) Reset() {
 	*x = LogicalPath{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[30]
+		mi := &file_bass_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2343,7 +2785,7 @@ func (x *LogicalPath) String() string {
 func (*LogicalPath) ProtoMessage() {}
 
 func (x *LogicalPath) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[30]
+	mi := &file_bass_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2356,7 +2798,7 @@ func (x *LogicalPath) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogicalPath.ProtoReflect.Descriptor instead.
 func (*LogicalPath) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{30}
+	return file_bass_proto_rawDescGZIP(), []int{35}
 }
 
 func (m *LogicalPath) GetPath() isLogicalPath_Path {
@@ -2408,7 +2850,7 @@ type LogicalPath_File struct {
 func (x *LogicalPath_File) Reset() {
 	*x = LogicalPath_File{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[31]
+		mi := &file_bass_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2421,7 +2863,7 @@ func (x *LogicalPath_File) String() string {
 func (*LogicalPath_File) ProtoMessage() {}
 
 func (x *LogicalPath_File) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[31]
+	mi := &file_bass_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2434,7 +2876,7 @@ func (x *LogicalPath_File) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogicalPath_File.ProtoReflect.Descriptor instead.
 func (*LogicalPath_File) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{30, 0}
+	return file_bass_proto_rawDescGZIP(), []int{35, 0}
 }
 
 func (x *LogicalPath_File) GetName() string {
@@ -2463,7 +2905,7 @@ type LogicalPath_Dir struct {
 func (x *LogicalPath_Dir) Reset() {
 	*x = LogicalPath_Dir{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bass_proto_msgTypes[32]
+		mi := &file_bass_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2476,7 +2918,7 @@ func (x *LogicalPath_Dir) String() string {
 func (*LogicalPath_Dir) ProtoMessage() {}
 
 func (x *LogicalPath_Dir) ProtoReflect() protoreflect.Message {
-	mi := &file_bass_proto_msgTypes[32]
+	mi := &file_bass_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2489,7 +2931,7 @@ func (x *LogicalPath_Dir) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogicalPath_Dir.ProtoReflect.Descriptor instead.
 func (*LogicalPath_Dir) Descriptor() ([]byte, []int) {
-	return file_bass_proto_rawDescGZIP(), []int{30, 1}
+	return file_bass_proto_rawDescGZIP(), []int{35, 1}
 }
 
 func (x *LogicalPath_Dir) GetName() string {
@@ -2510,7 +2952,7 @@ var File_bass_proto protoreflect.FileDescriptor
 
 var file_bass_proto_rawDesc = []byte{
 	0x0a, 0x0a, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x62, 0x61,
-	0x73, 0x73, 0x22, 0xc5, 0x05, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x20, 0x0a, 0x04,
+	0x73, 0x73, 0x22, 0xea, 0x05, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x20, 0x0a, 0x04,
 	0x6e, 0x75, 0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62, 0x61, 0x73,
 	0x73, 0x2e, 0x4e, 0x75, 0x6c, 0x6c, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x75, 0x6c, 0x6c, 0x12, 0x20,
 	0x0a, 0x04, 0x62, 0x6f, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62,
@@ -2554,231 +2996,293 @@ var file_bass_proto_rawDesc = []byte{
 	0x30, 0x0a, 0x0a, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x10, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x43, 0x61, 0x63, 0x68, 0x65,
 	0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x09, 0x63, 0x61, 0x63, 0x68, 0x65, 0x50, 0x61, 0x74,
-	0x68, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xaf, 0x04, 0x0a, 0x05, 0x54,
-	0x68, 0x75, 0x6e, 0x6b, 0x12, 0x26, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b,
-	0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08,
-	0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
-	0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x12, 0x1f, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73,
-	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x56, 0x61,
-	0x6c, 0x75, 0x65, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x74, 0x64,
-	0x69, 0x6e, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e,
-	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x12, 0x1f, 0x0a, 0x03,
-	0x65, 0x6e, 0x76, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x61, 0x73, 0x73,
-	0x2e, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x03, 0x65, 0x6e, 0x76, 0x12, 0x20, 0x0a,
-	0x03, 0x64, 0x69, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73,
-	0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x44, 0x69, 0x72, 0x52, 0x03, 0x64, 0x69, 0x72, 0x12,
-	0x28, 0x0a, 0x06, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x10, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x4d, 0x6f, 0x75, 0x6e,
-	0x74, 0x52, 0x06, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x06, 0x6c, 0x61, 0x62,
-	0x65, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x61, 0x73, 0x73,
-	0x2e, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73,
-	0x12, 0x25, 0x0a, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x50, 0x6f, 0x72, 0x74,
-	0x52, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x03, 0x74, 0x6c, 0x73, 0x18, 0x0b,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e,
-	0x6b, 0x54, 0x4c, 0x53, 0x52, 0x03, 0x74, 0x6c, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x6e, 0x74,
-	0x72, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x65,
-	0x6e, 0x74, 0x72, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6c, 0x65,
-	0x61, 0x72, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x0d, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x0f, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x70,
-	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f,
-	0x61, 0x72, 0x67, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x61,
-	0x75, 0x6c, 0x74, 0x41, 0x72, 0x67, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x63, 0x6c, 0x65, 0x61, 0x72,
-	0x5f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x61, 0x72, 0x67, 0x73, 0x18, 0x0f, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x10, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c,
-	0x74, 0x41, 0x72, 0x67, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x5f, 0x65, 0x6e, 0x74,
-	0x72, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x75,
-	0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x22, 0x5a, 0x0a, 0x09,
-	0x54, 0x68, 0x75, 0x6e, 0x6b, 0x41, 0x64, 0x64, 0x72, 0x12, 0x21, 0x0a, 0x05, 0x74, 0x68, 0x75,
-	0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e,
-	0x54, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04,
-	0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74,
-	0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22, 0x33, 0x0a, 0x09, 0x54, 0x68, 0x75, 0x6e,
-	0x6b, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x50, 0x0a,
-	0x08, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x54, 0x4c, 0x53, 0x12, 0x22, 0x0a, 0x04, 0x63, 0x65, 0x72,
-	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x46,
-	0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x63, 0x65, 0x72, 0x74, 0x12, 0x20, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73,
-	0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22,
-	0xcb, 0x01, 0x0a, 0x0a, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x22,
-	0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61,
-	0x73, 0x73, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x66, 0x48, 0x00, 0x52, 0x03, 0x72,
-	0x65, 0x66, 0x12, 0x23, 0x0a, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x00,
-	0x52, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x2e, 0x0a, 0x07, 0x61, 0x72, 0x63, 0x68, 0x69,
-	0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e,
-	0x49, 0x6d, 0x61, 0x67, 0x65, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x48, 0x00, 0x52, 0x07,
-	0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x12, 0x3b, 0x0a, 0x0c, 0x64, 0x6f, 0x63, 0x6b, 0x65,
-	0x72, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
-	0x62, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72,
-	0x42, 0x75, 0x69, 0x6c, 0x64, 0x48, 0x00, 0x52, 0x0b, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42,
-	0x75, 0x69, 0x6c, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x22, 0xfb, 0x01,
-	0x0a, 0x08, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x66, 0x12, 0x2a, 0x0a, 0x08, 0x70, 0x6c,
-	0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62,
-	0x61, 0x73, 0x73, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x08, 0x70, 0x6c,
-	0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x20, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69,
-	0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x65,
-	0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x29, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68,
-	0x75, 0x6e, 0x6b, 0x50, 0x61, 0x74, 0x68, 0x42, 0x02, 0x18, 0x01, 0x48, 0x00, 0x52, 0x04, 0x66,
-	0x69, 0x6c, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x41, 0x64,
-	0x64, 0x72, 0x48, 0x00, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x12, 0x15, 0x0a, 0x03, 0x74, 0x61,
-	0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x03, 0x74, 0x61, 0x67, 0x88, 0x01,
-	0x01, 0x12, 0x1b, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x09, 0x48, 0x02, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x88, 0x01, 0x01, 0x42, 0x08,
-	0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x74, 0x61, 0x67,
-	0x42, 0x09, 0x0a, 0x07, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x84, 0x01, 0x0a, 0x0c,
-	0x49, 0x6d, 0x61, 0x67, 0x65, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x12, 0x2a, 0x0a, 0x08,
-	0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
-	0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x08,
-	0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x29, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6d,
-	0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x04, 0x66,
-	0x69, 0x6c, 0x65, 0x12, 0x15, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x48, 0x00, 0x52, 0x03, 0x74, 0x61, 0x67, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x74,
-	0x61, 0x67, 0x22, 0xef, 0x01, 0x0a, 0x10, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x6f, 0x63, 0x6b,
-	0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x2a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66,
-	0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73,
-	0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66,
-	0x6f, 0x72, 0x6d, 0x12, 0x2f, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6d, 0x61, 0x67,
-	0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e,
-	0x74, 0x65, 0x78, 0x74, 0x12, 0x23, 0x0a, 0x0a, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x66, 0x69,
-	0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x6b,
-	0x65, 0x72, 0x66, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x05,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x42, 0x75, 0x69, 0x6c,
-	0x64, 0x41, 0x72, 0x67, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x64,
-	0x6f, 0x63, 0x6b, 0x65, 0x72, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x22, 0x98, 0x01, 0x0a, 0x0f, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75,
-	0x69, 0x6c, 0x64, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x27, 0x0a, 0x05, 0x74, 0x68, 0x75, 0x6e,
-	0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54,
-	0x68, 0x75, 0x6e, 0x6b, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x05, 0x74, 0x68, 0x75, 0x6e,
-	0x6b, 0x12, 0x24, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x48,
-	0x00, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x6c, 0x6f, 0x67, 0x69, 0x63,
-	0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e,
-	0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x07, 0x6c,
-	0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x42, 0x07, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x22,
-	0x34, 0x0a, 0x08, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x72, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x2e, 0x0a, 0x08, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72,
-	0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f,
-	0x73, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x61, 0x72, 0x63, 0x68, 0x22, 0x87, 0x01, 0x0a, 0x08, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x44,
-	0x69, 0x72, 0x12, 0x25, 0x0a, 0x05, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x0c, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x44, 0x69, 0x72, 0x50, 0x61, 0x74, 0x68,
-	0x48, 0x00, 0x52, 0x05, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x27, 0x0a, 0x05, 0x74, 0x68, 0x75,
-	0x6e, 0x6b, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e,
-	0x54, 0x68, 0x75, 0x6e, 0x6b, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x05, 0x74, 0x68, 0x75,
-	0x6e, 0x6b, 0x12, 0x24, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68,
-	0x48, 0x00, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x42, 0x05, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x22,
-	0xeb, 0x01, 0x0a, 0x10, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b,
-	0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x24, 0x0a,
-	0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61,
-	0x73, 0x73, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x04, 0x68,
-	0x6f, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x4c, 0x6f, 0x67, 0x69,
-	0x63, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x69, 0x63,
-	0x61, 0x6c, 0x12, 0x27, 0x0a, 0x05, 0x63, 0x61, 0x63, 0x68, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x43, 0x61, 0x63, 0x68, 0x65, 0x50, 0x61,
-	0x74, 0x68, 0x48, 0x00, 0x52, 0x05, 0x63, 0x61, 0x63, 0x68, 0x65, 0x12, 0x26, 0x0a, 0x06, 0x73,
-	0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x62, 0x61,
-	0x73, 0x73, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x48, 0x00, 0x52, 0x06, 0x73, 0x65, 0x63,
-	0x72, 0x65, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x6a, 0x0a,
-	0x0a, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x06, 0x73,
-	0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x61,
-	0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x6f, 0x75,
-	0x72, 0x63, 0x65, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x74,
-	0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61,
-	0x73, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x61, 0x74,
-	0x68, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x2c, 0x0a, 0x05, 0x41, 0x72, 0x72,
-	0x61, 0x79, 0x12, 0x23, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52,
-	0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x33, 0x0a, 0x06, 0x4f, 0x62, 0x6a, 0x65, 0x63,
-	0x74, 0x12, 0x29, 0x0a, 0x08, 0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x69,
-	0x6e, 0x67, 0x52, 0x08, 0x62, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x44, 0x0a, 0x07,
-	0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f,
-	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12,
-	0x21, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
-	0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x22, 0x06, 0x0a, 0x04, 0x4e, 0x75, 0x6c, 0x6c, 0x22, 0x1c, 0x0a, 0x04, 0x42, 0x6f,
-	0x6f, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x1b, 0x0a, 0x03, 0x49, 0x6e, 0x74, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x1e, 0x0a, 0x06, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x7e, 0x0a, 0x09, 0x43, 0x61, 0x63, 0x68, 0x65, 0x50, 0x61,
-	0x74, 0x68, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
-	0x69, 0x64, 0x12, 0x28, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x14, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74,
-	0x65, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x37, 0x0a, 0x0b,
-	0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x15, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72,
-	0x65, 0x6e, 0x63, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72,
-	0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x1c, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x22, 0x21, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x50, 0x61,
-	0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x1e, 0x0a, 0x08, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61,
-	0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x51, 0x0a, 0x07, 0x44, 0x69, 0x72, 0x50, 0x61, 0x74,
-	0x68, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65,
-	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x12,
-	0x18, 0x0a, 0x07, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x07, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x22, 0x61, 0x0a, 0x0e, 0x46, 0x69, 0x6c,
-	0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x12, 0x24, 0x0a, 0x04, 0x66,
-	0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73,
-	0x2e, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c,
-	0x65, 0x12, 0x21, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d,
-	0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x44, 0x69, 0x72, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52,
-	0x03, 0x64, 0x69, 0x72, 0x42, 0x06, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x58, 0x0a, 0x09,
-	0x54, 0x68, 0x75, 0x6e, 0x6b, 0x50, 0x61, 0x74, 0x68, 0x12, 0x21, 0x0a, 0x05, 0x74, 0x68, 0x75,
-	0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e,
-	0x54, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x28, 0x0a, 0x04,
-	0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x73,
-	0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x61, 0x74, 0x68,
-	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x4e, 0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x50, 0x61,
-	0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x28, 0x0a, 0x04,
-	0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x73,
-	0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x61, 0x74, 0x68,
-	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0xec, 0x01, 0x0a, 0x0b, 0x4c, 0x6f, 0x67, 0x69, 0x63,
-	0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x12, 0x2c, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x4c, 0x6f, 0x67, 0x69,
-	0x63, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x00, 0x52, 0x04,
-	0x66, 0x69, 0x6c, 0x65, 0x12, 0x29, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x15, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
-	0x50, 0x61, 0x74, 0x68, 0x2e, 0x44, 0x69, 0x72, 0x48, 0x00, 0x52, 0x03, 0x64, 0x69, 0x72, 0x1a,
-	0x34, 0x0a, 0x04, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63,
-	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f,
-	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x1a, 0x46, 0x0a, 0x03, 0x44, 0x69, 0x72, 0x12, 0x12, 0x0a, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x12, 0x2b, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x68, 0x12, 0x23, 0x0a, 0x05, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x46, 0x6c, 0x6f, 0x61, 0x74, 0x48, 0x00, 0x52,
+	0x05, 0x66, 0x6c, 0x6f, 0x61, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22,
+	0xa6, 0x06, 0x0a, 0x05, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x26, 0x0a, 0x05, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e,
+	0x54, 0x68, 0x75, 0x6e, 0x6b, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x12, 0x1f, 0x0a,
+	0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61,
+	0x73, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x21,
+	0x0a, 0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
+	0x62, 0x61, 0x73, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x73, 0x74, 0x64, 0x69,
+	0x6e, 0x12, 0x1f, 0x0a, 0x03, 0x65, 0x6e, 0x76, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x03, 0x65,
+	0x6e, 0x76, 0x12, 0x20, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x44, 0x69, 0x72, 0x52,
+	0x03, 0x64, 0x69, 0x72, 0x12, 0x28, 0x0a, 0x06, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e,
+	0x6b, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x06, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x25,
+	0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d,
+	0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x06, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x25, 0x0a, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x0a,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e,
+	0x6b, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x03,
+	0x74, 0x6c, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73,
+	0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x54, 0x4c, 0x53, 0x52, 0x03, 0x74, 0x6c, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x29,
+	0x0a, 0x10, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x66,
+	0x61, 0x75, 0x6c, 0x74, 0x5f, 0x61, 0x72, 0x67, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x41, 0x72, 0x67, 0x73, 0x12, 0x2c, 0x0a, 0x12,
+	0x63, 0x6c, 0x65, 0x61, 0x72, 0x5f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x61, 0x72,
+	0x67, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x44,
+	0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x41, 0x72, 0x67, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x73,
+	0x65, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0d, 0x75, 0x73, 0x65, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6e, 0x73, 0x18,
+	0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x4e, 0x73,
+	0x12, 0x26, 0x0a, 0x05, 0x72, 0x65, 0x74, 0x72, 0x79, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x74, 0x72,
+	0x79, 0x52, 0x05, 0x72, 0x65, 0x74, 0x72, 0x79, 0x12, 0x32, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61,
+	0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x73, 0x52, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07,
+	0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e,
+	0x62, 0x61, 0x73, 0x73, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4d, 0x6f, 0x64, 0x65,
+	0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x39, 0x0a,
+	0x0c, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x16, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b,
+	0x49, 0x6d, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0b, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x5a, 0x0a, 0x09, 0x54, 0x68, 0x75, 0x6e,
+	0x6b, 0x41, 0x64, 0x64, 0x72, 0x12, 0x21, 0x0a, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e,
+	0x6b, 0x52, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x22, 0x33, 0x0a, 0x09, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x50, 0x6f, 0x72,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x50, 0x0a, 0x08, 0x54, 0x68, 0x75,
+	0x6e, 0x6b, 0x54, 0x4c, 0x53, 0x12, 0x22, 0x0a, 0x04, 0x63, 0x65, 0x72, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x50,
+	0x61, 0x74, 0x68, 0x52, 0x04, 0x63, 0x65, 0x72, 0x74, 0x12, 0x20, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x47, 0x0a, 0x0a, 0x54,
+	0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x74, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66,
+	0x5f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x62, 0x61, 0x63, 0x6b, 0x6f,
+	0x66, 0x66, 0x4e, 0x73, 0x22, 0x4e, 0x0a, 0x0e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x70, 0x75, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x03, 0x63, 0x70, 0x75, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x6d, 0x6f,
+	0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x70, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x70, 0x69, 0x64, 0x73, 0x22, 0x80, 0x02, 0x0a, 0x10, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x49, 0x6d,
+	0x61, 0x67, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x6f, 0x72,
+	0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x77, 0x6f, 0x72, 0x6b, 0x69, 0x6e, 0x67, 0x44, 0x69, 0x72, 0x12, 0x2f, 0x0a, 0x0b, 0x61, 0x6e,
+	0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0d, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x0b,
+	0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x74, 0x6f, 0x70, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x73, 0x74, 0x6f, 0x70, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x12, 0x38, 0x0a, 0x0b,
+	0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x65,
+	0x61, 0x6c, 0x74, 0x68, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x0b, 0x68, 0x65, 0x61, 0x6c, 0x74,
+	0x68, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73,
+	0x12, 0x25, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52,
+	0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x22, 0xae, 0x01, 0x0a, 0x10, 0x54, 0x68, 0x75, 0x6e,
+	0x6b, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x5f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x4e, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f,
+	0x75, 0x74, 0x5f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x4e, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
+	0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x5f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0d, 0x73, 0x74, 0x61, 0x72, 0x74, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x4e, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0xcb, 0x01, 0x0a, 0x0a, 0x54, 0x68, 0x75,
+	0x6e, 0x6b, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x66, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x23, 0x0a, 0x05, 0x74,
+	0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73,
+	0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x00, 0x52, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b,
+	0x12, 0x2e, 0x0a, 0x07, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x41, 0x72,
+	0x63, 0x68, 0x69, 0x76, 0x65, 0x48, 0x00, 0x52, 0x07, 0x61, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65,
+	0x12, 0x3b, 0x0a, 0x0c, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6d,
+	0x61, 0x67, 0x65, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x48, 0x00,
+	0x52, 0x0b, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x07, 0x0a,
+	0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x22, 0xfb, 0x01, 0x0a, 0x08, 0x49, 0x6d, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x66, 0x12, 0x2a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x50, 0x6c, 0x61,
+	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12,
+	0x20, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x6f, 0x72,
+	0x79, 0x12, 0x29, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x50, 0x61, 0x74, 0x68,
+	0x42, 0x02, 0x18, 0x01, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x25, 0x0a, 0x04,
+	0x61, 0x64, 0x64, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73,
+	0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x41, 0x64, 0x64, 0x72, 0x48, 0x00, 0x52, 0x04, 0x61,
+	0x64, 0x64, 0x72, 0x12, 0x15, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x01, 0x52, 0x03, 0x74, 0x61, 0x67, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x06, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x74, 0x61, 0x67, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x22, 0x84, 0x01, 0x0a, 0x0c, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x41, 0x72,
+	0x63, 0x68, 0x69, 0x76, 0x65, 0x12, 0x2a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x50,
+	0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72,
+	0x6d, 0x12, 0x29, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x15, 0x0a, 0x03,
+	0x74, 0x61, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03, 0x74, 0x61, 0x67,
+	0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x74, 0x61, 0x67, 0x22, 0xef, 0x01, 0x0a, 0x10,
+	0x49, 0x6d, 0x61, 0x67, 0x65, 0x44, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x12, 0x2a, 0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f,
+	0x72, 0x6d, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x2f, 0x0a, 0x07,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x62, 0x61, 0x73, 0x73, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49,
+	0x6e, 0x70, 0x75, 0x74, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x23, 0x0a,
+	0x0a, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x0a, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x66, 0x69, 0x6c, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x22, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x62, 0x61, 0x73, 0x73, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x72, 0x67, 0x52, 0x04, 0x61,
+	0x72, 0x67, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x64, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x66, 0x69,
+	0x6c, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x98, 0x01,
+	0x0a, 0x0f, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x6e, 0x70, 0x75,
+	0x74, 0x12, 0x27, 0x0a, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x50, 0x61, 0x74,
+	0x68, 0x48, 0x00, 0x52, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x24, 0x0a, 0x04, 0x68, 0x6f,
+	0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e,
+	0x48, 0x6f, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74,
+	0x12, 0x2d, 0x0a, 0x07, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x11, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
-	0x50, 0x61, 0x74, 0x68, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x42, 0x06, 0x0a,
-	0x04, 0x70, 0x61, 0x74, 0x68, 0x2a, 0x69, 0x0a, 0x0f, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72,
-	0x65, 0x6e, 0x63, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x43, 0x4f, 0x4e, 0x43,
-	0x55, 0x52, 0x52, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x48, 0x41,
-	0x52, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x43, 0x4f, 0x4e, 0x43, 0x55, 0x52, 0x52,
-	0x45, 0x4e, 0x43, 0x59, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54,
-	0x45, 0x10, 0x01, 0x12, 0x1b, 0x0a, 0x17, 0x43, 0x4f, 0x4e, 0x43, 0x55, 0x52, 0x52, 0x45, 0x4e,
-	0x43, 0x59, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x4c, 0x4f, 0x43, 0x4b, 0x45, 0x44, 0x10, 0x02,
-	0x42, 0x0b, 0x5a, 0x09, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x42,
+	0x07, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x22, 0x34, 0x0a, 0x08, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x41, 0x72, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x2e,
+	0x0a, 0x08, 0x50, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72,
+	0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x63, 0x68, 0x22, 0x87,
+	0x01, 0x0a, 0x08, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x44, 0x69, 0x72, 0x12, 0x25, 0x0a, 0x05, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x61, 0x73,
+	0x73, 0x2e, 0x44, 0x69, 0x72, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x12, 0x27, 0x0a, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x50, 0x61,
+	0x74, 0x68, 0x48, 0x00, 0x52, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x24, 0x0a, 0x04, 0x68,
+	0x6f, 0x73, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73,
+	0x2e, 0x48, 0x6f, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x04, 0x68, 0x6f, 0x73,
+	0x74, 0x42, 0x05, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x22, 0xeb, 0x01, 0x0a, 0x10, 0x54, 0x68, 0x75,
+	0x6e, 0x6b, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x27, 0x0a,
+	0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62,
+	0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52,
+	0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x24, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x48, 0x6f, 0x73, 0x74,
+	0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x07,
+	0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x62, 0x61, 0x73, 0x73, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68,
+	0x48, 0x00, 0x52, 0x07, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x12, 0x27, 0x0a, 0x05, 0x63,
+	0x61, 0x63, 0x68, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x62, 0x61, 0x73,
+	0x73, 0x2e, 0x43, 0x61, 0x63, 0x68, 0x65, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x05, 0x63,
+	0x61, 0x63, 0x68, 0x65, 0x12, 0x26, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x48, 0x00, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x42, 0x08, 0x0a, 0x06,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x6a, 0x0a, 0x0a, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x4d,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e,
+	0x6b, 0x4d, 0x6f, 0x75, 0x6e, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x06, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x22, 0x2c, 0x0a, 0x05, 0x41, 0x72, 0x72, 0x61, 0x79, 0x12, 0x23, 0x0a, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61,
+	0x73, 0x73, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x22, 0x33, 0x0a, 0x06, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x29, 0x0a, 0x08, 0x62, 0x69,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62,
+	0x61, 0x73, 0x73, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x62, 0x69, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x44, 0x0a, 0x07, 0x42, 0x69, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x21, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x06, 0x0a, 0x04, 0x4e,
+	0x75, 0x6c, 0x6c, 0x22, 0x1c, 0x0a, 0x04, 0x42, 0x6f, 0x6f, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x22, 0x1b, 0x0a, 0x03, 0x49, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x1d,
+	0x0a, 0x05, 0x46, 0x6c, 0x6f, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x1e, 0x0a,
+	0x06, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x7e, 0x0a,
+	0x09, 0x43, 0x61, 0x63, 0x68, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x28, 0x0a, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e,
+	0x46, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x12, 0x37, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x62, 0x61, 0x73, 0x73,
+	0x2e, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x6f, 0x64, 0x65,
+	0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x1c, 0x0a,
+	0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x21, 0x0a, 0x0b, 0x43,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x50, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x1e,
+	0x0a, 0x08, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x51,
+	0x0a, 0x07, 0x44, 0x69, 0x72, 0x50, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a,
+	0x07, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x22, 0x61, 0x0a, 0x0e, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x50,
+	0x61, 0x74, 0x68, 0x12, 0x24, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74,
+	0x68, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x03, 0x64, 0x69, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x44, 0x69,
+	0x72, 0x50, 0x61, 0x74, 0x68, 0x48, 0x00, 0x52, 0x03, 0x64, 0x69, 0x72, 0x42, 0x06, 0x0a, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x22, 0x58, 0x0a, 0x09, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x50, 0x61, 0x74,
+	0x68, 0x12, 0x21, 0x0a, 0x05, 0x74, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x54, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x05, 0x74,
+	0x68, 0x75, 0x6e, 0x6b, 0x12, 0x28, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x4e,
+	0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x78, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x62, 0x61, 0x73, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x50, 0x61, 0x74, 0x68, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0xec,
+	0x01, 0x0a, 0x0b, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x12, 0x2c,
+	0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x62,
+	0x61, 0x73, 0x73, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x2e,
+	0x46, 0x69, 0x6c, 0x65, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x29, 0x0a, 0x03,
+	0x64, 0x69, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x62, 0x61, 0x73, 0x73,
+	0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x2e, 0x44, 0x69, 0x72,
+	0x48, 0x00, 0x52, 0x03, 0x64, 0x69, 0x72, 0x1a, 0x34, 0x0a, 0x04, 0x46, 0x69, 0x6c, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x1a, 0x46, 0x0a,
+	0x03, 0x44, 0x69, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x62, 0x61, 0x73, 0x73,
+	0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x52, 0x07, 0x65, 0x6e,
+	0x74, 0x72, 0x69, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x2a, 0x55, 0x0a,
+	0x0b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x14,
+	0x4e, 0x45, 0x54, 0x57, 0x4f, 0x52, 0x4b, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x44, 0x45, 0x46,
+	0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x4e, 0x45, 0x54, 0x57, 0x4f, 0x52,
+	0x4b, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x01, 0x12, 0x15, 0x0a,
+	0x11, 0x4e, 0x45, 0x54, 0x57, 0x4f, 0x52, 0x4b, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x48, 0x4f,
+	0x53, 0x54, 0x10, 0x02, 0x2a, 0x69, 0x0a, 0x0f, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x43, 0x4f, 0x4e, 0x43, 0x55,
+	0x52, 0x52, 0x45, 0x4e, 0x43, 0x59, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x48, 0x41, 0x52,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x43, 0x4f, 0x4e, 0x43, 0x55, 0x52, 0x52, 0x45,
+	0x4e, 0x43, 0x59, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45,
+	0x10, 0x01, 0x12, 0x1b, 0x0a, 0x17, 0x43, 0x4f, 0x4e, 0x43, 0x55, 0x52, 0x52, 0x45, 0x4e, 0x43,
+	0x59, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x4c, 0x4f, 0x43, 0x4b, 0x45, 0x44, 0x10, 0x02, 0x42,
+	0x0b, 0x5a, 0x09, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2793,116 +3297,130 @@ func file_bass_proto_rawDescGZIP() []byte {
 	return file_bass_proto_rawDescData
 }
 
-var file_bass_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_bass_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
+var file_bass_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_bass_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_bass_proto_goTypes = []interface{}{
-	(ConcurrencyMode)(0),     // 0: bass.ConcurrencyMode
-	(*Value)(nil),            // 1: bass.Value
-	(*Thunk)(nil),            // 2: bass.Thunk
-	(*ThunkAddr)(nil),        // 3: bass.ThunkAddr
-	(*ThunkPort)(nil),        // 4: bass.ThunkPort
-	(*ThunkTLS)(nil),         // 5: bass.ThunkTLS
-	(*ThunkImage)(nil),       // 6: bass.ThunkImage
-	(*ImageRef)(nil),         // 7: bass.ImageRef
-	(*ImageArchive)(nil),     // 8: bass.ImageArchive
-	(*ImageDockerBuild)(nil), // 9: bass.ImageDockerBuild
-	(*ImageBuildInput)(nil),  // 10: bass.ImageBuildInput
-	(*BuildArg)(nil),         // 11: bass.BuildArg
-	(*Platform)(nil),         // 12: bass.Platform
-	(*ThunkDir)(nil),         // 13: bass.ThunkDir
-	(*ThunkMountSource)(nil), // 14: bass.ThunkMountSource
-	(*ThunkMount)(nil),       // 15: bass.ThunkMount
-	(*Array)(nil),            // 16: bass.Array
-	(*Object)(nil),           // 17: bass.Object
-	(*Binding)(nil),          // 18: bass.Binding
-	(*Null)(nil),             // 19: bass.Null
-	(*Bool)(nil),             // 20: bass.Bool
-	(*Int)(nil),              // 21: bass.Int
-	(*String)(nil),           // 22: bass.String
-	(*CachePath)(nil),        // 23: bass.CachePath
-	(*Secret)(nil),           // 24: bass.Secret
-	(*CommandPath)(nil),      // 25: bass.CommandPath
-	(*FilePath)(nil),         // 26: bass.FilePath
-	(*DirPath)(nil),          // 27: bass.DirPath
-	(*FilesystemPath)(nil),   // 28: bass.FilesystemPath
-	(*ThunkPath)(nil),        // 29: bass.ThunkPath
-	(*HostPath)(nil),         // 30: bass.HostPath
-	(*LogicalPath)(nil),      // 31: bass.LogicalPath
-	(*LogicalPath_File)(nil), // 32: bass.LogicalPath.File
-	(*LogicalPath_Dir)(nil),  // 33: bass.LogicalPath.Dir
+	(NetworkMode)(0),         // 0: bass.NetworkMode
+	(ConcurrencyMode)(0),     // 1: bass.ConcurrencyMode
+	(*Value)(nil),            // 2: bass.Value
+	(*Thunk)(nil),            // 3: bass.Thunk
+	(*ThunkAddr)(nil),        // 4: bass.ThunkAddr
+	(*ThunkPort)(nil),        // 5: bass.ThunkPort
+	(*ThunkTLS)(nil),         // 6: bass.ThunkTLS
+	(*ThunkRetry)(nil),       // 7: bass.ThunkRetry
+	(*ThunkResources)(nil),   // 8: bass.ThunkResources
+	(*ThunkImageConfig)(nil), // 9: bass.ThunkImageConfig
+	(*ThunkHealthcheck)(nil), // 10: bass.ThunkHealthcheck
+	(*ThunkImage)(nil),       // 11: bass.ThunkImage
+	(*ImageRef)(nil),         // 12: bass.ImageRef
+	(*ImageArchive)(nil),     // 13: bass.ImageArchive
+	(*ImageDockerBuild)(nil), // 14: bass.ImageDockerBuild
+	(*ImageBuildInput)(nil),  // 15: bass.ImageBuildInput
+	(*BuildArg)(nil),         // 16: bass.BuildArg
+	(*Platform)(nil),         // 17: bass.Platform
+	(*ThunkDir)(nil),         // 18: bass.ThunkDir
+	(*ThunkMountSource)(nil), // 19: bass.ThunkMountSource
+	(*ThunkMount)(nil),       // 20: bass.ThunkMount
+	(*Array)(nil),            // 21: bass.Array
+	(*Object)(nil),           // 22: bass.Object
+	(*Binding)(nil),          // 23: bass.Binding
+	(*Null)(nil),             // 24: bass.Null
+	(*Bool)(nil),             // 25: bass.Bool
+	(*Int)(nil),              // 26: bass.Int
+	(*Float)(nil),            // 27: bass.Float
+	(*String)(nil),           // 28: bass.String
+	(*CachePath)(nil),        // 29: bass.CachePath
+	(*Secret)(nil),           // 30: bass.Secret
+	(*CommandPath)(nil),      // 31: bass.CommandPath
+	(*FilePath)(nil),         // 32: bass.FilePath
+	(*DirPath)(nil),          // 33: bass.DirPath
+	(*FilesystemPath)(nil),   // 34: bass.FilesystemPath
+	(*ThunkPath)(nil),        // 35: bass.ThunkPath
+	(*HostPath)(nil),         // 36: bass.HostPath
+	(*LogicalPath)(nil),      // 37: bass.LogicalPath
+	(*LogicalPath_File)(nil), // 38: bass.LogicalPath.File
+	(*LogicalPath_Dir)(nil),  // 39: bass.LogicalPath.Dir
 }
 var file_bass_proto_depIdxs = []int32{
-	19, // 0: bass.Value.null:type_name -> bass.Null
-	20, // 1: bass.Value.bool:type_name -> bass.Bool
-	21, // 2: bass.Value.int:type_name -> bass.Int
-	22, // 3: bass.Value.string:type_name -> bass.String
-	24, // 4: bass.Value.secret:type_name -> bass.Secret
-	16, // 5: bass.Value.array:type_name -> bass.Array
-	17, // 6: bass.Value.object:type_name -> bass.Object
-	2,  // 7: bass.Value.thunk:type_name -> bass.Thunk
-	25, // 8: bass.Value.command_path:type_name -> bass.CommandPath
-	26, // 9: bass.Value.file_path:type_name -> bass.FilePath
-	27, // 10: bass.Value.dir_path:type_name -> bass.DirPath
-	30, // 11: bass.Value.host_path:type_name -> bass.HostPath
-	29, // 12: bass.Value.thunk_path:type_name -> bass.ThunkPath
-	31, // 13: bass.Value.logical_path:type_name -> bass.LogicalPath
-	3,  // 14: bass.Value.thunk_addr:type_name -> bass.ThunkAddr
-	23, // 15: bass.Value.cache_path:type_name -> bass.CachePath
-	6,  // 16: bass.Thunk.image:type_name -> bass.ThunkImage
-	1,  // 17: bass.Thunk.args:type_name -> bass.Value
-	1,  // 18: bass.Thunk.stdin:type_name -> bass.Value
-	18, // 19: bass.Thunk.env:type_name -> bass.Binding
-	13, // 20: bass.Thunk.dir:type_name -> bass.ThunkDir
-	15, // 21: bass.Thunk.mounts:type_name -> bass.ThunkMount
-	18, // 22: bass.Thunk.labels:type_name -> bass.Binding
-	4,  // 23: bass.Thunk.ports:type_name -> bass.ThunkPort
-	5,  // 24: bass.Thunk.tls:type_name -> bass.ThunkTLS
-	2,  // 25: bass.ThunkAddr.thunk:type_name -> bass.Thunk
-	26, // 26: bass.ThunkTLS.cert:type_name -> bass.FilePath
-	26, // 27: bass.ThunkTLS.key:type_name -> bass.FilePath
-	7,  // 28: bass.ThunkImage.ref:type_name -> bass.ImageRef
-	2,  // 29: bass.ThunkImage.thunk:type_name -> bass.Thunk
-	8,  // 30: bass.ThunkImage.archive:type_name -> bass.ImageArchive
-	9,  // 31: bass.ThunkImage.docker_build:type_name -> bass.ImageDockerBuild
-	12, // 32: bass.ImageRef.platform:type_name -> bass.Platform
-	29, // 33: bass.ImageRef.file:type_name -> bass.ThunkPath
-	3,  // 34: bass.ImageRef.addr:type_name -> bass.ThunkAddr
-	12, // 35: bass.ImageArchive.platform:type_name -> bass.Platform
-	10, // 36: bass.ImageArchive.file:type_name -> bass.ImageBuildInput
-	12, // 37: bass.ImageDockerBuild.platform:type_name -> bass.Platform
-	10, // 38: bass.ImageDockerBuild.context:type_name -> bass.ImageBuildInput
-	11, // 39: bass.ImageDockerBuild.args:type_name -> bass.BuildArg
-	29, // 40: bass.ImageBuildInput.thunk:type_name -> bass.ThunkPath
-	30, // 41: bass.ImageBuildInput.host:type_name -> bass.HostPath
-	31, // 42: bass.ImageBuildInput.logical:type_name -> bass.LogicalPath
-	27, // 43: bass.ThunkDir.local:type_name -> bass.DirPath
-	29, // 44: bass.ThunkDir.thunk:type_name -> bass.ThunkPath
-	30, // 45: bass.ThunkDir.host:type_name -> bass.HostPath
-	29, // 46: bass.ThunkMountSource.thunk:type_name -> bass.ThunkPath
-	30, // 47: bass.ThunkMountSource.host:type_name -> bass.HostPath
-	31, // 48: bass.ThunkMountSource.logical:type_name -> bass.LogicalPath
-	23, // 49: bass.ThunkMountSource.cache:type_name -> bass.CachePath
-	24, // 50: bass.ThunkMountSource.secret:type_name -> bass.Secret
-	14, // 51: bass.ThunkMount.source:type_name -> bass.ThunkMountSource
-	28, // 52: bass.ThunkMount.target:type_name -> bass.FilesystemPath
-	1,  // 53: bass.Array.values:type_name -> bass.Value
-	18, // 54: bass.Object.bindings:type_name -> bass.Binding
-	1,  // 55: bass.Binding.value:type_name -> bass.Value
-	28, // 56: bass.CachePath.path:type_name -> bass.FilesystemPath
-	0,  // 57: bass.CachePath.concurrency:type_name -> bass.ConcurrencyMode
-	26, // 58: bass.FilesystemPath.file:type_name -> bass.FilePath
-	27, // 59: bass.FilesystemPath.dir:type_name -> bass.DirPath
-	2,  // 60: bass.ThunkPath.thunk:type_name -> bass.Thunk
-	28, // 61: bass.ThunkPath.path:type_name -> bass.FilesystemPath
-	28, // 62: bass.HostPath.path:type_name -> bass.FilesystemPath
-	32, // 63: bass.LogicalPath.file:type_name -> bass.LogicalPath.File
-	33, // 64: bass.LogicalPath.dir:type_name -> bass.LogicalPath.Dir
-	31, // 65: bass.LogicalPath.Dir.entries:type_name -> bass.LogicalPath
-	66, // [66:66] is the sub-list for method output_type
-	66, // [66:66] is the sub-list for method input_type
-	66, // [66:66] is the sub-list for extension type_name
-	66, // [66:66] is the sub-list for extension extendee
-	0,  // [0:66] is the sub-list for field type_name
+	24, // 0: bass.Value.null:type_name -> bass.Null
+	25, // 1: bass.Value.bool:type_name -> bass.Bool
+	26, // 2: bass.Value.int:type_name -> bass.Int
+	28, // 3: bass.Value.string:type_name -> bass.String
+	30, // 4: bass.Value.secret:type_name -> bass.Secret
+	21, // 5: bass.Value.array:type_name -> bass.Array
+	22, // 6: bass.Value.object:type_name -> bass.Object
+	3,  // 7: bass.Value.thunk:type_name -> bass.Thunk
+	31, // 8: bass.Value.command_path:type_name -> bass.CommandPath
+	32, // 9: bass.Value.file_path:type_name -> bass.FilePath
+	33, // 10: bass.Value.dir_path:type_name -> bass.DirPath
+	36, // 11: bass.Value.host_path:type_name -> bass.HostPath
+	35, // 12: bass.Value.thunk_path:type_name -> bass.ThunkPath
+	37, // 13: bass.Value.logical_path:type_name -> bass.LogicalPath
+	4,  // 14: bass.Value.thunk_addr:type_name -> bass.ThunkAddr
+	29, // 15: bass.Value.cache_path:type_name -> bass.CachePath
+	27, // 16: bass.Value.float:type_name -> bass.Float
+	11, // 17: bass.Thunk.image:type_name -> bass.ThunkImage
+	2,  // 18: bass.Thunk.args:type_name -> bass.Value
+	2,  // 19: bass.Thunk.stdin:type_name -> bass.Value
+	23, // 20: bass.Thunk.env:type_name -> bass.Binding
+	18, // 21: bass.Thunk.dir:type_name -> bass.ThunkDir
+	20, // 22: bass.Thunk.mounts:type_name -> bass.ThunkMount
+	23, // 23: bass.Thunk.labels:type_name -> bass.Binding
+	5,  // 24: bass.Thunk.ports:type_name -> bass.ThunkPort
+	6,  // 25: bass.Thunk.tls:type_name -> bass.ThunkTLS
+	7,  // 26: bass.Thunk.retry:type_name -> bass.ThunkRetry
+	8,  // 27: bass.Thunk.resources:type_name -> bass.ThunkResources
+	0,  // 28: bass.Thunk.network:type_name -> bass.NetworkMode
+	9,  // 29: bass.Thunk.image_config:type_name -> bass.ThunkImageConfig
+	3,  // 30: bass.ThunkAddr.thunk:type_name -> bass.Thunk
+	32, // 31: bass.ThunkTLS.cert:type_name -> bass.FilePath
+	32, // 32: bass.ThunkTLS.key:type_name -> bass.FilePath
+	23, // 33: bass.ThunkImageConfig.annotations:type_name -> bass.Binding
+	10, // 34: bass.ThunkImageConfig.healthcheck:type_name -> bass.ThunkHealthcheck
+	23, // 35: bass.ThunkImageConfig.labels:type_name -> bass.Binding
+	12, // 36: bass.ThunkImage.ref:type_name -> bass.ImageRef
+	3,  // 37: bass.ThunkImage.thunk:type_name -> bass.Thunk
+	13, // 38: bass.ThunkImage.archive:type_name -> bass.ImageArchive
+	14, // 39: bass.ThunkImage.docker_build:type_name -> bass.ImageDockerBuild
+	17, // 40: bass.ImageRef.platform:type_name -> bass.Platform
+	35, // 41: bass.ImageRef.file:type_name -> bass.ThunkPath
+	4,  // 42: bass.ImageRef.addr:type_name -> bass.ThunkAddr
+	17, // 43: bass.ImageArchive.platform:type_name -> bass.Platform
+	15, // 44: bass.ImageArchive.file:type_name -> bass.ImageBuildInput
+	17, // 45: bass.ImageDockerBuild.platform:type_name -> bass.Platform
+	15, // 46: bass.ImageDockerBuild.context:type_name -> bass.ImageBuildInput
+	16, // 47: bass.ImageDockerBuild.args:type_name -> bass.BuildArg
+	35, // 48: bass.ImageBuildInput.thunk:type_name -> bass.ThunkPath
+	36, // 49: bass.ImageBuildInput.host:type_name -> bass.HostPath
+	37, // 50: bass.ImageBuildInput.logical:type_name -> bass.LogicalPath
+	33, // 51: bass.ThunkDir.local:type_name -> bass.DirPath
+	35, // 52: bass.ThunkDir.thunk:type_name -> bass.ThunkPath
+	36, // 53: bass.ThunkDir.host:type_name -> bass.HostPath
+	35, // 54: bass.ThunkMountSource.thunk:type_name -> bass.ThunkPath
+	36, // 55: bass.ThunkMountSource.host:type_name -> bass.HostPath
+	37, // 56: bass.ThunkMountSource.logical:type_name -> bass.LogicalPath
+	29, // 57: bass.ThunkMountSource.cache:type_name -> bass.CachePath
+	30, // 58: bass.ThunkMountSource.secret:type_name -> bass.Secret
+	19, // 59: bass.ThunkMount.source:type_name -> bass.ThunkMountSource
+	34, // 60: bass.ThunkMount.target:type_name -> bass.FilesystemPath
+	2,  // 61: bass.Array.values:type_name -> bass.Value
+	23, // 62: bass.Object.bindings:type_name -> bass.Binding
+	2,  // 63: bass.Binding.value:type_name -> bass.Value
+	34, // 64: bass.CachePath.path:type_name -> bass.FilesystemPath
+	1,  // 65: bass.CachePath.concurrency:type_name -> bass.ConcurrencyMode
+	32, // 66: bass.FilesystemPath.file:type_name -> bass.FilePath
+	33, // 67: bass.FilesystemPath.dir:type_name -> bass.DirPath
+	3,  // 68: bass.ThunkPath.thunk:type_name -> bass.Thunk
+	34, // 69: bass.ThunkPath.path:type_name -> bass.FilesystemPath
+	34, // 70: bass.HostPath.path:type_name -> bass.FilesystemPath
+	38, // 71: bass.LogicalPath.file:type_name -> bass.LogicalPath.File
+	39, // 72: bass.LogicalPath.dir:type_name -> bass.LogicalPath.Dir
+	37, // 73: bass.LogicalPath.Dir.entries:type_name -> bass.LogicalPath
+	74, // [74:74] is the sub-list for method output_type
+	74, // [74:74] is the sub-list for method input_type
+	74, // [74:74] is the sub-list for extension type_name
+	74, // [74:74] is the sub-list for extension extendee
+	0,  // [0:74] is the sub-list for field type_name
 }
 
 func init() { file_bass_proto_init() }
@@ -2972,7 +3490,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ThunkImage); i {
+			switch v := v.(*ThunkRetry); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2984,7 +3502,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ImageRef); i {
+			switch v := v.(*ThunkResources); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2996,7 +3514,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ImageArchive); i {
+			switch v := v.(*ThunkImageConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3008,7 +3526,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ImageDockerBuild); i {
+			switch v := v.(*ThunkHealthcheck); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3020,7 +3538,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ImageBuildInput); i {
+			switch v := v.(*ThunkImage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3032,7 +3550,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BuildArg); i {
+			switch v := v.(*ImageRef); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3044,7 +3562,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Platform); i {
+			switch v := v.(*ImageArchive); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3056,7 +3574,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ThunkDir); i {
+			switch v := v.(*ImageDockerBuild); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3068,7 +3586,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ThunkMountSource); i {
+			switch v := v.(*ImageBuildInput); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3080,7 +3598,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ThunkMount); i {
+			switch v := v.(*BuildArg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3092,7 +3610,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Array); i {
+			switch v := v.(*Platform); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3104,7 +3622,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Object); i {
+			switch v := v.(*ThunkDir); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3116,7 +3634,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Binding); i {
+			switch v := v.(*ThunkMountSource); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3128,7 +3646,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Null); i {
+			switch v := v.(*ThunkMount); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3140,7 +3658,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Bool); i {
+			switch v := v.(*Array); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3152,7 +3670,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Int); i {
+			switch v := v.(*Object); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3164,7 +3682,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*String); i {
+			switch v := v.(*Binding); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3176,7 +3694,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CachePath); i {
+			switch v := v.(*Null); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3188,7 +3706,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Secret); i {
+			switch v := v.(*Bool); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3200,7 +3718,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CommandPath); i {
+			switch v := v.(*Int); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3212,7 +3730,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FilePath); i {
+			switch v := v.(*Float); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3224,7 +3742,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DirPath); i {
+			switch v := v.(*String); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3236,7 +3754,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FilesystemPath); i {
+			switch v := v.(*CachePath); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3248,7 +3766,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ThunkPath); i {
+			switch v := v.(*Secret); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3260,7 +3778,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HostPath); i {
+			switch v := v.(*CommandPath); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3272,7 +3790,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LogicalPath); i {
+			switch v := v.(*FilePath); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3284,7 +3802,7 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LogicalPath_File); i {
+			switch v := v.(*DirPath); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3296,6 +3814,66 @@ func file_bass_proto_init() {
 			}
 		}
 		file_bass_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilesystemPath); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bass_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ThunkPath); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bass_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HostPath); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bass_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogicalPath); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bass_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogicalPath_File); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bass_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*LogicalPath_Dir); i {
 			case 0:
 				return &v.state
@@ -3325,42 +3903,43 @@ func file_bass_proto_init() {
 		(*Value_LogicalPath)(nil),
 		(*Value_ThunkAddr)(nil),
 		(*Value_CachePath)(nil),
+		(*Value_Float)(nil),
 	}
-	file_bass_proto_msgTypes[5].OneofWrappers = []interface{}{
+	file_bass_proto_msgTypes[9].OneofWrappers = []interface{}{
 		(*ThunkImage_Ref)(nil),
 		(*ThunkImage_Thunk)(nil),
 		(*ThunkImage_Archive)(nil),
 		(*ThunkImage_DockerBuild)(nil),
 	}
-	file_bass_proto_msgTypes[6].OneofWrappers = []interface{}{
+	file_bass_proto_msgTypes[10].OneofWrappers = []interface{}{
 		(*ImageRef_Repository)(nil),
 		(*ImageRef_File)(nil),
 		(*ImageRef_Addr)(nil),
 	}
-	file_bass_proto_msgTypes[7].OneofWrappers = []interface{}{}
-	file_bass_proto_msgTypes[8].OneofWrappers = []interface{}{}
-	file_bass_proto_msgTypes[9].OneofWrappers = []interface{}{
+	file_bass_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_bass_proto_msgTypes[12].OneofWrappers = []interface{}{}
+	file_bass_proto_msgTypes[13].OneofWrappers = []interface{}{
 		(*ImageBuildInput_Thunk)(nil),
 		(*ImageBuildInput_Host)(nil),
 		(*ImageBuildInput_Logical)(nil),
 	}
-	file_bass_proto_msgTypes[12].OneofWrappers = []interface{}{
+	file_bass_proto_msgTypes[16].OneofWrappers = []interface{}{
 		(*ThunkDir_Local)(nil),
 		(*ThunkDir_Thunk)(nil),
 		(*ThunkDir_Host)(nil),
 	}
-	file_bass_proto_msgTypes[13].OneofWrappers = []interface{}{
+	file_bass_proto_msgTypes[17].OneofWrappers = []interface{}{
 		(*ThunkMountSource_Thunk)(nil),
 		(*ThunkMountSource_Host)(nil),
 		(*ThunkMountSource_Logical)(nil),
 		(*ThunkMountSource_Cache)(nil),
 		(*ThunkMountSource_Secret)(nil),
 	}
-	file_bass_proto_msgTypes[27].OneofWrappers = []interface{}{
+	file_bass_proto_msgTypes[32].OneofWrappers = []interface{}{
 		(*FilesystemPath_File)(nil),
 		(*FilesystemPath_Dir)(nil),
 	}
-	file_bass_proto_msgTypes[30].OneofWrappers = []interface{}{
+	file_bass_proto_msgTypes[35].OneofWrappers = []interface{}{
 		(*LogicalPath_File_)(nil),
 		(*LogicalPath_Dir_)(nil),
 	}
@@ -3369,8 +3948,8 @@ func file_bass_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_bass_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   33,
+			NumEnums:      2,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
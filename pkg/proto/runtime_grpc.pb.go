@@ -19,12 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Runtime_Resolve_FullMethodName    = "/bass.Runtime/Resolve"
-	Runtime_Run_FullMethodName        = "/bass.Runtime/Run"
-	Runtime_Read_FullMethodName       = "/bass.Runtime/Read"
-	Runtime_Export_FullMethodName     = "/bass.Runtime/Export"
-	Runtime_Publish_FullMethodName    = "/bass.Runtime/Publish"
-	Runtime_ExportPath_FullMethodName = "/bass.Runtime/ExportPath"
+	Runtime_Resolve_FullMethodName      = "/bass.Runtime/Resolve"
+	Runtime_Run_FullMethodName          = "/bass.Runtime/Run"
+	Runtime_Read_FullMethodName         = "/bass.Runtime/Read"
+	Runtime_RunResult_FullMethodName    = "/bass.Runtime/RunResult"
+	Runtime_Export_FullMethodName       = "/bass.Runtime/Export"
+	Runtime_ExportImage_FullMethodName  = "/bass.Runtime/ExportImage"
+	Runtime_Publish_FullMethodName      = "/bass.Runtime/Publish"
+	Runtime_PublishIndex_FullMethodName = "/bass.Runtime/PublishIndex"
+	Runtime_ExportPath_FullMethodName   = "/bass.Runtime/ExportPath"
+	Runtime_Prune_FullMethodName        = "/bass.Runtime/Prune"
+	Runtime_Caches_FullMethodName       = "/bass.Runtime/Caches"
 )
 
 // RuntimeClient is the client API for Runtime service.
@@ -34,9 +39,14 @@ type RuntimeClient interface {
 	Resolve(ctx context.Context, in *ImageRef, opts ...grpc.CallOption) (*Thunk, error)
 	Run(ctx context.Context, in *Thunk, opts ...grpc.CallOption) (Runtime_RunClient, error)
 	Read(ctx context.Context, in *Thunk, opts ...grpc.CallOption) (Runtime_ReadClient, error)
+	RunResult(ctx context.Context, in *Thunk, opts ...grpc.CallOption) (Runtime_RunResultClient, error)
 	Export(ctx context.Context, in *Thunk, opts ...grpc.CallOption) (Runtime_ExportClient, error)
+	ExportImage(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (Runtime_ExportImageClient, error)
 	Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (Runtime_PublishClient, error)
+	PublishIndex(ctx context.Context, in *PublishIndexRequest, opts ...grpc.CallOption) (Runtime_PublishIndexClient, error)
 	ExportPath(ctx context.Context, in *ThunkPath, opts ...grpc.CallOption) (Runtime_ExportPathClient, error)
+	Prune(ctx context.Context, in *PruneRequest, opts ...grpc.CallOption) (Runtime_PruneClient, error)
+	Caches(ctx context.Context, in *CachesRequest, opts ...grpc.CallOption) (*CachesResponse, error)
 }
 
 type runtimeClient struct {
@@ -120,8 +130,40 @@ func (x *runtimeReadClient) Recv() (*ReadResponse, error) {
 	return m, nil
 }
 
+func (c *runtimeClient) RunResult(ctx context.Context, in *Thunk, opts ...grpc.CallOption) (Runtime_RunResultClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Runtime_ServiceDesc.Streams[2], Runtime_RunResult_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &runtimeRunResultClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Runtime_RunResultClient interface {
+	Recv() (*RunResultResponse, error)
+	grpc.ClientStream
+}
+
+type runtimeRunResultClient struct {
+	grpc.ClientStream
+}
+
+func (x *runtimeRunResultClient) Recv() (*RunResultResponse, error) {
+	m := new(RunResultResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *runtimeClient) Export(ctx context.Context, in *Thunk, opts ...grpc.CallOption) (Runtime_ExportClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Runtime_ServiceDesc.Streams[2], Runtime_Export_FullMethodName, opts...)
+	stream, err := c.cc.NewStream(ctx, &Runtime_ServiceDesc.Streams[3], Runtime_Export_FullMethodName, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -152,8 +194,40 @@ func (x *runtimeExportClient) Recv() (*ExportResponse, error) {
 	return m, nil
 }
 
+func (c *runtimeClient) ExportImage(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (Runtime_ExportImageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Runtime_ServiceDesc.Streams[4], Runtime_ExportImage_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &runtimeExportImageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Runtime_ExportImageClient interface {
+	Recv() (*ExportResponse, error)
+	grpc.ClientStream
+}
+
+type runtimeExportImageClient struct {
+	grpc.ClientStream
+}
+
+func (x *runtimeExportImageClient) Recv() (*ExportResponse, error) {
+	m := new(ExportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *runtimeClient) Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (Runtime_PublishClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Runtime_ServiceDesc.Streams[3], Runtime_Publish_FullMethodName, opts...)
+	stream, err := c.cc.NewStream(ctx, &Runtime_ServiceDesc.Streams[5], Runtime_Publish_FullMethodName, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -184,8 +258,40 @@ func (x *runtimePublishClient) Recv() (*PublishResponse, error) {
 	return m, nil
 }
 
+func (c *runtimeClient) PublishIndex(ctx context.Context, in *PublishIndexRequest, opts ...grpc.CallOption) (Runtime_PublishIndexClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Runtime_ServiceDesc.Streams[6], Runtime_PublishIndex_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &runtimePublishIndexClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Runtime_PublishIndexClient interface {
+	Recv() (*PublishResponse, error)
+	grpc.ClientStream
+}
+
+type runtimePublishIndexClient struct {
+	grpc.ClientStream
+}
+
+func (x *runtimePublishIndexClient) Recv() (*PublishResponse, error) {
+	m := new(PublishResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *runtimeClient) ExportPath(ctx context.Context, in *ThunkPath, opts ...grpc.CallOption) (Runtime_ExportPathClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Runtime_ServiceDesc.Streams[4], Runtime_ExportPath_FullMethodName, opts...)
+	stream, err := c.cc.NewStream(ctx, &Runtime_ServiceDesc.Streams[7], Runtime_ExportPath_FullMethodName, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -216,6 +322,47 @@ func (x *runtimeExportPathClient) Recv() (*ExportResponse, error) {
 	return m, nil
 }
 
+func (c *runtimeClient) Prune(ctx context.Context, in *PruneRequest, opts ...grpc.CallOption) (Runtime_PruneClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Runtime_ServiceDesc.Streams[8], Runtime_Prune_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &runtimePruneClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Runtime_PruneClient interface {
+	Recv() (*PruneResponse, error)
+	grpc.ClientStream
+}
+
+type runtimePruneClient struct {
+	grpc.ClientStream
+}
+
+func (x *runtimePruneClient) Recv() (*PruneResponse, error) {
+	m := new(PruneResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *runtimeClient) Caches(ctx context.Context, in *CachesRequest, opts ...grpc.CallOption) (*CachesResponse, error) {
+	out := new(CachesResponse)
+	err := c.cc.Invoke(ctx, Runtime_Caches_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RuntimeServer is the server API for Runtime service.
 // All implementations must embed UnimplementedRuntimeServer
 // for forward compatibility
@@ -223,9 +370,14 @@ type RuntimeServer interface {
 	Resolve(context.Context, *ImageRef) (*Thunk, error)
 	Run(*Thunk, Runtime_RunServer) error
 	Read(*Thunk, Runtime_ReadServer) error
+	RunResult(*Thunk, Runtime_RunResultServer) error
 	Export(*Thunk, Runtime_ExportServer) error
+	ExportImage(*ExportRequest, Runtime_ExportImageServer) error
 	Publish(*PublishRequest, Runtime_PublishServer) error
+	PublishIndex(*PublishIndexRequest, Runtime_PublishIndexServer) error
 	ExportPath(*ThunkPath, Runtime_ExportPathServer) error
+	Prune(*PruneRequest, Runtime_PruneServer) error
+	Caches(context.Context, *CachesRequest) (*CachesResponse, error)
 	mustEmbedUnimplementedRuntimeServer()
 }
 
@@ -242,15 +394,30 @@ func (UnimplementedRuntimeServer) Run(*Thunk, Runtime_RunServer) error {
 func (UnimplementedRuntimeServer) Read(*Thunk, Runtime_ReadServer) error {
 	return status.Errorf(codes.Unimplemented, "method Read not implemented")
 }
+func (UnimplementedRuntimeServer) RunResult(*Thunk, Runtime_RunResultServer) error {
+	return status.Errorf(codes.Unimplemented, "method RunResult not implemented")
+}
 func (UnimplementedRuntimeServer) Export(*Thunk, Runtime_ExportServer) error {
 	return status.Errorf(codes.Unimplemented, "method Export not implemented")
 }
+func (UnimplementedRuntimeServer) ExportImage(*ExportRequest, Runtime_ExportImageServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportImage not implemented")
+}
 func (UnimplementedRuntimeServer) Publish(*PublishRequest, Runtime_PublishServer) error {
 	return status.Errorf(codes.Unimplemented, "method Publish not implemented")
 }
+func (UnimplementedRuntimeServer) PublishIndex(*PublishIndexRequest, Runtime_PublishIndexServer) error {
+	return status.Errorf(codes.Unimplemented, "method PublishIndex not implemented")
+}
 func (UnimplementedRuntimeServer) ExportPath(*ThunkPath, Runtime_ExportPathServer) error {
 	return status.Errorf(codes.Unimplemented, "method ExportPath not implemented")
 }
+func (UnimplementedRuntimeServer) Prune(*PruneRequest, Runtime_PruneServer) error {
+	return status.Errorf(codes.Unimplemented, "method Prune not implemented")
+}
+func (UnimplementedRuntimeServer) Caches(context.Context, *CachesRequest) (*CachesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Caches not implemented")
+}
 func (UnimplementedRuntimeServer) mustEmbedUnimplementedRuntimeServer() {}
 
 // UnsafeRuntimeServer may be embedded to opt out of forward compatibility for this service.
@@ -324,6 +491,27 @@ func (x *runtimeReadServer) Send(m *ReadResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Runtime_RunResult_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Thunk)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RuntimeServer).RunResult(m, &runtimeRunResultServer{stream})
+}
+
+type Runtime_RunResultServer interface {
+	Send(*RunResultResponse) error
+	grpc.ServerStream
+}
+
+type runtimeRunResultServer struct {
+	grpc.ServerStream
+}
+
+func (x *runtimeRunResultServer) Send(m *RunResultResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Runtime_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(Thunk)
 	if err := stream.RecvMsg(m); err != nil {
@@ -345,6 +533,27 @@ func (x *runtimeExportServer) Send(m *ExportResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Runtime_ExportImage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RuntimeServer).ExportImage(m, &runtimeExportImageServer{stream})
+}
+
+type Runtime_ExportImageServer interface {
+	Send(*ExportResponse) error
+	grpc.ServerStream
+}
+
+type runtimeExportImageServer struct {
+	grpc.ServerStream
+}
+
+func (x *runtimeExportImageServer) Send(m *ExportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Runtime_Publish_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(PublishRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -366,6 +575,27 @@ func (x *runtimePublishServer) Send(m *PublishResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Runtime_PublishIndex_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PublishIndexRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RuntimeServer).PublishIndex(m, &runtimePublishIndexServer{stream})
+}
+
+type Runtime_PublishIndexServer interface {
+	Send(*PublishResponse) error
+	grpc.ServerStream
+}
+
+type runtimePublishIndexServer struct {
+	grpc.ServerStream
+}
+
+func (x *runtimePublishIndexServer) Send(m *PublishResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Runtime_ExportPath_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(ThunkPath)
 	if err := stream.RecvMsg(m); err != nil {
@@ -387,6 +617,45 @@ func (x *runtimeExportPathServer) Send(m *ExportResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Runtime_Prune_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PruneRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RuntimeServer).Prune(m, &runtimePruneServer{stream})
+}
+
+type Runtime_PruneServer interface {
+	Send(*PruneResponse) error
+	grpc.ServerStream
+}
+
+type runtimePruneServer struct {
+	grpc.ServerStream
+}
+
+func (x *runtimePruneServer) Send(m *PruneResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Runtime_Caches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CachesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RuntimeServer).Caches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Runtime_Caches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RuntimeServer).Caches(ctx, req.(*CachesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Runtime_ServiceDesc is the grpc.ServiceDesc for Runtime service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -398,6 +667,10 @@ var Runtime_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Resolve",
 			Handler:    _Runtime_Resolve_Handler,
 		},
+		{
+			MethodName: "Caches",
+			Handler:    _Runtime_Caches_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -410,21 +683,41 @@ var Runtime_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Runtime_Read_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "RunResult",
+			Handler:       _Runtime_RunResult_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Export",
 			Handler:       _Runtime_Export_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ExportImage",
+			Handler:       _Runtime_ExportImage_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Publish",
 			Handler:       _Runtime_Publish_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "PublishIndex",
+			Handler:       _Runtime_PublishIndex_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "ExportPath",
 			Handler:       _Runtime_ExportPath_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Prune",
+			Handler:       _Runtime_Prune_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "runtime.proto",
 }
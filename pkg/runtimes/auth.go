@@ -0,0 +1,191 @@
+package runtimes
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	authutil "github.com/containerd/containerd/remotes/docker/auth"
+	remoteserrors "github.com/containerd/containerd/remotes/errors"
+	dockerconfig "github.com/docker/cli/cli/config"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/auth"
+	"github.com/moby/buildkit/session/auth/authprovider"
+	"github.com/vito/bass/pkg/bass"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// dockerHubHost is the host that buildkit requests credentials for when
+// pulling from or pushing to Docker Hub.
+const dockerHubHost = "registry-1.docker.io"
+
+// registryAuthProvider is a buildkit session auth provider which serves
+// credentials set with bass.WithRegistryAuth, falling back to the Docker
+// config file and its credential helpers.
+type registryAuthProvider struct {
+	docker auth.AuthServer
+
+	auths map[string][]bass.RegistryAuth
+	authL sync.Mutex
+}
+
+var _ session.Attachable = &registryAuthProvider{}
+
+func newRegistryAuthProvider(stderr io.Writer) *registryAuthProvider {
+	return &registryAuthProvider{
+		docker: authprovider.NewDockerAuthProvider(
+			dockerconfig.LoadDefaultConfigFile(stderr),
+		).(auth.AuthServer),
+		auths: map[string][]bass.RegistryAuth{},
+	}
+}
+
+// Use serves the credentials from the context until the returned func is
+// called.
+func (provider *registryAuthProvider) Use(ctx context.Context) func() {
+	auths := bass.RegistryAuthsFromContext(ctx)
+
+	provider.authL.Lock()
+	for _, a := range auths {
+		host := registryHost(a.Host)
+		provider.auths[host] = append(provider.auths[host], a)
+	}
+	provider.authL.Unlock()
+
+	return func() {
+		provider.authL.Lock()
+		defer provider.authL.Unlock()
+
+		for _, a := range auths {
+			host := registryHost(a.Host)
+
+			stack := provider.auths[host]
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].Host == a.Host && stack[i].Username == a.Username && stack[i].Secret.Equal(a.Secret) {
+					stack = append(stack[:i:i], stack[i+1:]...)
+					break
+				}
+			}
+
+			if len(stack) == 0 {
+				delete(provider.auths, host)
+			} else {
+				provider.auths[host] = stack
+			}
+		}
+	}
+}
+
+func (provider *registryAuthProvider) Register(server *grpc.Server) {
+	auth.RegisterAuthServer(server, provider)
+}
+
+func (provider *registryAuthProvider) Credentials(ctx context.Context, req *auth.CredentialsRequest) (*auth.CredentialsResponse, error) {
+	a, found := provider.lookup(req.Host)
+	if !found {
+		return provider.docker.Credentials(ctx, req)
+	}
+
+	return &auth.CredentialsResponse{
+		Username: a.Username,
+		Secret:   string(a.Secret.Reveal()),
+	}, nil
+}
+
+func (provider *registryAuthProvider) FetchToken(ctx context.Context, req *auth.FetchTokenRequest) (*auth.FetchTokenResponse, error) {
+	a, found := provider.lookup(req.Host)
+	if !found {
+		return provider.docker.FetchToken(ctx, req)
+	}
+
+	to := authutil.TokenOptions{
+		Realm:    req.Realm,
+		Service:  req.Service,
+		Scopes:   req.Scopes,
+		Username: a.Username,
+		Secret:   string(a.Secret.Reveal()),
+	}
+
+	resp, err := authutil.FetchTokenWithOAuth(ctx, http.DefaultClient, nil, "bass", to)
+	if err != nil {
+		// registries without support for POST may respond with any of these;
+		// fall back to GET like buildkit does
+		var errStatus remoteserrors.ErrUnexpectedStatus
+		if errors.As(err, &errStatus) && (errStatus.StatusCode == 405 || errStatus.StatusCode == 404 || errStatus.StatusCode == 401) {
+			resp, err := authutil.FetchToken(ctx, http.DefaultClient, nil, to)
+			if err != nil {
+				return nil, err
+			}
+
+			return tokenResponse(resp.Token, resp.IssuedAt, resp.ExpiresIn), nil
+		}
+
+		return nil, err
+	}
+
+	return tokenResponse(resp.AccessToken, resp.IssuedAt, resp.ExpiresIn), nil
+}
+
+func (provider *registryAuthProvider) GetTokenAuthority(ctx context.Context, req *auth.GetTokenAuthorityRequest) (*auth.GetTokenAuthorityResponse, error) {
+	if _, found := provider.lookup(req.Host); found {
+		// make buildkit fetch tokens through FetchToken instead
+		return nil, status.Errorf(codes.Unavailable, "client side tokens disabled for %s", req.Host)
+	}
+
+	return provider.docker.GetTokenAuthority(ctx, req)
+}
+
+func (provider *registryAuthProvider) VerifyTokenAuthority(ctx context.Context, req *auth.VerifyTokenAuthorityRequest) (*auth.VerifyTokenAuthorityResponse, error) {
+	if _, found := provider.lookup(req.Host); found {
+		return nil, status.Errorf(codes.Unavailable, "client side tokens disabled for %s", req.Host)
+	}
+
+	return provider.docker.VerifyTokenAuthority(ctx, req)
+}
+
+func (provider *registryAuthProvider) lookup(host string) (bass.RegistryAuth, bool) {
+	provider.authL.Lock()
+	defer provider.authL.Unlock()
+
+	stack := provider.auths[registryHost(host)]
+	if len(stack) == 0 {
+		return bass.RegistryAuth{}, false
+	}
+
+	// most recently set credentials win
+	return stack[len(stack)-1], true
+}
+
+// registryHost normalizes the various aliases for Docker Hub to the host that
+// buildkit uses.
+func registryHost(host string) string {
+	switch host {
+	case "docker.io", "index.docker.io":
+		return dockerHubHost
+	default:
+		return host
+	}
+}
+
+func tokenResponse(token string, issuedAt time.Time, expiresIn int) *auth.FetchTokenResponse {
+	// same default as buildkit
+	if expiresIn == 0 {
+		expiresIn = 60
+	}
+
+	resp := &auth.FetchTokenResponse{
+		Token:     token,
+		ExpiresIn: int64(expiresIn),
+	}
+
+	if !issuedAt.IsZero() {
+		resp.IssuedAt = issuedAt.Unix()
+	}
+
+	return resp
+}
@@ -0,0 +1,109 @@
+package runtimes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moby/buildkit/session/auth"
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/is"
+)
+
+func TestRegistryAuthProvider(t *testing.T) {
+	is := is.New(t)
+
+	configDir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", configDir)
+
+	config, err := json.Marshal(map[string]any{
+		"auths": map[string]any{
+			"registry.example.com": map[string]string{
+				"auth": base64.StdEncoding.EncodeToString([]byte("config-user:config-pass")),
+			},
+		},
+	})
+	is.NoErr(err)
+	is.NoErr(os.WriteFile(filepath.Join(configDir, "config.json"), config, 0600))
+
+	provider := newRegistryAuthProvider(os.Stderr)
+
+	ctx := context.Background()
+
+	creds, err := provider.Credentials(ctx, &auth.CredentialsRequest{Host: "registry.example.com"})
+	is.NoErr(err)
+	is.Equal(creds.Username, "config-user")
+	is.Equal(creds.Secret, "config-pass")
+
+	authCtx := bass.WithRegistryAuth(ctx, bass.RegistryAuth{
+		Host:     "registry.example.com",
+		Username: "bass-user",
+		Secret:   bass.NewSecret("token", []byte("bass-pass")),
+	})
+	authCtx = bass.WithRegistryAuth(authCtx, bass.RegistryAuth{
+		Host:     "docker.io",
+		Username: "hub-user",
+		Secret:   bass.NewSecret("hub-token", []byte("hub-pass")),
+	})
+
+	done := provider.Use(authCtx)
+
+	creds, err = provider.Credentials(ctx, &auth.CredentialsRequest{Host: "registry.example.com"})
+	is.NoErr(err)
+	is.Equal(creds.Username, "bass-user")
+	is.Equal(creds.Secret, "bass-pass")
+
+	// Docker Hub aliases are normalized to the host buildkit asks for
+	creds, err = provider.Credentials(ctx, &auth.CredentialsRequest{Host: "registry-1.docker.io"})
+	is.NoErr(err)
+	is.Equal(creds.Username, "hub-user")
+	is.Equal(creds.Secret, "hub-pass")
+
+	var tokenReqs int
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenReqs++
+
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.Form.Get("username") != "bass-user" || r.Form.Get("password") != "bass-pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "some-token",
+			"expires_in":   300,
+		})
+	}))
+	defer tokenSrv.Close()
+
+	token, err := provider.FetchToken(ctx, &auth.FetchTokenRequest{
+		Host:    "registry.example.com",
+		Realm:   tokenSrv.URL,
+		Service: "registry.example.com",
+		Scopes:  []string{"repository:foo:push,pull"},
+	})
+	is.NoErr(err)
+	is.Equal(token.Token, "some-token")
+	is.Equal(token.ExpiresIn, int64(300))
+	is.Equal(tokenReqs, 1)
+
+	// tokens are fetched by the session rather than the daemon
+	_, err = provider.GetTokenAuthority(ctx, &auth.GetTokenAuthorityRequest{Host: "registry.example.com"})
+	is.True(err != nil)
+
+	done()
+
+	creds, err = provider.Credentials(ctx, &auth.CredentialsRequest{Host: "registry.example.com"})
+	is.NoErr(err)
+	is.Equal(creds.Username, "config-user")
+	is.Equal(creds.Secret, "config-pass")
+}
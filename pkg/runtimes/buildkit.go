@@ -2,18 +2,21 @@ package runtimes
 
 import (
 	"archive/tar"
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"embed"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"hash"
 	"io"
 	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -31,20 +34,21 @@ import (
 	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/pkg/transfer/archive"
 	"github.com/containerd/containerd/platforms"
-	dockerconfig "github.com/docker/cli/cli/config"
 	"github.com/docker/distribution/reference"
-	"github.com/hashicorp/go-multierror"
 	bkclient "github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/exporter/containerimage/image"
 	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
 	"github.com/moby/buildkit/frontend/dockerui"
 	gwclient "github.com/moby/buildkit/frontend/gateway/client"
+	gwpb "github.com/moby/buildkit/frontend/gateway/pb"
+	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/session"
-	"github.com/moby/buildkit/session/auth/authprovider"
 	"github.com/moby/buildkit/session/filesync"
 	"github.com/moby/buildkit/session/secrets"
 	"github.com/moby/buildkit/session/secrets/secretsprovider"
+	"github.com/moby/buildkit/solver/errdefs"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/entitlements"
 	"github.com/morikuni/aec"
@@ -80,6 +84,74 @@ type BuildkitConfig struct {
 	DisableCache bool   `json:"disable_cache,omitempty"`
 	CertsDir     string `json:"certs_dir,omitempty"`
 	OCIStoreDir  string `json:"oci_store_dir,omitempty"`
+
+	// Caches to import from and export to, e.g. to share layer cache between
+	// ephemeral CI workers.
+	//
+	// Caches are exported once, when the runtime is closed, covering every
+	// thunk built by the runtime.
+	CacheImports []BuildkitCacheConfig `json:"cache_imports,omitempty"`
+	CacheExports []BuildkitCacheConfig `json:"cache_exports,omitempty"`
+}
+
+// BuildkitCacheConfig configures a Buildkit cache importer or exporter.
+type BuildkitCacheConfig struct {
+	// The cache backend, e.g. local, registry, or inline.
+	Type string `json:"type"`
+
+	// Attributes for the backend, e.g. src and dest for local or ref for
+	// registry.
+	Attrs map[string]string `json:"attrs,omitempty"`
+}
+
+// Scope returns the config as a scope suitable for BuildkitConfig.
+func (config BuildkitCacheConfig) Scope() *bass.Scope {
+	attrs := bass.NewEmptyScope()
+	for k, v := range config.Attrs {
+		attrs.Set(bass.Symbol(k), bass.String(v))
+	}
+
+	return bass.Bindings{
+		"type":  bass.String(config.Type),
+		"attrs": attrs,
+	}.Scope()
+}
+
+// ParseBuildkitCacheConfig parses a cache config in the same CSV format as
+// 'docker buildx build --cache-from' and '--cache-to', e.g.
+// "type=local,src=./cache".
+//
+// A value without a type is treated as a registry ref.
+func ParseBuildkitCacheConfig(str string) (BuildkitCacheConfig, error) {
+	config := BuildkitCacheConfig{
+		Attrs: map[string]string{},
+	}
+
+	fields := strings.Split(str, ",")
+	for _, field := range fields {
+		key, val, ok := strings.Cut(field, "=")
+		if !ok {
+			if len(fields) != 1 {
+				return BuildkitCacheConfig{}, fmt.Errorf("malformed cache config field: %q", field)
+			}
+
+			config.Type = "registry"
+			config.Attrs["ref"] = field
+			continue
+		}
+
+		if key == "type" {
+			config.Type = val
+		} else {
+			config.Attrs[key] = val
+		}
+	}
+
+	if config.Type == "" {
+		return BuildkitCacheConfig{}, fmt.Errorf("cache config has no type: %q", str)
+	}
+
+	return config, nil
 }
 
 var _ bass.Runtime = &Buildkit{}
@@ -94,8 +166,15 @@ const workDir = "/bass/work"
 const ioDir = "/bass/io"
 const inputFile = "/bass/io/in"
 const outputFile = "/bass/io/out"
+const stderrFile = "/bass/io/err"
+const exitCodeFile = "/bass/io/exit"
+const runIDFile = "/bass/io/run-id"
 const caFile = "/bass/ca.crt"
 
+// ioUserDir is the directory in the thunk's image mounted to ioDir for thunks
+// run as another user, who has to own it to write the command's output.
+const ioUserDir = "bass-io"
+
 var allShims = map[string][]byte{}
 
 func init() {
@@ -122,8 +201,13 @@ type Buildkit struct {
 
 	solveOpt bkclient.SolveOpt
 
+	auth     *registryAuthProvider
 	secrets  *secretStore
 	ociStore content.Store
+
+	// definitions of built thunks, to export to CacheExports on Close
+	built  map[digest.Digest]*pb.Definition
+	builtL sync.Mutex
 }
 
 const DefaultBuildkitInstallation = "bass-buildkitd"
@@ -176,9 +260,7 @@ func NewBuildkit(ctx context.Context, _ bass.RuntimePool, cfg *bass.Scope) (bass
 		checkSame = platforms.Only(platform)
 	}
 
-	authp := authprovider.NewDockerAuthProvider(
-		dockerconfig.LoadDefaultConfigFile(os.Stderr),
-	)
+	authp := newRegistryAuthProvider(os.Stderr)
 
 	secrets := newSecretStore()
 
@@ -187,7 +269,7 @@ func NewBuildkit(ctx context.Context, _ bass.RuntimePool, cfg *bass.Scope) (bass
 		return nil, fmt.Errorf("create oci store: %w", err)
 	}
 
-	solveOpt := newSolveOpt(authp, secrets, ociStore)
+	solveOpt := newSolveOpt(config, authp, secrets, ociStore)
 
 	runtime := &Buildkit{
 		Config: config,
@@ -197,6 +279,7 @@ func NewBuildkit(ctx context.Context, _ bass.RuntimePool, cfg *bass.Scope) (bass
 
 		client: client,
 
+		auth:     authp,
 		secrets:  secrets,
 		ociStore: ociStore,
 		solveOpt: solveOpt,
@@ -242,9 +325,7 @@ func NewBuildkitFrontend(gw gwclient.Client, inputs map[string]llb.State, config
 		config.OCIStoreDir = filepath.Join(xdg.DataHome, "bass", "oci")
 	}
 
-	authp := authprovider.NewDockerAuthProvider(
-		dockerconfig.LoadDefaultConfigFile(os.Stderr),
-	)
+	authp := newRegistryAuthProvider(os.Stderr)
 
 	secrets := newSecretStore()
 
@@ -253,7 +334,7 @@ func NewBuildkitFrontend(gw gwclient.Client, inputs map[string]llb.State, config
 		return nil, fmt.Errorf("create oci store: %w", err)
 	}
 
-	solveOpt := newSolveOpt(authp, secrets, ociStore)
+	solveOpt := newSolveOpt(config, authp, secrets, ociStore)
 
 	return &Buildkit{
 		Config: config,
@@ -265,6 +346,7 @@ func NewBuildkitFrontend(gw gwclient.Client, inputs map[string]llb.State, config
 
 		gateway: &RecordingGateway{gw},
 
+		auth:     authp,
 		secrets:  secrets,
 		ociStore: ociStore,
 		solveOpt: solveOpt,
@@ -286,6 +368,7 @@ func (runtime *Buildkit) WithGateway(ctx context.Context, doBuild func(ctx conte
 	}
 
 	statusProxy := forwardStatus(progrock.FromContext(ctx))
+	statusProxy.tap, _ = ctx.Value(outputTapKey{}).(*outputTap)
 	defer statusProxy.Wait()
 
 	_, err := runtime.client.Build(
@@ -347,6 +430,8 @@ func (runtime *Buildkit) Run(ctx context.Context, thunk bass.Thunk) error {
 	ctx, rec := progrock.WithGroup(ctx, "run "+thunk.String())
 	defer rec.Complete()
 
+	ctx, done := thunk.TimeoutContext(ctx)
+
 	ctx, svcs := bass.TrackRuns(ctx)
 	defer svcs.StopAndWait()
 	_, err := runtime.build(
@@ -354,11 +439,16 @@ func (runtime *Buildkit) Run(ctx context.Context, thunk bass.Thunk) error {
 		thunk,
 		nil, // exports
 		func(ctx context.Context, gw gwclient.Client, ib IntermediateBuild) (*gwclient.Result, error) {
-			return ib.ForRun(ctx, gw)
+			res, err := ib.ForRun(ctx, gw)
+			if err != nil {
+				runtime.debugFailure(ctx, gw, thunk, err)
+			}
+
+			return res, err
 		},
 		true, // inherit entrypoint/cmd
 	)
-	return err
+	return done(err)
 }
 
 func (runtime *Buildkit) Start(ctx context.Context, thunk bass.Thunk) (StartResult, error) {
@@ -457,42 +547,141 @@ func (runtime *Buildkit) Read(ctx context.Context, w io.Writer, thunk bass.Thunk
 	ctx, rec := progrock.WithGroup(ctx, "read "+thunk.String())
 	defer rec.Complete()
 
+	ctx, done := thunk.TimeoutContext(ctx)
+
 	ctx, svcs := bass.TrackRuns(ctx)
 	defer svcs.StopAndWait()
 
+	// stream output from the build's logs while the command runs
+	tap := &outputTap{w: w}
+	ctx = context.WithValue(ctx, outputTapKey{}, tap)
+
 	_, err := runtime.build(
 		ctx,
 		thunk,
 		nil,
 		func(ctx context.Context, gw gwclient.Client, ib IntermediateBuild) (*gwclient.Result, error) {
-			return ib.ReadStdout(ctx, gw, w)
+			res, err := ib.ReadStdout(ctx, gw, tap)
+			if err != nil {
+				runtime.debugFailure(ctx, gw, thunk, err)
+			}
+
+			return res, err
 		},
 		true, // inherit entrypoint/cmd
 	)
-	return err
+	return done(err)
+}
+
+func (runtime *Buildkit) RunResult(ctx context.Context, thunk bass.Thunk) (bass.RunResult, error) {
+	ctx, rec := progrock.WithGroup(ctx, "run "+thunk.String())
+	defer rec.Complete()
+
+	ctx, done := thunk.TimeoutContext(ctx)
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	hash, err := thunk.Hash()
+	if err != nil {
+		return bass.RunResult{}, done(err)
+	}
+
+	// a failed command is cached like any other, so each run is given an ID
+	// which is passed as a secret. if a failure comes back with a different ID
+	// it's from the cache, so the command is run again.
+	//
+	// the secret ID includes the run ID so that concurrent runs of the same
+	// thunk don't overwrite each other's ID.
+	runID := identity.NewID()
+	secretID := "bass-run-id-" + hash + "-" + runID
+	runtime.secrets.SetSecret(secretID, []byte(runID))
+	defer runtime.secrets.DeleteSecret(secretID)
+
+	result, resultID, err := runtime.runResult(ctx, thunk, secretID)
+	if err == nil && result.ExitCode != 0 && resultID != runID {
+		result, _, err = runtime.runResult(ctx, thunk, secretID, llb.IgnoreCache)
+	}
+
+	return result, done(err)
+}
+
+func (runtime *Buildkit) runResult(ctx context.Context, thunk bass.Thunk, secretID string, runOpts ...llb.RunOption) (bass.RunResult, string, error) {
+	var result bass.RunResult
+	var runID string
+	_, err := runtime.build(
+		ctx,
+		thunk,
+		nil, // exports
+		func(ctx context.Context, gw gwclient.Client, ib IntermediateBuild) (*gwclient.Result, error) {
+			res, err := ib.ReadResult(ctx, gw, &result, &runID)
+			if err != nil {
+				runtime.debugFailure(ctx, gw, thunk, err)
+			}
+
+			return res, err
+		},
+		true, // inherit entrypoint/cmd
+		append([]llb.RunOption{
+			// record the exit code instead of failing
+			llb.AddEnv("_BASS_STDERR", stderrFile),
+			llb.AddEnv("_BASS_EXIT_CODE", exitCodeFile),
+			llb.AddEnv("_BASS_RUN_ID_FILE", runIDFile),
+			llb.AddSecret("_BASS_RUN_ID", llb.SecretID(secretID), llb.SecretAsEnv(true)),
+		}, runOpts...)...,
+	)
+	return result, runID, err
+}
+
+// debugFailure opens a debug shell for a failed command if configured with
+// bass.WithDebugShell. Failing to open the shell is only logged so that the
+// original error is raised.
+func (runtime *Buildkit) debugFailure(ctx context.Context, gw gwclient.Client, thunk bass.Thunk, err error) {
+	if !bass.DebugShellEnabled(ctx) || ctx.Err() != nil {
+		return
+	}
+
+	if err := runtime.NewBuilder(gw).DebugShell(ctx, thunk.String(), err); err != nil {
+		zapctx.FromContext(ctx).Error("failed to open debug shell", zap.Error(err))
+	}
 }
 
 type marshalable interface {
 	Marshal(ctx context.Context, co ...llb.ConstraintsOpt) (*llb.Definition, error)
 }
 
-func (runtime *Buildkit) Export(ctx context.Context, w io.Writer, thunk bass.Thunk) error {
+func (runtime *Buildkit) Export(ctx context.Context, w io.Writer, thunk bass.Thunk, opts bass.ExportOpts) error {
 	ctx, rec := progrock.WithGroup(ctx, "export "+thunk.String())
 	defer rec.Complete()
 
+	export := bkclient.ExportEntry{
+		Output: func(map[string]string) (io.WriteCloser, error) {
+			return nopCloser{w}, nil
+		},
+	}
+
+	switch opts.Format {
+	case bass.ExportOCI, "":
+		export.Type = bkclient.ExporterOCI
+	case bass.ExportDocker:
+		export.Type = bkclient.ExporterDocker
+		if len(opts.Tags) > 0 {
+			export.Attrs = map[string]string{
+				"name": strings.Join(opts.Tags, ","),
+			}
+		}
+	case bass.ExportRootfs:
+		export.Type = bkclient.ExporterTar
+	default:
+		return fmt.Errorf("unsupported export format: %s", opts.Format)
+	}
+
 	ctx, svcs := bass.TrackRuns(ctx)
 	defer svcs.StopAndWait()
 	_, err := runtime.build(
 		ctx,
 		thunk,
-		[]bkclient.ExportEntry{
-			{
-				Type: bkclient.ExporterOCI,
-				Output: func(map[string]string) (io.WriteCloser, error) {
-					return nopCloser{w}, nil
-				},
-			},
-		},
+		[]bkclient.ExportEntry{export},
 		func(ctx context.Context, gw gwclient.Client, ib IntermediateBuild) (*gwclient.Result, error) {
 			return ib.ForPublish(ctx, gw)
 		},
@@ -505,6 +694,8 @@ func (runtime *Buildkit) Publish(ctx context.Context, ref bass.ImageRef, thunk b
 	ctx, rec := progrock.WithGroup(ctx, "publish "+thunk.String())
 	defer rec.Complete()
 
+	defer runtime.auth.Use(ctx)()
+
 	ctx, svcs := bass.TrackRuns(ctx)
 	defer svcs.StopAndWait()
 
@@ -542,6 +733,47 @@ func (runtime *Buildkit) Publish(ctx context.Context, ref bass.ImageRef, thunk b
 	return ref, nil
 }
 
+func (runtime *Buildkit) PublishIndex(ctx context.Context, ref bass.ImageRef, thunks []bass.Thunk) (bass.ImageRef, error) {
+	addr, err := ref.Ref()
+	if err != nil {
+		return ref, err
+	}
+
+	ctx, rec := progrock.WithGroup(ctx, "publish index "+addr)
+	defer rec.Complete()
+
+	defer runtime.auth.Use(ctx)()
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	res, err := runtime.buildAll(
+		ctx,
+		thunks,
+		[]bkclient.ExportEntry{
+			{
+				Type: bkclient.ExporterImage,
+				Attrs: map[string]string{
+					"name": addr,
+					"push": "true",
+				},
+			},
+		},
+		ForPublishIndex,
+		false, // do not inherit entrypoint/cmd
+	)
+	if err != nil {
+		return ref, err
+	}
+
+	imageDigest, found := res.ExporterResponse[exptypes.ExporterImageDigestKey]
+	if found {
+		ref.Digest = imageDigest
+	}
+
+	return ref, nil
+}
+
 func (runtime *Buildkit) ExportPath(ctx context.Context, w io.Writer, tp bass.ThunkPath) error {
 	ctx, rec := progrock.WithGroup(ctx, "export path "+tp.String())
 	defer rec.Complete()
@@ -589,6 +821,32 @@ func (runtime *Buildkit) Prune(ctx context.Context, opts bass.PruneOpts) error {
 	stderr := ioctx.StderrFromContext(ctx)
 	tw := tabwriter.NewWriter(stderr, 2, 8, 2, ' ', 0)
 
+	kitdOpts := []bkclient.PruneOption{
+		bkclient.WithKeepOpt(opts.KeepDuration, opts.KeepBytes),
+	}
+
+	if opts.All {
+		kitdOpts = append(kitdOpts, bkclient.PruneAll)
+	}
+
+	client, err := runtime.Client()
+	if err != nil {
+		return err
+	}
+
+	prune := true
+	if len(opts.IDs) > 0 {
+		filters, err := runtime.idFilters(ctx, opts.IDs)
+		if err != nil {
+			return err
+		}
+
+		// no matching caches; an empty filter would match everything
+		prune = len(filters) > 0
+
+		kitdOpts = append(kitdOpts, bkclient.WithFilter(filters))
+	}
+
 	ch := make(chan bkclient.UsageInfo)
 	printed := make(chan struct{})
 
@@ -612,38 +870,208 @@ func (runtime *Buildkit) Prune(ctx context.Context, opts bass.PruneOpts) error {
 		}
 	}()
 
-	kitdOpts := []bkclient.PruneOption{
-		bkclient.WithKeepOpt(opts.KeepDuration, opts.KeepBytes),
+	if prune {
+		err = client.Prune(ctx, ch, kitdOpts...)
+	}
+	close(ch)
+	<-printed
+	if err != nil {
+		return err
 	}
 
-	if opts.All {
-		kitdOpts = append(kitdOpts, bkclient.PruneAll)
+	fmt.Fprintf(tw, "total: %.2f\n", units.Bytes(total))
+
+	return tw.Flush()
+}
+
+func (runtime *Buildkit) Caches(ctx context.Context) ([]bass.CacheInfo, error) {
+	client, err := runtime.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := client.DiskUsage(ctx, bkclient.WithFilter([]string{
+		"type==" + string(bkclient.UsageRecordTypeCacheMount),
+	}))
+	if err != nil {
+		return nil, err
 	}
 
+	caches := make([]bass.CacheInfo, len(usage))
+	for i, du := range usage {
+		id, ok := cacheMountID(du.Description)
+		if !ok {
+			id = du.ID
+		}
+
+		caches[i] = bass.CacheInfo{
+			ID:          id,
+			Description: du.Description,
+			Size:        du.Size,
+			UsageCount:  du.UsageCount,
+		}
+
+		if du.LastUsedAt != nil {
+			caches[i].LastUsedAt = *du.LastUsedAt
+		}
+	}
+
+	return caches, nil
+}
+
+// idFilters returns Buildkit filters matching the records of any of the given
+// caches, as identified by Caches.
+func (runtime *Buildkit) idFilters(ctx context.Context, ids []string) ([]string, error) {
 	client, err := runtime.Client()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = client.Prune(ctx, ch, kitdOpts...)
-	close(ch)
-	<-printed
+	usage, err := client.DiskUsage(ctx, bkclient.WithFilter([]string{
+		"type==" + string(bkclient.UsageRecordTypeCacheMount),
+	}))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Fprintf(tw, "total: %.2f\n", units.Bytes(total))
+	want := map[string]bool{}
+	for _, id := range ids {
+		want[id] = true
+	}
 
-	return tw.Flush()
+	var filters []string
+	for _, du := range usage {
+		id, ok := cacheMountID(du.Description)
+		if (ok && want[id]) || want[du.ID] {
+			filters = append(filters, "id=="+du.ID)
+		}
+	}
+
+	return filters, nil
+}
+
+// cacheMountID parses the cache ID from a cache mount's description, e.g.
+// `cached mount /var/cache from exec /bin/sh with id "apt-cache"`.
+//
+// Older Buildkit versions do not include the ID in the description.
+func cacheMountID(desc string) (string, bool) {
+	idx := strings.LastIndex(desc, " with id ")
+	if idx == -1 {
+		return "", false
+	}
+
+	id, err := strconv.Unquote(desc[idx+len(" with id "):])
+	if err != nil {
+		return "", false
+	}
+
+	return id, true
 }
 
 func (runtime *Buildkit) Close() error {
-	if runtime.client != nil {
-		return runtime.client.Close()
+	if runtime.client == nil {
+		return nil
+	}
+
+	exportErr := runtime.exportCache(context.Background())
+
+	if err := runtime.client.Close(); err != nil {
+		return err
+	}
+
+	return exportErr
+}
+
+// recordBuilt records the thunks' definitions so their cache can be exported
+// when the runtime is closed.
+func (runtime *Buildkit) recordBuilt(ctx context.Context, ibs []IntermediateBuild) error {
+	if len(runtime.Config.CacheExports) == 0 {
+		return nil
+	}
+
+	runtime.builtL.Lock()
+	defer runtime.builtL.Unlock()
+
+	if runtime.built == nil {
+		runtime.built = map[digest.Digest]*pb.Definition{}
+	}
+
+	for _, ib := range ibs {
+		def, err := ib.FS.Marshal(ctx, llb.Platform(ib.Platform))
+		if err != nil {
+			return err
+		}
+
+		pbDef := def.ToPB()
+
+		// forced re-runs are cached all the same; don't run them yet again
+		for dgst, meta := range pbDef.Metadata {
+			meta.IgnoreCache = false
+			pbDef.Metadata[dgst] = meta
+		}
+
+		if len(pbDef.Def) == 0 {
+			continue
+		}
+
+		runtime.built[digest.FromBytes(pbDef.Def[len(pbDef.Def)-1])] = pbDef
 	}
+
+	return nil
+}
+
+// exportCache exports the cache for every thunk built by the runtime to the
+// configured CacheExports in a single solve, so that exporters don't clobber
+// each other's results.
+func (runtime *Buildkit) exportCache(ctx context.Context) error {
+	runtime.builtL.Lock()
+	defer runtime.builtL.Unlock()
+
+	if len(runtime.Config.CacheExports) == 0 || len(runtime.built) == 0 {
+		return nil
+	}
+
+	solveOpt := runtime.solveOpt
+	solveOpt.CacheExports = cacheOptions(runtime.Config.CacheExports)
+
+	statusProxy := forwardStatus(progrock.FromContext(ctx))
+	defer statusProxy.Wait()
+
+	_, err := runtime.client.Build(ctx, solveOpt, buildkitProduct, func(ctx context.Context, gw gwclient.Client) (*gwclient.Result, error) {
+		return solveBuilt(ctx, gw, runtime.built)
+	}, statusProxy.Writer())
+	if err != nil {
+		return statusProxy.NiceError("export cache failed", err)
+	}
+
+	runtime.built = nil
+
 	return nil
 }
 
+// solveBuilt solves each of the definitions, returning a result with a ref
+// for each so that all of them are exported.
+func solveBuilt(ctx context.Context, gw gwclient.Client, built map[digest.Digest]*pb.Definition) (*gwclient.Result, error) {
+	res := gwclient.NewResult()
+	for dgst, def := range built {
+		solved, err := gw.Solve(ctx, gwclient.SolveRequest{
+			Definition: def,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		ref, err := solved.SingleRef()
+		if err != nil {
+			return nil, err
+		}
+
+		res.AddRef(dgst.String(), ref)
+	}
+
+	return res, nil
+}
+
 func (runtime *Buildkit) build(
 	ctx context.Context,
 	thunk bass.Thunk,
@@ -652,15 +1080,45 @@ func (runtime *Buildkit) build(
 	forceExec bool,
 	runOpts ...llb.RunOption,
 ) (*bkclient.SolveResponse, error) {
-	// build llb definition using the remote gateway for image resolution
-	var ib IntermediateBuild
+	return runtime.buildAll(
+		ctx,
+		[]bass.Thunk{thunk},
+		exports,
+		func(ctx context.Context, gw gwclient.Client, ibs []IntermediateBuild) (*gwclient.Result, error) {
+			return cb(ctx, gw, ibs[0])
+		},
+		forceExec,
+		runOpts...,
+	)
+}
+
+// buildAll builds multiple thunks and solves them together, e.g. for
+// publishing a multi-platform index.
+//
+// Each thunk is built for its own platform if it differs from the runtime's.
+func (runtime *Buildkit) buildAll(
+	ctx context.Context,
+	thunks []bass.Thunk,
+	exports []bkclient.ExportEntry,
+	cb func(context.Context, gwclient.Client, []IntermediateBuild) (*gwclient.Result, error),
+	forceExec bool,
+	runOpts ...llb.RunOption,
+) (*bkclient.SolveResponse, error) {
+	// build llb definitions using the remote gateway for image resolution
+	ibs := make([]IntermediateBuild, len(thunks))
 	err := runtime.WithGateway(ctx, func(ctx context.Context, gw gwclient.Client) (*gwclient.Result, error) {
-		b := runtime.NewBuilder(gw)
+		for i, thunk := range thunks {
+			b := runtime.NewPlatformBuilder(gw, runtime.platformFor(thunk))
 
-		var err error
-		ib, err = b.Build(ctx, thunk, forceExec, runOpts...)
-		if err != nil {
-			return nil, err
+			var err error
+			ibs[i], err = b.Build(ctx, thunk, forceExec, runOpts...)
+			if err != nil {
+				if len(thunks) > 1 {
+					return nil, fmt.Errorf("%s: %w", thunk.Platform(), err)
+				}
+
+				return nil, err
+			}
 		}
 
 		return &gwclient.Result{}, nil
@@ -670,20 +1128,27 @@ func (runtime *Buildkit) build(
 	}
 
 	doBuild := func(ctx context.Context, gw gwclient.Client) (*gwclient.Result, error) {
-		return cb(ctx, RecordingGateway{gw}, ib)
+		return cb(ctx, RecordingGateway{gw}, ibs)
 	}
 
 	if len(exports) > 0 {
 		solveOpt := runtime.solveOpt
 		solveOpt.Exports = exports
 
-		if client, err := runtime.Client(); err == nil {
-			statusProxy := forwardStatus(progrock.FromContext(ctx))
-			defer statusProxy.Wait()
-			return client.Build(ctx, solveOpt, buildkitProduct, doBuild, statusProxy.Writer())
+		client, err := runtime.Client()
+		if err != nil {
+			return nil, fmt.Errorf("gateway client does not support exporting")
 		}
 
-		return nil, fmt.Errorf("gateway client does not support exporting")
+		statusProxy := forwardStatus(progrock.FromContext(ctx))
+		defer statusProxy.Wait()
+
+		res, err := client.Build(ctx, solveOpt, buildkitProduct, doBuild, statusProxy.Writer())
+		if err != nil {
+			return nil, err
+		}
+
+		return res, runtime.recordBuilt(ctx, ibs)
 	}
 
 	err = runtime.WithGateway(ctx, doBuild)
@@ -691,7 +1156,19 @@ func (runtime *Buildkit) build(
 		return nil, err
 	}
 
-	return &bkclient.SolveResponse{}, nil
+	return &bkclient.SolveResponse{}, runtime.recordBuilt(ctx, ibs)
+}
+
+// platformFor returns the platform to build the thunk for: the runtime's
+// own, unless the thunk is for another OS or architecture.
+func (runtime *Buildkit) platformFor(thunk bass.Thunk) ocispecs.Platform {
+	platform := thunk.Platform()
+	if platform == nil ||
+		(platform.OS == runtime.Platform.OS && platform.Architecture == runtime.Platform.Architecture) {
+		return runtime.Platform
+	}
+
+	return ocispecs.Platform(*platform)
 }
 
 func result(ctx context.Context, gw gwclient.Client, st marshalable) (*gwclient.Result, error) {
@@ -813,9 +1290,15 @@ type buildkitBuilder struct {
 }
 
 func (runtime *Buildkit) NewBuilder(client gwclient.Client) *buildkitBuilder {
+	return runtime.NewPlatformBuilder(client, runtime.Platform)
+}
+
+// NewPlatformBuilder returns a builder which builds for the given platform
+// rather than the runtime's platform.
+func (runtime *Buildkit) NewPlatformBuilder(client gwclient.Client, platform ocispecs.Platform) *buildkitBuilder {
 	return NewBuilder(
 		client,
-		runtime.Platform,
+		platform,
 		runtime.Inputs,
 		runtime.Config.CertsDir,
 		runtime.secrets,
@@ -852,6 +1335,15 @@ func (b *buildkitBuilder) Build(
 	forceExec bool,
 	extraOpts ...llb.RunOption,
 ) (IntermediateBuild, error) {
+	if thunk.Resources != nil && !thunk.Insecure {
+		// limits are enforced by the shim, which can only manage cgroups with
+		// elevated privileges
+		return IntermediateBuild{}, UnsupportedError{
+			Runtime: BuildkitName,
+			Feature: "resource limits without insecure!",
+		}
+	}
+
 	ib, err := b.image(ctx, thunk.Image)
 	if err != nil {
 		return ib, err
@@ -867,38 +1359,15 @@ func (b *buildkitBuilder) Build(
 		return ib, err
 	}
 
-	// propagate thunk's entrypoint to the child
-	if len(thunk.Entrypoint) > 0 || thunk.ClearEntrypoint {
-		ib.Config.Entrypoint = thunk.Entrypoint
-	}
-
-	// propagate thunk's default command
-	if len(thunk.DefaultArgs) > 0 || thunk.ClearDefaultArgs {
-		ib.Config.Cmd = thunk.DefaultArgs
+	ib.Config, err = thunkImageConfig(ib.Config, thunk)
+	if err != nil {
+		return ib, err
 	}
 
-	if thunk.Labels != nil {
-		ib.Config.Labels = map[string]string{}
-		err := thunk.Labels.Each(func(k bass.Symbol, v bass.Value) error {
-			var str string
-			if err := v.Decode(&str); err != nil {
-				return err
-			}
-
-			ib.Config.Labels[k.String()] = str
-			return nil
-		})
+	if thunk.ImageConfig != nil {
+		ib, err = ib.withThunkImageConfig(*thunk.ImageConfig)
 		if err != nil {
-			return ib, fmt.Errorf("labels: %w", err)
-		}
-	}
-
-	if len(thunk.Ports) > 0 {
-		if ib.Config.ExposedPorts == nil {
-			ib.Config.ExposedPorts = map[string]struct{}{}
-		}
-		for _, port := range thunk.Ports {
-			ib.Config.ExposedPorts[fmt.Sprintf("%d/tcp", port.Port)] = struct{}{}
+			return ib, err
 		}
 	}
 
@@ -921,11 +1390,33 @@ func (b *buildkitBuilder) Build(
 		return ib, fmt.Errorf("no command specified")
 	}
 
+	// the command is run as the thunk's user rather than by the shim
+	cmd.User = ""
+
 	cmdPayload, err := bass.MarshalJSON(cmd)
 	if err != nil {
 		return ib, err
 	}
 
+	ioSt := llb.Scratch().File(
+		llb.Mkfile("in", 0600, cmdPayload),
+		llb.WithCustomNamef("[hide] mount command json for %s", thunk.String()),
+	)
+
+	var ioOpts []llb.MountOption
+	if thunk.User != "" {
+		// NB: create the directory on the image so that the user is resolved
+		// from its /etc/passwd
+		ioSt = ib.FS.File(
+			llb.Mkdir(ioUserDir, 0700, llb.WithUser(thunk.User)).
+				Mkfile(path.Join(ioUserDir, "in"), 0600, cmdPayload, llb.WithUser(thunk.User)),
+			llb.WithCustomNamef("[hide] mount command json for %s", thunk.String()),
+		)
+
+		ib.IOSourcePath = ioUserDir
+		ioOpts = append(ioOpts, llb.SourcePath(ioUserDir))
+	}
+
 	shimExe, err := shim(b.platform.Architecture)
 	if err != nil {
 		return ib, err
@@ -935,10 +1426,7 @@ func (b *buildkitBuilder) Build(
 		llb.WithCustomName(thunk.Cmdline()),
 		llb.AddMount("/tmp", llb.Scratch(), llb.Tmpfs()),
 		llb.AddMount("/dev/shm", llb.Scratch(), llb.Tmpfs()),
-		llb.AddMount(ioDir, llb.Scratch().File(
-			llb.Mkfile("in", 0600, cmdPayload),
-			llb.WithCustomNamef("[hide] mount command json for %s", thunk.String()),
-		)),
+		llb.AddMount(ioDir, ioSt, ioOpts...),
 		llb.AddMount(shimExePath, shimExe, llb.SourcePath("run")),
 		llb.With(llb.Dir(workDir)),
 		llb.AddEnv("_BASS_OUTPUT", outputFile),
@@ -1006,6 +1494,10 @@ func (b *buildkitBuilder) Build(
 		)
 	}
 
+	if thunk.User != "" {
+		runOpt = append(runOpt, llb.User(thunk.User))
+	}
+
 	if b.debug {
 		runOpt = append(runOpt, llb.AddEnv("_BASS_DEBUG", "1"))
 	}
@@ -1023,6 +1515,13 @@ func (b *buildkitBuilder) Build(
 			llb.Security(llb.SecurityModeInsecure))
 	}
 
+	switch thunk.Network {
+	case bass.NetworkModeNone:
+		runOpt = append(runOpt, llb.Network(pb.NetMode_NONE))
+	case bass.NetworkModeHost:
+		runOpt = append(runOpt, llb.Network(pb.NetMode_HOST))
+	}
+
 	var remountedWorkdir bool
 	for _, mount := range cmd.Mounts {
 		var targetPath string
@@ -1046,31 +1545,147 @@ func (b *buildkitBuilder) Build(
 			ib.NeedsInsecure = true
 		}
 
-		runOpt = append(runOpt, mountOpt)
-	}
+		runOpt = append(runOpt, mountOpt)
+	}
+
+	if !remountedWorkdir {
+		if ib.OutputSourcePath != "" {
+			// NB: could just call SourcePath with "", but this is to ensure there's
+			// code coverage
+			runOpt = append(runOpt, llb.AddMount(workDir, ib.Output, llb.SourcePath(ib.OutputSourcePath)))
+		} else {
+			runOpt = append(runOpt, llb.AddMount(workDir, ib.Output))
+		}
+	}
+
+	if b.disableCache {
+		runOpt = append(runOpt, llb.IgnoreCache)
+	}
+
+	runOpt = append(runOpt, extraOpts...)
+
+	execSt := ib.FS.Run(runOpt...)
+	ib.Exec = execSt
+	ib.Output = execSt.GetMount(workDir)
+	ib.FS = execSt.Root()
+
+	return ib, nil
+}
+
+// DebugShell opens an interactive shell in the container of the command that
+// failed with the given error, with the same mounts, env, and working
+// directory. It returns once the shell exits.
+//
+// The container's filesystem is left in the state the failed command left it
+// in. Errors that did not come from running a command are ignored.
+func (b *buildkitBuilder) DebugShell(ctx context.Context, name string, solveErr error) error {
+	var se *errdefs.SolveError
+	if !errors.As(solveErr, &se) {
+		return nil
+	}
+
+	exec := se.Op.GetExec()
+	if exec == nil {
+		return nil
+	}
+
+	if len(se.MountIDs) != len(exec.Mounts) || len(se.InputIDs) != len(exec.Mounts) {
+		return fmt.Errorf("debug shell: have %d mounts, %d results, and %d inputs", len(exec.Mounts), len(se.MountIDs), len(se.InputIDs))
+	}
+
+	mounts := make([]gwclient.Mount, len(exec.Mounts))
+	for i, mnt := range exec.Mounts {
+		resultID := se.MountIDs[i]
+		if mnt.Dest == ioDir {
+			// the shim removes the command payload once it's read, so mount the
+			// initial state instead
+			resultID = se.InputIDs[i]
+		}
+
+		mounts[i] = gwclient.Mount{
+			Selector:  mnt.Selector,
+			Dest:      mnt.Dest,
+			ResultID:  resultID,
+			Readonly:  mnt.Readonly,
+			MountType: mnt.MountType,
+			CacheOpt:  mnt.CacheOpt,
+			SecretOpt: mnt.SecretOpt,
+			SSHOpt:    mnt.SSHOpt,
+		}
+	}
+
+	term, err := bass.OpenTerminal(ctx, "debug "+name)
+	if err != nil {
+		return fmt.Errorf("debug shell: %w", err)
+	}
+
+	defer term.Close()
+
+	container, err := b.gw.NewContainer(ctx, gwclient.NewContainerRequest{
+		Mounts:   mounts,
+		Hostname: exec.Meta.Hostname,
+		NetMode:  exec.Network,
+		Platform: se.Op.Platform,
+	})
+	if err != nil {
+		return fmt.Errorf("debug shell: %w", err)
+	}
+
+	// NB: use a different ctx than the one that'll be interrupted for anything
+	// that needs to run as part of post-interruption cleanup
+	cleanupCtx := context.Background()
+
+	defer container.Release(cleanupCtx)
 
-	if !remountedWorkdir {
-		if ib.OutputSourcePath != "" {
-			// NB: could just call SourcePath with "", but this is to ensure there's
-			// code coverage
-			runOpt = append(runOpt, llb.AddMount(workDir, ib.Output, llb.SourcePath(ib.OutputSourcePath)))
-		} else {
-			runOpt = append(runOpt, llb.AddMount(workDir, ib.Output))
-		}
+	proc, err := container.Start(ctx, gwclient.StartRequest{
+		Args:         []string{shimExePath, "shell", inputFile},
+		Env:          exec.Meta.Env,
+		User:         exec.Meta.User,
+		Cwd:          exec.Meta.Cwd,
+		Tty:          true,
+		Stdin:        io.NopCloser(term),
+		Stdout:       nopCloser{term},
+		Stderr:       nopCloser{term},
+		SecurityMode: exec.Security,
+	})
+	if err != nil {
+		return fmt.Errorf("debug shell: %w", err)
 	}
 
-	if b.disableCache {
-		runOpt = append(runOpt, llb.IgnoreCache)
-	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- proc.Wait()
+	}()
 
-	runOpt = append(runOpt, extraOpts...)
+	for {
+		select {
+		case size := <-term.Resized():
+			err := proc.Resize(ctx, gwclient.WinSize{
+				Rows: uint32(size.Rows),
+				Cols: uint32(size.Cols),
+			})
+			if err != nil {
+				zapctx.FromContext(ctx).Warn("resize debug shell", zap.Error(err))
+			}
+		case err := <-exited:
+			// the shell's exit status is not interesting; the user exited it
+			var exitErr *gwpb.ExitError
+			if errors.As(err, &exitErr) {
+				return nil
+			}
 
-	execSt := ib.FS.Run(runOpt...)
-	ib.Exec = execSt
-	ib.Output = execSt.GetMount(workDir)
-	ib.FS = execSt.Root()
+			return err
+		case <-ctx.Done():
+			err := proc.Signal(cleanupCtx, syscall.SIGKILL)
+			if err != nil {
+				return fmt.Errorf("interrupt debug shell: %w", err)
+			}
 
-	return ib, nil
+			<-exited
+
+			return ctx.Err()
+		}
+	}
 }
 
 func shim(arch string) (llb.State, error) {
@@ -1119,19 +1734,24 @@ type IntermediateBuild struct {
 	Output llb.State
 
 	OutputSourcePath string
+	IOSourcePath     string
 	NeedsInsecure    bool
 
-	Platform ocispecs.Platform
-	Config   ocispecs.ImageConfig
+	Platform    ocispecs.Platform
+	Config      ocispecs.ImageConfig
+	Healthcheck *image.HealthConfig
+	Annotations map[string]string
 }
 
 func (ib IntermediateBuild) WithImageConfig(config []byte) (IntermediateBuild, error) {
-	var img ocispecs.Image
+	// NB: parse as a Docker image to preserve its healthcheck
+	var img image.Image
 	if err := json.Unmarshal(config, &img); err != nil {
 		return ib, err
 	}
 
-	ib.Config = img.Config
+	ib.Config = img.Config.ImageConfig
+	ib.Healthcheck = img.Config.Healthcheck
 
 	for _, env := range img.Config.Env {
 		parts := strings.SplitN(env, "=", 2)
@@ -1156,6 +1776,142 @@ func (ib IntermediateBuild) WithImageConfig(config []byte) (IntermediateBuild, e
 	return ib, nil
 }
 
+// thunkImageConfig propagates the thunk's entrypoint, default command, user,
+// labels, and ports to the image config.
+func thunkImageConfig(config ocispecs.ImageConfig, thunk bass.Thunk) (ocispecs.ImageConfig, error) {
+	if len(thunk.Entrypoint) > 0 || thunk.ClearEntrypoint {
+		config.Entrypoint = thunk.Entrypoint
+	}
+
+	if len(thunk.DefaultArgs) > 0 || thunk.ClearDefaultArgs {
+		config.Cmd = thunk.DefaultArgs
+	}
+
+	if thunk.User != "" {
+		config.User = thunk.User
+	}
+
+	if thunk.Labels != nil {
+		// NB: copy to keep the parent image's labels without modifying them
+		labels := map[string]string{}
+		for k, v := range config.Labels {
+			labels[k] = v
+		}
+
+		err := thunk.Labels.Each(func(k bass.Symbol, v bass.Value) error {
+			var str string
+			if err := v.Decode(&str); err != nil {
+				return err
+			}
+
+			labels[k.String()] = str
+			return nil
+		})
+		if err != nil {
+			return config, fmt.Errorf("labels: %w", err)
+		}
+
+		config.Labels = labels
+	}
+
+	if len(thunk.Ports) > 0 {
+		// NB: copy for the same reason as labels
+		ports := map[string]struct{}{}
+		for port := range config.ExposedPorts {
+			ports[port] = struct{}{}
+		}
+
+		for _, port := range thunk.Ports {
+			ports[fmt.Sprintf("%d/tcp", port.Port)] = struct{}{}
+		}
+
+		config.ExposedPorts = ports
+	}
+
+	return config, nil
+}
+
+// withThunkImageConfig applies the config that only affects the published
+// image.
+func (ib IntermediateBuild) withThunkImageConfig(cfg bass.ThunkImageConfig) (IntermediateBuild, error) {
+	if cfg.WorkingDir != "" {
+		ib.Config.WorkingDir = cfg.WorkingDir
+	}
+
+	if cfg.StopSignal != "" {
+		ib.Config.StopSignal = cfg.StopSignal
+	}
+
+	if len(cfg.Volumes) > 0 {
+		// NB: copy to avoid modifying the parent image's config
+		volumes := map[string]struct{}{}
+		for vol := range ib.Config.Volumes {
+			volumes[vol] = struct{}{}
+		}
+		for _, vol := range cfg.Volumes {
+			volumes[vol] = struct{}{}
+		}
+		ib.Config.Volumes = volumes
+	}
+
+	if hc := cfg.Healthcheck; hc != nil {
+		ib.Healthcheck = &image.HealthConfig{
+			Test:        append([]string{"CMD"}, hc.Command...),
+			Interval:    hc.Interval,
+			Timeout:     hc.Timeout,
+			StartPeriod: hc.StartPeriod,
+			Retries:     hc.Retries,
+		}
+	}
+
+	if cfg.Annotations != nil {
+		annotations := map[string]string{}
+		for k, v := range ib.Annotations {
+			annotations[k] = v
+		}
+
+		err := cfg.Annotations.Each(func(k bass.Symbol, v bass.Value) error {
+			var str string
+			if err := v.Decode(&str); err != nil {
+				return err
+			}
+
+			annotations[k.String()] = str
+			return nil
+		})
+		if err != nil {
+			return ib, fmt.Errorf("annotations: %w", err)
+		}
+
+		ib.Annotations = annotations
+	}
+
+	if cfg.Labels != nil {
+		// NB: copy to avoid modifying the parent image's config
+		labels := map[string]string{}
+		for k, v := range ib.Config.Labels {
+			labels[k] = v
+		}
+
+		err := cfg.Labels.Each(func(k bass.Symbol, v bass.Value) error {
+			var str string
+			if err := v.Decode(&str); err != nil {
+				return err
+			}
+
+			labels[k.String()] = str
+			return nil
+		})
+		if err != nil {
+			return ib, fmt.Errorf("image labels: %w", err)
+		}
+
+		ib.Config.Labels = labels
+	}
+
+	return ib, nil
+}
+
 func (ib IntermediateBuild) ForPublish(ctx context.Context, gw gwclient.Client) (*gwclient.Result, error) {
 	def, err := ib.FS.Marshal(ctx)
 	if err != nil {
@@ -1170,18 +1926,77 @@ func (ib IntermediateBuild) ForPublish(ctx context.Context, gw gwclient.Client)
 		return nil, err
 	}
 
-	cfgBytes, err := json.Marshal(ocispecs.Image{
-		Platform: ib.Platform,
-		Config:   ib.Config,
-	})
+	cfgBytes, err := ib.imageConfig()
 	if err != nil {
 		return nil, err
 	}
 	res.AddMeta(exptypes.ExporterImageConfigKey, cfgBytes)
 
+	for k, v := range ib.Annotations {
+		res.AddMeta(exptypes.AnnotationManifestKey(nil, k), []byte(v))
+	}
+
+	return res, nil
+}
+
+// ForPublishIndex solves each build and returns a result that exports an image
+// index with an image for each build's platform.
+func ForPublishIndex(ctx context.Context, gw gwclient.Client, ibs []IntermediateBuild) (*gwclient.Result, error) {
+	res := gwclient.NewResult()
+
+	var expPlatforms exptypes.Platforms
+	for _, ib := range ibs {
+		platformRes, err := ib.ForPublish(ctx, gw)
+		if err != nil {
+			return nil, err
+		}
+
+		ref, err := platformRes.SingleRef()
+		if err != nil {
+			return nil, err
+		}
+
+		cfgBytes, err := ib.imageConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		key := platforms.Format(ib.Platform)
+		res.AddRef(key, ref)
+		res.AddMeta(fmt.Sprintf("%s/%s", exptypes.ExporterImageConfigKey, key), cfgBytes)
+
+		platform := ib.Platform
+		for k, v := range ib.Annotations {
+			res.AddMeta(exptypes.AnnotationManifestKey(&platform, k), []byte(v))
+		}
+
+		expPlatforms.Platforms = append(expPlatforms.Platforms, exptypes.Platform{
+			ID:       key,
+			Platform: ib.Platform,
+		})
+	}
+
+	platformsBytes, err := json.Marshal(expPlatforms)
+	if err != nil {
+		return nil, err
+	}
+	res.AddMeta(exptypes.ExporterPlatformsKey, platformsBytes)
+
 	return res, nil
 }
 
+func (ib IntermediateBuild) imageConfig() ([]byte, error) {
+	return json.Marshal(image.Image{
+		Image: ocispecs.Image{
+			Platform: ib.Platform,
+		},
+		Config: image.ImageConfig{
+			ImageConfig: ib.Config,
+			Healthcheck: ib.Healthcheck,
+		},
+	})
+}
+
 func (ib IntermediateBuild) ForRun(ctx context.Context, gw gwclient.Client) (*gwclient.Result, error) {
 	def, err := ib.Exec.Marshal(ctx)
 	if err != nil {
@@ -1194,33 +2009,48 @@ func (ib IntermediateBuild) ForRun(ctx context.Context, gw gwclient.Client) (*gw
 	})
 }
 
-func (ib IntermediateBuild) ReadStdout(ctx context.Context, gw gwclient.Client, w io.Writer) (*gwclient.Result, error) {
+// ReadStdout solves the command and writes its stdout to the tap.
+//
+// While the command runs its output is streamed from the build's logs. Once
+// it exits, or if it was cached, the rest is copied from the output file.
+func (ib IntermediateBuild) ReadStdout(ctx context.Context, gw gwclient.Client, tap *outputTap) (*gwclient.Result, error) {
 	def, err := ib.Exec.GetMount(ioDir).Marshal(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// the last op just selects the output, so its input is the exec op
+	var last pb.Op
+	if err := last.Unmarshal(def.Def[len(def.Def)-1]); err != nil {
+		return nil, err
+	}
+
+	tap.Watch(last.Inputs[0].Digest)
+
 	res, err := gw.Solve(ctx, gwclient.SolveRequest{
 		Evaluate:   true,
 		Definition: def.ToPB(),
 	})
 	if err != nil {
+		tap.Stop()
 		return nil, err
 	}
 
 	ref, err := res.SingleRef()
 	if err != nil {
+		tap.Stop()
 		return nil, err
 	}
 
 	fs := util.NewRefFS(ctx, ref)
 
-	f, err := fs.Open(path.Base(outputFile))
+	f, err := fs.Open(ib.ioPath(outputFile))
 	if err != nil {
+		tap.Stop()
 		return nil, err
 	}
 
-	if _, err := io.Copy(w, f); err != nil {
+	if err := tap.Finish(f); err != nil {
 		return nil, err
 	}
 
@@ -1231,6 +2061,70 @@ func (ib IntermediateBuild) ReadStdout(ctx context.Context, gw gwclient.Client,
 	return res, nil
 }
 
+// ReadResult solves the command configured to record its exit code and reads
+// its result, along with the ID of the run which produced it.
+func (ib IntermediateBuild) ReadResult(ctx context.Context, gw gwclient.Client, result *bass.RunResult, runID *string) (*gwclient.Result, error) {
+	def, err := ib.Exec.GetMount(ioDir).Marshal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := gw.Solve(ctx, gwclient.SolveRequest{
+		Evaluate:   true,
+		Definition: def.ToPB(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := res.SingleRef()
+	if err != nil {
+		return nil, err
+	}
+
+	result.Stdout, err = ref.ReadFile(ctx, gwclient.ReadRequest{
+		Filename: ib.ioPath(outputFile),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Stderr, err = ref.ReadFile(ctx, gwclient.ReadRequest{
+		Filename: ib.ioPath(stderrFile),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exitCode, err := ref.ReadFile(ctx, gwclient.ReadRequest{
+		Filename: ib.ioPath(exitCodeFile),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.ExitCode, err = strconv.Atoi(string(exitCode))
+	if err != nil {
+		return nil, fmt.Errorf("malformed exit code: %w", err)
+	}
+
+	id, err := ref.ReadFile(ctx, gwclient.ReadRequest{
+		Filename: ib.ioPath(runIDFile),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	*runID = string(id)
+
+	return res, nil
+}
+
+// ioPath returns the path to a file in ioDir within its mount's output.
+func (ib IntermediateBuild) ioPath(file string) string {
+	return path.Join(ib.IOSourcePath, path.Base(file))
+}
+
 func (ib IntermediateBuild) ForExportDir(ctx context.Context, gw gwclient.Client, fsp bass.DirPath) (*gwclient.Result, error) {
 	copyOpt := &llb.CopyInfo{
 		IncludePatterns: fsp.Includes(),
@@ -1782,6 +2676,108 @@ func (b *buildkitBuilder) fsPathSt(ctx context.Context, source bass.FSPath) (llb
 	}
 }
 
+type outputTapKey struct{}
+
+// clippedLogMarker is appended to a log by Buildkit when it exceeds the log
+// size or speed limit.
+var clippedLogMarker = []byte("\n[output clipped, log limit ")
+
+// outputTap streams a command's stdout from the build's logs to a writer so
+// that it can be read before the command exits.
+//
+// Only a prefix of the output is streamed; Buildkit clips logs that exceed its
+// limits, so streaming stops as soon as the output is clipped and Finish
+// copies the rest from the output file.
+//
+// The logs are not guaranteed to match the output file, e.g. if the command
+// was retried, so Finish verifies the streamed prefix against it.
+type outputTap struct {
+	w io.Writer
+
+	mu       sync.Mutex
+	vertex   digest.Digest
+	written  int64
+	streamed hash.Hash
+	stopped  bool
+	err      error
+}
+
+// Watch configures the tap to stream the stdout logs of the given vertex.
+func (tap *outputTap) Watch(vertex digest.Digest) {
+	tap.mu.Lock()
+	tap.vertex = vertex
+	tap.mu.Unlock()
+}
+
+// Log writes the log's data if it is stdout of the watched vertex.
+func (tap *outputTap) Log(log *bkclient.VertexLog) {
+	if log.Stream != 1 {
+		return
+	}
+
+	tap.mu.Lock()
+	defer tap.mu.Unlock()
+
+	if tap.stopped || tap.vertex == "" || log.Vertex != tap.vertex {
+		return
+	}
+
+	data := log.Data
+	if idx := bytes.Index(data, clippedLogMarker); idx != -1 {
+		data = data[:idx]
+		tap.stopped = true
+	}
+
+	if tap.streamed == nil {
+		tap.streamed = sha256.New()
+	}
+
+	n, err := tap.w.Write(data)
+	tap.written += int64(n)
+	tap.streamed.Write(data[:n])
+	if err != nil {
+		tap.err = err
+		tap.stopped = true
+	}
+}
+
+// Stop stops streaming logs.
+func (tap *outputTap) Stop() {
+	tap.mu.Lock()
+	tap.stopped = true
+	tap.mu.Unlock()
+}
+
+// Finish stops streaming logs and copies the remaining output that was not
+// already streamed.
+//
+// If the streamed logs differ from the start of the output an error is
+// returned, since they've already been written.
+func (tap *outputTap) Finish(output io.Reader) error {
+	tap.mu.Lock()
+	defer tap.mu.Unlock()
+
+	tap.stopped = true
+
+	if tap.err != nil {
+		return tap.err
+	}
+
+	if tap.written > 0 {
+		prefix := sha256.New()
+		if _, err := io.CopyN(prefix, output, tap.written); err != nil {
+			return fmt.Errorf("verify streamed output: %w", err)
+		}
+
+		if !bytes.Equal(prefix.Sum(nil), tap.streamed.Sum(nil)) {
+			return fmt.Errorf("streamed output does not match the command's output")
+		}
+	}
+
+	_, err := io.Copy(tap.w, output)
+	return err
+}
+
 type nopCloser struct {
 	io.Writer
 }
@@ -1803,6 +2799,7 @@ type statusProxy struct {
 	rec  *progrock.Recorder
 	wg   *sync.WaitGroup
 	prog *cli.Progress
+	tap  *outputTap
 }
 
 func (proxy *statusProxy) proxy(rec *progrock.Recorder, statuses chan *bkclient.SolveStatus) {
@@ -1812,6 +2809,12 @@ func (proxy *statusProxy) proxy(rec *progrock.Recorder, statuses chan *bkclient.
 			break
 		}
 
+		if proxy.tap != nil {
+			for _, log := range status.Logs {
+				proxy.tap.Log(log)
+			}
+		}
+
 		update := bk2progrock(status)
 		proxy.prog.WriteStatus(update)
 		rec.Record(update)
@@ -1857,22 +2860,19 @@ func dialBuildkit(ctx context.Context, addr string, installation string, certsDi
 		}
 	}
 
-	var errs error
 	if addr == "" {
-		var startErr error
-		addr, startErr = buildkitd.Start(ctx, installation, certsDir)
-		if startErr != nil {
-			errs = multierror.Append(startErr)
+		var err error
+		addr, err = buildkitd.Start(ctx, installation, certsDir)
+		if errors.Is(err, buildkitd.ErrDockerUnavailable) || errors.Is(err, buildkitd.ErrImageUnavailable) {
+			// nothing was configured and the default installation isn't
+			// available; other runtimes may still be usable
+			return nil, BuildkitNotFoundError{Err: err}
+		} else if err != nil {
+			return nil, err
 		}
 	}
 
-	client, err := bkclient.New(context.TODO(), addr)
-	if err != nil {
-		errs = multierror.Append(errs, err)
-		return nil, errs
-	}
-
-	return client, nil
+	return bkclient.New(context.TODO(), addr)
 }
 
 type AnyDirSource struct{}
@@ -1965,6 +2965,22 @@ func (s *secretStore) PutSecret(value []byte) string {
 	return digestStr
 }
 
+// SetSecret sets a secret with the given ID rather than one derived from its
+// value.
+func (s *secretStore) SetSecret(id string, value []byte) {
+	s.Lock()
+	defer s.Unlock()
+	s.digest2secret[id] = value
+}
+
+// DeleteSecret removes a secret set with SetSecret once it is no longer
+// needed.
+func (s *secretStore) DeleteSecret(id string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.digest2secret, id)
+}
+
 func (s *secretStore) GetSecret(ctx context.Context, digest string) ([]byte, error) {
 	s.Lock()
 	defer s.Unlock()
@@ -1978,13 +2994,16 @@ func (s *secretStore) GetSecret(ctx context.Context, digest string) ([]byte, err
 }
 
 func newSolveOpt(
+	config BuildkitConfig,
 	authp session.Attachable,
 	secrets *secretStore,
 	ociStore content.Store,
 ) bkclient.SolveOpt {
 	return bkclient.SolveOpt{
+		CacheImports: cacheOptions(config.CacheImports),
 		AllowedEntitlements: []entitlements.Entitlement{
 			entitlements.EntitlementSecurityInsecure,
+			entitlements.EntitlementNetworkHost,
 		},
 		Session: []session.Attachable{
 			authp,
@@ -1997,6 +3016,18 @@ func newSolveOpt(
 	}
 }
 
+func cacheOptions(configs []BuildkitCacheConfig) []bkclient.CacheOptionsEntry {
+	var entries []bkclient.CacheOptionsEntry
+	for _, config := range configs {
+		entries = append(entries, bkclient.CacheOptionsEntry{
+			Type:  config.Type,
+			Attrs: config.Attrs,
+		})
+	}
+
+	return entries
+}
+
 type protoCache[T any] struct {
 	cache map[uint64]T
 	l     sync.Mutex
@@ -8,6 +8,7 @@ import (
 	"github.com/vito/bass/pkg/bass"
 	"github.com/vito/bass/pkg/runtimes"
 	"github.com/vito/bass/pkg/runtimes/util/buildkitd"
+	"github.com/vito/is"
 )
 
 func TestBuildkitRuntime(t *testing.T) {
@@ -35,5 +36,76 @@ func TestBuildkitRuntime(t *testing.T) {
 		Platform: bass.LinuxPlatform,
 		Runtime:  runtimes.BuildkitName,
 		Config:   config.Scope(),
-	})
+	}, runtimes.SkipSuites(
+		// limits are only enforced for insecure thunks
+		"resources.bass",
+	))
+}
+
+func TestParseBuildkitCacheConfig(t *testing.T) {
+	for _, example := range []struct {
+		Input  string
+		Config runtimes.BuildkitCacheConfig
+		Err    string
+	}{
+		{
+			Input: "type=local,src=./cache",
+			Config: runtimes.BuildkitCacheConfig{
+				Type:  "local",
+				Attrs: map[string]string{"src": "./cache"},
+			},
+		},
+		{
+			Input: "type=registry,ref=example/image:cache,mode=max",
+			Config: runtimes.BuildkitCacheConfig{
+				Type:  "registry",
+				Attrs: map[string]string{"ref": "example/image:cache", "mode": "max"},
+			},
+		},
+		{
+			Input: "type=inline",
+			Config: runtimes.BuildkitCacheConfig{
+				Type:  "inline",
+				Attrs: map[string]string{},
+			},
+		},
+		{
+			Input: "example/image:cache",
+			Config: runtimes.BuildkitCacheConfig{
+				Type:  "registry",
+				Attrs: map[string]string{"ref": "example/image:cache"},
+			},
+		},
+		{
+			Input: "src=./cache",
+			Err:   `cache config has no type: "src=./cache"`,
+		},
+		{
+			Input: "type=local,./cache",
+			Err:   `malformed cache config field: "./cache"`,
+		},
+	} {
+		example := example
+		t.Run(example.Input, func(t *testing.T) {
+			is := is.New(t)
+
+			config, err := runtimes.ParseBuildkitCacheConfig(example.Input)
+			if example.Err != "" {
+				is.True(err != nil)
+				is.Equal(err.Error(), example.Err)
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(config, example.Config)
+
+			// round-trip through the runtime config
+			var bkConfig runtimes.BuildkitConfig
+			err = bass.Bindings{
+				"cache_imports": bass.NewList(config.Scope()),
+			}.Scope().Decode(&bkConfig)
+			is.NoErr(err)
+			is.Equal(bkConfig.CacheImports, []runtimes.BuildkitCacheConfig{config})
+		})
+	}
 }
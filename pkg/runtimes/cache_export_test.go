@@ -0,0 +1,86 @@
+package runtimes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/client/llb"
+	gwclient "github.com/moby/buildkit/frontend/gateway/client"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/vito/is"
+)
+
+func TestCacheExport(t *testing.T) {
+	ctx := context.Background()
+
+	platform := ocispecs.Platform{OS: "linux", Architecture: "amd64"}
+
+	thunk := func(cmd string, opts ...llb.RunOption) IntermediateBuild {
+		opts = append([]llb.RunOption{llb.Shlex(cmd)}, opts...)
+		return IntermediateBuild{
+			FS:       llb.Image("alpine").Run(opts...).Root(),
+			Platform: platform,
+		}
+	}
+
+	config := BuildkitConfig{
+		CacheExports: []BuildkitCacheConfig{
+			{Type: "local", Attrs: map[string]string{"dest": "./cache"}},
+		},
+	}
+
+	t.Run("does not export on every solve", func(t *testing.T) {
+		is := is.New(t)
+
+		solveOpt := newSolveOpt(config, nil, newSecretStore(), nil)
+		is.Equal(len(solveOpt.CacheExports), 0)
+	})
+
+	t.Run("exports every built thunk at once", func(t *testing.T) {
+		is := is.New(t)
+
+		runtime := &Buildkit{Config: config}
+		is.NoErr(runtime.recordBuilt(ctx, []IntermediateBuild{thunk("echo one")}))
+		is.NoErr(runtime.recordBuilt(ctx, []IntermediateBuild{thunk("echo two", llb.IgnoreCache)}))
+		is.NoErr(runtime.recordBuilt(ctx, []IntermediateBuild{thunk("echo one")}))
+		is.Equal(len(runtime.built), 2)
+
+		gw := &solveRecorder{}
+		res, err := solveBuilt(ctx, gw, runtime.built)
+		is.NoErr(err)
+		is.Equal(len(res.Refs), 2)
+		is.Equal(len(gw.solved), 2)
+
+		for _, req := range gw.solved {
+			for _, meta := range req.Definition.Metadata {
+				is.True(!meta.IgnoreCache)
+			}
+		}
+	})
+
+	t.Run("records nothing without exports", func(t *testing.T) {
+		is := is.New(t)
+
+		runtime := &Buildkit{}
+		is.NoErr(runtime.recordBuilt(ctx, []IntermediateBuild{thunk("echo one")}))
+		is.Equal(len(runtime.built), 0)
+	})
+}
+
+type solveRecorder struct {
+	gwclient.Client
+
+	solved []gwclient.SolveRequest
+}
+
+func (gw *solveRecorder) Solve(ctx context.Context, req gwclient.SolveRequest) (*gwclient.Result, error) {
+	gw.solved = append(gw.solved, req)
+
+	res := gwclient.NewResult()
+	res.SetRef(nopRef{})
+	return res, nil
+}
+
+type nopRef struct {
+	gwclient.Reference
+}
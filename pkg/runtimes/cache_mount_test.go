@@ -0,0 +1,41 @@
+package runtimes
+
+import (
+	"testing"
+
+	"github.com/vito/is"
+)
+
+func TestCacheMountID(t *testing.T) {
+	for _, example := range []struct {
+		Description string
+		ID          string
+		OK          bool
+	}{
+		{
+			Description: `cached mount /var/cache from exec /bin/sh with id "apt-cache"`,
+			ID:          "apt-cache",
+			OK:          true,
+		},
+		{
+			Description: `cached mount /cache from exec with id "weird \"id\""`,
+			ID:          `weird "id"`,
+			OK:          true,
+		},
+		{
+			Description: `cached mount /var/cache from exec /bin/sh`,
+		},
+		{
+			Description: `cached mount /var/cache from exec /bin/sh with id unquoted`,
+		},
+	} {
+		example := example
+		t.Run(example.Description, func(t *testing.T) {
+			is := is.New(t)
+
+			id, ok := cacheMountID(example.Description)
+			is.Equal(ok, example.OK)
+			is.Equal(id, example.ID)
+		})
+	}
+}
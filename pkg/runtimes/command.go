@@ -23,6 +23,14 @@ type Command struct {
 	Env   []string `json:"env"`
 	Dir   *string  `json:"dir"`
 
+	// Resources configures limits enforced by the shim, for runtimes which
+	// can't enforce them on the container themselves.
+	Resources *bass.ThunkResources `json:"resources,omitempty"`
+
+	// User is the user the shim runs the command as, for runtimes which
+	// can't run the command as the user themselves.
+	User string `json:"user,omitempty"`
+
 	// these don't need to be marshaled, since they're part of the container
 	// setup and not passed to the shim
 	Mounts    []CommandMount     `json:"-"`
@@ -65,7 +73,14 @@ type PortInfos map[string]*bass.Scope
 // concrete paths in the container, and collecting the requisite mount points
 // along the way.
 func NewCommand(ctx context.Context, starter Starter, thunk bass.Thunk) (Command, error) {
+	if thunk.Network == bass.NetworkModeHost && !thunk.Insecure {
+		return Command{}, fmt.Errorf("network mode %s requires insecure", thunk.Network)
+	}
+
 	cmd := &Command{
+		Resources: thunk.Resources,
+		User:      thunk.User,
+
 		mounted: map[string]bool{},
 		starter: starter,
 	}
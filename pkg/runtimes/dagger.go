@@ -76,13 +76,15 @@ func (runtime *Dagger) Resolve(ctx context.Context, imageRef bass.ImageRef) (bas
 }
 
 func (runtime *Dagger) Run(ctx context.Context, thunk bass.Thunk) error {
+	ctx, done := thunk.TimeoutContext(ctx)
+
 	ctr, err := runtime.Container(ctx, thunk, true)
 	if err != nil {
-		return err
+		return done(err)
 	}
 
 	_, err = ctr.Sync(ctx)
-	return err
+	return done(err)
 }
 
 func (runtime *Dagger) Start(ctx context.Context, thunk bass.Thunk) (StartResult, error) {
@@ -102,22 +104,53 @@ func (runtime *Dagger) Start(ctx context.Context, thunk bass.Thunk) (StartResult
 }
 
 func (runtime *Dagger) Read(ctx context.Context, w io.Writer, thunk bass.Thunk) error {
+	ctx, done := thunk.TimeoutContext(ctx)
+
 	ctr, err := runtime.Container(ctx, thunk, true)
 	if err != nil {
-		return err
+		return done(err)
 	}
 
 	stdout, err := ctr.Stdout(ctx)
 	if err != nil {
-		return err
+		return done(err)
 	}
 
 	_, err = fmt.Fprint(w, stdout)
+	return done(err)
+}
+
+func (runtime *Dagger) RunResult(ctx context.Context, thunk bass.Thunk) (bass.RunResult, error) {
+	ctx, done := thunk.TimeoutContext(ctx)
+
+	ctr, err := runtime.Container(ctx, thunk, true)
 	if err != nil {
-		return err
+		return bass.RunResult{}, done(err)
 	}
 
-	return nil
+	stdout, err := ctr.Stdout(ctx)
+	if err != nil {
+		var execErr *dagger.ExecError
+		if errors.As(err, &execErr) {
+			return bass.RunResult{
+				ExitCode: execErr.ExitCode,
+				Stdout:   []byte(execErr.Stdout),
+				Stderr:   []byte(execErr.Stderr),
+			}, done(nil)
+		}
+
+		return bass.RunResult{}, done(err)
+	}
+
+	stderr, err := ctr.Stderr(ctx)
+	if err != nil {
+		return bass.RunResult{}, done(err)
+	}
+
+	return bass.RunResult{
+		Stdout: []byte(stdout),
+		Stderr: []byte(stderr),
+	}, done(nil)
 }
 
 func (runtime *Dagger) Publish(ctx context.Context, ref bass.ImageRef, thunk bass.Thunk) (bass.ImageRef, error) {
@@ -131,7 +164,7 @@ func (runtime *Dagger) Publish(ctx context.Context, ref bass.ImageRef, thunk bas
 		return ref, err
 	}
 
-	fqref, err := ctr.Publish(ctx, addr)
+	fqref, err := withRegistryAuth(ctx, ctr).Publish(ctx, addr)
 	if err != nil {
 		return ref, err
 	}
@@ -151,7 +184,65 @@ func (runtime *Dagger) Publish(ctx context.Context, ref bass.ImageRef, thunk bas
 	return ref, nil
 }
 
-func (runtime *Dagger) Export(ctx context.Context, w io.Writer, thunk bass.Thunk) error {
+func (runtime *Dagger) PublishIndex(ctx context.Context, ref bass.ImageRef, thunks []bass.Thunk) (bass.ImageRef, error) {
+	variants := make([]*dagger.Container, len(thunks))
+	for i, thunk := range thunks {
+		ctr, err := runtime.Container(ctx, thunk, false)
+		if err != nil {
+			return ref, err
+		}
+
+		variants[i] = ctr
+	}
+
+	addr, err := ref.Ref()
+	if err != nil {
+		return ref, err
+	}
+
+	fqref, err := withRegistryAuth(ctx, dag.Container()).Publish(ctx, addr, dagger.ContainerPublishOpts{
+		PlatformVariants: variants,
+	})
+	if err != nil {
+		return ref, err
+	}
+
+	fq, err := reference.ParseNamed(fqref)
+	if err != nil {
+		return ref, err
+	}
+
+	canon, ok := fq.(reference.Canonical)
+	if !ok {
+		return ref, fmt.Errorf("Dagger did not return a canonical reference: %T: %s", fq, fqref)
+	}
+
+	ref.Digest = canon.Digest().String()
+
+	return ref, nil
+}
+
+// withRegistryAuth configures the container with any credentials set by
+// bass.WithRegistryAuth.
+func withRegistryAuth(ctx context.Context, ctr *dagger.Container) *dagger.Container {
+	for _, auth := range bass.RegistryAuthsFromContext(ctx) {
+		secret := dag.SetSecret(auth.Secret.Name, string(auth.Secret.Reveal()))
+		ctr = ctr.WithRegistryAuth(auth.Host, auth.Username, secret)
+	}
+
+	return ctr
+}
+
+func (runtime *Dagger) Export(ctx context.Context, w io.Writer, thunk bass.Thunk, opts bass.ExportOpts) error {
+	switch opts.Format {
+	case bass.ExportOCI, bass.ExportRootfs, "":
+	default:
+		return UnsupportedError{
+			Runtime: DaggerName,
+			Feature: fmt.Sprintf("export format %s", opts.Format),
+		}
+	}
+
 	ctr, err := runtime.Container(ctx, thunk, false)
 	if err != nil {
 		return err
@@ -164,6 +255,19 @@ func (runtime *Dagger) Export(ctx context.Context, w io.Writer, thunk bass.Thunk
 
 	defer os.RemoveAll(dir)
 
+	if opts.Format == bass.ExportRootfs {
+		ok, err := ctr.Rootfs().Export(ctx, dir)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return fmt.Errorf("write to export dir: not ok")
+		}
+
+		return fsutil.WriteTar(ctx, fsutil.NewFS(dir, &fsutil.WalkOpt{}), w)
+	}
+
 	image := filepath.Join(dir, "image.tar")
 	ok, err := ctr.Export(ctx, image)
 	if err != nil {
@@ -221,11 +325,38 @@ func (runtime *Dagger) Prune(ctx context.Context, opts bass.PruneOpts) error {
 	return errors.New("Prune: not implemented")
 }
 
+func (runtime *Dagger) Caches(ctx context.Context) ([]bass.CacheInfo, error) {
+	return nil, errors.New("Caches: not implemented")
+}
+
 func (runtime *Dagger) Close() error {
 	return nil
 }
 
 func (runtime *Dagger) Container(ctx context.Context, thunk bass.Thunk, forceExec bool) (*dagger.Container, error) {
+	if thunk.Resources != nil {
+		return nil, UnsupportedError{
+			Runtime: DaggerName,
+			Feature: "resource limits (with-cpu, with-memory, with-pids-limit)",
+		}
+	}
+
+	if thunk.Network != bass.NetworkModeDefault {
+		return nil, UnsupportedError{
+			Runtime: DaggerName,
+			Feature: fmt.Sprintf("network mode %s", thunk.Network),
+		}
+	}
+
+	if cfg := thunk.ImageConfig; cfg != nil {
+		if cfg.Annotations != nil || cfg.StopSignal != "" || cfg.Healthcheck != nil || len(cfg.Volumes) > 0 {
+			return nil, UnsupportedError{
+				Runtime: DaggerName,
+				Feature: "image config other than the working directory (with-annotation, with-stop-signal, with-healthcheck, with-volume)",
+			}
+		}
+	}
+
 	cmd, err := NewCommand(ctx, runtime, thunk)
 	if err != nil {
 		return nil, err
@@ -248,6 +379,18 @@ func (runtime *Dagger) Container(ctx context.Context, thunk bass.Thunk, forceExe
 		})
 	}
 
+	if cfg := thunk.ImageConfig; cfg != nil && cfg.Labels != nil {
+		_ = cfg.Labels.Each(func(k bass.Symbol, v bass.Value) error {
+			var s string
+			if err := v.Decode(&s); err != nil {
+				s = v.String()
+			}
+
+			ctr = ctr.WithLabel(k.String(), s)
+			return nil
+		})
+	}
+
 	for _, port := range thunk.Ports {
 		ctr = ctr.WithExposedPort(port.Port, dagger.ContainerWithExposedPortOpts{
 			Description: port.Name,
@@ -299,6 +442,10 @@ func (runtime *Dagger) Container(ctx context.Context, thunk bass.Thunk, forceExe
 		ctr = ctr.WithSecretVariable(env.Name, secret)
 	}
 
+	if thunk.User != "" {
+		ctr = ctr.WithUser(thunk.User)
+	}
+
 	if len(cmd.Args) > 0 {
 		ctr = ctr.WithExec(cmd.Args, dagger.ContainerWithExecOpts{
 			Stdin:                    string(cmd.Stdin),
@@ -317,6 +464,11 @@ func (runtime *Dagger) Container(ctx context.Context, thunk bass.Thunk, forceExe
 		ctr = ctr.WithDefaultArgs(thunk.DefaultArgs)
 	}
 
+	// NB: set after the command runs so that it is only set in the image config
+	if thunk.ImageConfig != nil && thunk.ImageConfig.WorkingDir != "" {
+		ctr = ctr.WithWorkdir(thunk.ImageConfig.WorkingDir)
+	}
+
 	return ctr, nil
 }
 
@@ -441,7 +593,9 @@ func (runtime *Dagger) image(ctx context.Context, image *bass.ThunkImage) (*dagg
 			return nil, err
 		}
 
-		return basics(dag.Container().From(ref)), nil
+		return basics(dag.Container(dagger.ContainerOpts{
+			Platform: dagger.Platform(image.Ref.Platform.String()),
+		}).From(ref)), nil
 
 	case image.Thunk != nil:
 		ctr, err := runtime.Container(ctx, *image.Thunk, false)
@@ -28,5 +28,9 @@ func TestDaggerRuntime(t *testing.T) {
 		"tls.bass",
 		"cache-cmd.bass",
 		"globs.bass",
+		"resources.bass",
+		"network.bass",
+		"read-stream.bass",
+		"export-formats.bass",
 	))
 }
@@ -0,0 +1,1512 @@
+package runtimes
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/moby/buildkit/exporter/containerimage/image"
+	"github.com/moby/buildkit/identity"
+	"github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/tonistiigi/units"
+	"github.com/vito/progrock"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/cli"
+	"github.com/vito/bass/pkg/ioctx"
+)
+
+const DockerName = "docker"
+
+func init() {
+	RegisterRuntime(DockerName, NewDocker)
+}
+
+const (
+	// dockerNetwork is the network that thunks and services run in, so that
+	// services can be reached by their hostname.
+	dockerNetwork = "bass"
+
+	// dockerResultRepo is the repository that thunk results are committed to,
+	// tagged by their cache key.
+	dockerResultRepo = "bass-result"
+
+	// dockerScratch is an empty image to run thunks without an image from.
+	dockerScratch = "bass-scratch:latest"
+
+	// dockerResultLabel is set on the volumes holding each result's working
+	// directory and output, with the result's cache key as the value.
+	dockerResultLabel = "bass.result"
+
+	// dockerCacheLabel is set on cache volumes, with the cache ID as the value.
+	dockerCacheLabel = "bass.cache"
+
+	// dockerBassDir is where each result's volume is mounted, containing the
+	// shim, the working directory, and the command's output.
+	dockerBassDir = "/bass"
+)
+
+// DockerConfig configures the Docker runtime.
+type DockerConfig struct {
+	// Address of the Docker daemon. Defaults to $DOCKER_HOST, falling back to
+	// the local socket.
+	Host string `json:"host,omitempty"`
+
+	// Directory for the content store used to export images. Defaults to
+	// $XDG_DATA_HOME/bass/docker.
+	DataDir string `json:"data_dir,omitempty"`
+
+	Debug        bool `json:"debug,omitempty"`
+	DisableCache bool `json:"disable_cache,omitempty"`
+}
+
+// Docker is a runtime which runs thunks in containers using the Docker Engine
+// API.
+//
+// Thunk results are committed to images in the local Docker image store and
+// their working directories are kept in volumes, both named by a key derived
+// from the thunk and all of its inputs.
+type Docker struct {
+	Config   DockerConfig
+	Platform ocispecs.Platform
+
+	client *client.Client
+	auth   *registryAuthProvider
+	store  content.Store
+
+	pulled  map[string]dockerState
+	pulledL sync.Mutex
+
+	building  map[string]*sync.Mutex
+	buildingL sync.Mutex
+
+	runs runTracker
+}
+
+var _ bass.Runtime = &Docker{}
+
+// dockerState is the result of building a thunk or image.
+type dockerState struct {
+	// A key derived from the thunk and all of its inputs, used to cache
+	// results of thunks which build on this one.
+	Key string
+
+	// The image to run containers from.
+	Image string
+
+	// The volume containing the working directory, i.e. the thunk's output,
+	// and the command's stdout, stderr, and exit code. Empty if nothing has
+	// run yet.
+	Volume string
+
+	Platform    ocispecs.Platform
+	Config      ocispecs.ImageConfig
+	Healthcheck *image.HealthConfig
+	Annotations map[string]string
+}
+
+// withThunkImageConfig applies the config that only affects the published
+// image.
+func (st dockerState) withThunkImageConfig(cfg bass.ThunkImageConfig) (dockerState, error) {
+	ib, err := IntermediateBuild{
+		Config:      st.Config,
+		Healthcheck: st.Healthcheck,
+		Annotations: st.Annotations,
+	}.withThunkImageConfig(cfg)
+	if err != nil {
+		return st, err
+	}
+
+	st.Config = ib.Config
+	st.Healthcheck = ib.Healthcheck
+	st.Annotations = ib.Annotations
+
+	return st, nil
+}
+
+// dockerExec configures how the Docker runtime runs a thunk's command.
+type dockerExec struct {
+	// Stream the command's stdout to the writer, or copy it from the cached
+	// result.
+	Stdout io.Writer
+
+	// Record the exit code and stderr instead of failing.
+	Result bool
+
+	// Run a service; never use or save a cached result.
+	Service bool
+}
+
+func NewDocker(ctx context.Context, _ bass.RuntimePool, cfg *bass.Scope) (bass.Runtime, error) {
+	var config DockerConfig
+	if cfg != nil {
+		if err := cfg.Decode(&config); err != nil {
+			return nil, fmt.Errorf("docker runtime config: %w", err)
+		}
+	}
+
+	if config.DataDir == "" {
+		config.DataDir = filepath.Join(xdg.DataHome, "bass", "docker")
+	}
+
+	opts := []client.Opt{
+		client.FromEnv,
+		client.WithAPIVersionNegotiation(),
+	}
+
+	if config.Host != "" {
+		opts = append(opts, client.WithHost(config.Host))
+	}
+
+	dockerClient, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("docker client: %w", err)
+	}
+
+	info, err := dockerClient.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connect to docker: %w", err)
+	}
+
+	store, err := local.NewStore(filepath.Join(config.DataDir, "content"))
+	if err != nil {
+		return nil, fmt.Errorf("create content store: %w", err)
+	}
+
+	runtime := &Docker{
+		Config: config,
+		Platform: platforms.Normalize(ocispecs.Platform{
+			OS:           info.OSType,
+			Architecture: info.Architecture,
+		}),
+
+		client: dockerClient,
+		auth:   newRegistryAuthProvider(os.Stderr),
+		store:  store,
+
+		pulled:   map[string]dockerState{},
+		building: map[string]*sync.Mutex{},
+	}
+
+	if err := runtime.setup(ctx); err != nil {
+		return nil, err
+	}
+
+	return runtime, nil
+}
+
+// setup creates the network and scratch image if they don't exist.
+func (runtime *Docker) setup(ctx context.Context) error {
+	_, err := runtime.client.NetworkInspect(ctx, dockerNetwork, types.NetworkInspectOptions{})
+	if errdefs.IsNotFound(err) {
+		_, err = runtime.client.NetworkCreate(ctx, dockerNetwork, types.NetworkCreate{
+			CheckDuplicate: true,
+		})
+		if errdefs.IsConflict(err) {
+			// created concurrently
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("create network: %w", err)
+	}
+
+	_, _, err = runtime.client.ImageInspectWithRaw(ctx, dockerScratch)
+	if errdefs.IsNotFound(err) {
+		var empty []byte
+		empty, err = tarball(func(tw *tar.Writer) error { return nil })
+		if err != nil {
+			return err
+		}
+
+		var rc io.ReadCloser
+		rc, err = runtime.client.ImageImport(ctx, types.ImageImportSource{
+			Source:     bytes.NewReader(empty),
+			SourceName: "-",
+		}, dockerScratch, types.ImageImportOptions{
+			Platform: platforms.Format(runtime.Platform),
+		})
+		if err == nil {
+			err = dockerMessages(rc, io.Discard, nil)
+			rc.Close()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("create scratch image: %w", err)
+	}
+
+	return nil
+}
+
+func (runtime *Docker) Resolve(ctx context.Context, imageRef bass.ImageRef) (bass.Thunk, error) {
+	// track dependent services
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	ctx, rec := progrock.WithGroup(ctx, "resolve "+imageRef.Thunk().String())
+	defer rec.Complete()
+
+	defer runtime.auth.Use(ctx)()
+
+	addr, err := ref(ctx, runtime, imageRef)
+	if err != nil {
+		return bass.Thunk{}, fmt.Errorf("resolve ref %v: %w", imageRef, err)
+	}
+
+	registryAuth, err := runtime.registryAuth(ctx, addr)
+	if err != nil {
+		return bass.Thunk{}, err
+	}
+
+	dist, err := runtime.client.DistributionInspect(ctx, addr, registryAuth)
+	if err != nil {
+		return bass.Thunk{}, fmt.Errorf("resolve image: %w", err)
+	}
+
+	imageRef.Digest = dist.Descriptor.Digest.String()
+
+	return imageRef.Thunk(), nil
+}
+
+func (runtime *Docker) Run(ctx context.Context, thunk bass.Thunk) error {
+	ctx, rec := progrock.WithGroup(ctx, "run "+thunk.String())
+	defer rec.Complete()
+
+	ctx, done := thunk.TimeoutContext(ctx)
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	_, err := runtime.build(ctx, thunk, true, dockerExec{})
+	return done(err)
+}
+
+func (runtime *Docker) Start(ctx context.Context, thunk bass.Thunk) (StartResult, error) {
+	ctx, rec := progrock.WithGroup(ctx, "start "+thunk.String())
+	defer rec.Complete()
+
+	host, err := thunk.Hash()
+	if err != nil {
+		return StartResult{}, err
+	}
+
+	ctx, stop := context.WithCancel(ctx)
+
+	runs := bass.RunsFromContext(ctx)
+
+	exited := make(chan error, 1)
+	runs.Go(stop, func() error {
+		_, err := runtime.build(ctx, thunk, true, dockerExec{Service: true})
+		exited <- err
+		return err
+	})
+
+	checked := make(chan error, 1)
+	go func() {
+		checked <- runtime.check(ctx, host, thunk.Ports)
+	}()
+
+	select {
+	case err := <-checked:
+		if err != nil {
+			return StartResult{}, fmt.Errorf("check error: %w", err)
+		}
+
+		result := StartResult{
+			Ports: PortInfos{},
+		}
+
+		// services are reachable by their hostname on the bass network
+		for _, port := range thunk.Ports {
+			result.Ports[port.Name] = bass.Bindings{
+				"host": bass.String(host),
+				"port": bass.Int(port.Port),
+			}.Scope()
+		}
+
+		return result, nil
+	case err := <-exited:
+		stop() // interrupt healthcheck
+
+		if err != nil {
+			return StartResult{}, err
+		}
+
+		return StartResult{}, fmt.Errorf("service exited before healthcheck")
+	}
+}
+
+// check runs the shim in a container on the bass network to wait for each
+// port to accept connections.
+func (runtime *Docker) check(ctx context.Context, host string, ports []bass.ThunkPort) error {
+	args := []string{shimExePath, "check", host}
+	for _, port := range ports {
+		args = append(args, fmt.Sprintf("%s:%d", port.Name, port.Port))
+	}
+
+	resp, err := runtime.client.ContainerCreate(ctx, &container.Config{
+		Image:      dockerScratch,
+		Entrypoint: args,
+	}, &container.HostConfig{
+		NetworkMode: dockerNetwork,
+	}, nil, nil, "")
+	if err != nil {
+		return err
+	}
+
+	defer runtime.removeContainer(resp.ID)
+
+	if err := runtime.copyShim(ctx, resp.ID); err != nil {
+		return err
+	}
+
+	stderr := ioctx.StderrFromContext(ctx)
+
+	code, err := runtime.runContainer(ctx, resp.ID, stderr, stderr)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("exit status %d", code)
+	}
+
+	return nil
+}
+
+func (runtime *Docker) Read(ctx context.Context, w io.Writer, thunk bass.Thunk) error {
+	ctx, rec := progrock.WithGroup(ctx, "read "+thunk.String())
+	defer rec.Complete()
+
+	ctx, done := thunk.TimeoutContext(ctx)
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	_, err := runtime.build(ctx, thunk, true, dockerExec{Stdout: w})
+	return done(err)
+}
+
+func (runtime *Docker) RunResult(ctx context.Context, thunk bass.Thunk) (bass.RunResult, error) {
+	ctx, rec := progrock.WithGroup(ctx, "run "+thunk.String())
+	defer rec.Complete()
+
+	ctx, done := thunk.TimeoutContext(ctx)
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	st, err := runtime.build(ctx, thunk, true, dockerExec{Result: true})
+	if err != nil {
+		return bass.RunResult{}, done(err)
+	}
+
+	var result bass.RunResult
+
+	result.Stdout, err = runtime.readFile(ctx, st.Volume, outputFile)
+	if err != nil {
+		return result, done(err)
+	}
+
+	result.Stderr, err = runtime.readFile(ctx, st.Volume, stderrFile)
+	if err != nil {
+		return result, done(err)
+	}
+
+	exitCode, err := runtime.readFile(ctx, st.Volume, exitCodeFile)
+	if err != nil {
+		return result, done(err)
+	}
+
+	result.ExitCode, err = strconv.Atoi(string(exitCode))
+	if err != nil {
+		return result, done(fmt.Errorf("malformed exit code: %w", err))
+	}
+
+	if result.ExitCode != 0 {
+		// don't cache failures; without its volume the result is run again
+		err := runtime.client.VolumeRemove(ctx, st.Volume, true)
+		if err != nil && !errdefs.IsNotFound(err) {
+			return result, done(err)
+		}
+	}
+
+	return result, done(nil)
+}
+
+func (runtime *Docker) Export(ctx context.Context, w io.Writer, thunk bass.Thunk, opts bass.ExportOpts) error {
+	ctx, rec := progrock.WithGroup(ctx, "export "+thunk.String())
+	defer rec.Complete()
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	switch opts.Format {
+	case bass.ExportOCI, bass.ExportDocker, bass.ExportRootfs, "":
+	default:
+		return UnsupportedError{
+			Runtime: DockerName,
+			Feature: fmt.Sprintf("export format %s", opts.Format),
+		}
+	}
+
+	st, err := runtime.build(ctx, thunk, false, dockerExec{})
+	if err != nil {
+		return err
+	}
+
+	if opts.Format == bass.ExportRootfs {
+		return runtime.exportRootfs(ctx, w, st)
+	}
+
+	desc, err := runtime.writeImage(ctx, st)
+	if err != nil {
+		return err
+	}
+
+	return exportImage(ctx, runtime.store, w, desc, opts)
+}
+
+func (runtime *Docker) Publish(ctx context.Context, ref bass.ImageRef, thunk bass.Thunk) (bass.ImageRef, error) {
+	ctx, rec := progrock.WithGroup(ctx, "publish "+thunk.String())
+	defer rec.Complete()
+
+	defer runtime.auth.Use(ctx)()
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	addr, err := ref.Ref()
+	if err != nil {
+		return ref, err
+	}
+
+	st, err := runtime.build(ctx, thunk, false, dockerExec{})
+	if err != nil {
+		return ref, err
+	}
+
+	if len(st.Annotations) > 0 {
+		return ref, UnsupportedError{
+			Runtime: DockerName,
+			Feature: "publishing image annotations",
+		}
+	}
+
+	desc, err := runtime.writeImage(ctx, st)
+	if err != nil {
+		return ref, err
+	}
+
+	dig, err := runtime.push(ctx, addr, desc)
+	if err != nil {
+		return ref, err
+	}
+
+	ref.Digest = dig
+
+	return ref, nil
+}
+
+func (runtime *Docker) PublishIndex(ctx context.Context, ref bass.ImageRef, thunks []bass.Thunk) (bass.ImageRef, error) {
+	return ref, UnsupportedError{
+		Runtime: DockerName,
+		Feature: "publishing multi-platform images",
+	}
+}
+
+func (runtime *Docker) ExportPath(ctx context.Context, w io.Writer, tp bass.ThunkPath) error {
+	ctx, rec := progrock.WithGroup(ctx, "export path "+tp.String())
+	defer rec.Complete()
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	st, err := runtime.build(ctx, tp.Thunk, true, dockerExec{})
+	if err != nil {
+		return err
+	}
+
+	fsp := tp.Path.FilesystemPath()
+
+	var name string
+	if !fsp.IsDir() {
+		name = fsp.FromSlash()
+	}
+
+	return runtime.writeWorkPath(ctx, w, st, tp, name)
+}
+
+func (runtime *Docker) Prune(ctx context.Context, opts bass.PruneOpts) error {
+	stderr := ioctx.StderrFromContext(ctx)
+	tw := tabwriter.NewWriter(stderr, 2, 8, 2, ' ', 0)
+
+	type record struct {
+		id       string
+		desc     string
+		image    string
+		volume   string
+		size     int64
+		lastUsed time.Time
+	}
+
+	var records []record
+
+	caches, err := runtime.Caches(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.IDs) > 0 {
+		for _, cache := range caches {
+			for _, id := range opts.IDs {
+				if cache.ID == id {
+					records = append(records, record{
+						id:       cache.ID,
+						desc:     cache.Description,
+						volume:   cache.ID,
+						size:     cache.Size,
+						lastUsed: cache.LastUsedAt,
+					})
+				}
+			}
+		}
+	} else {
+		results, err := runtime.client.ImageList(ctx, types.ImageListOptions{
+			Filters:    filters.NewArgs(filters.Arg("reference", dockerResultRepo)),
+			SharedSize: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		volumes, err := runtime.volumes(ctx, dockerResultLabel)
+		if err != nil {
+			return err
+		}
+
+		for _, img := range results {
+			for _, tag := range img.RepoTags {
+				key := tag[len(dockerResultRepo)+1:]
+
+				r := record{
+					id:       key,
+					desc:     "result",
+					image:    tag,
+					size:     img.Size,
+					lastUsed: time.Unix(img.Created, 0),
+				}
+
+				if img.SharedSize > 0 {
+					r.size -= img.SharedSize
+				}
+
+				if vol, found := volumes[dockerResultVolume(key)]; found {
+					r.volume = vol.Name
+					r.size += volumeSize(vol)
+				}
+
+				records = append(records, r)
+			}
+		}
+
+		if opts.All {
+			for _, cache := range caches {
+				records = append(records, record{
+					id:       cache.ID,
+					desc:     cache.Description,
+					volume:   cache.ID,
+					size:     cache.Size,
+					lastUsed: cache.LastUsedAt,
+				})
+			}
+		}
+	}
+
+	// prune the least recently used first
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].lastUsed.Before(records[j].lastUsed)
+	})
+
+	var remaining int64
+	for _, r := range records {
+		remaining += r.size
+	}
+
+	total := int64(0)
+	for _, r := range records {
+		if opts.KeepDuration > 0 && time.Since(r.lastUsed) < opts.KeepDuration {
+			continue
+		}
+
+		if opts.KeepBytes > 0 && remaining <= opts.KeepBytes {
+			break
+		}
+
+		if r.image != "" {
+			_, err := runtime.client.ImageRemove(ctx, r.image, types.ImageRemoveOptions{
+				PruneChildren: true,
+			})
+			if err != nil && !errdefs.IsNotFound(err) {
+				return fmt.Errorf("prune %s: %w", r.id, err)
+			}
+		}
+
+		if r.volume != "" {
+			err := runtime.client.VolumeRemove(ctx, r.volume, false)
+			if err != nil && !errdefs.IsNotFound(err) {
+				return fmt.Errorf("prune %s: %w", r.id, err)
+			}
+		}
+
+		fmt.Fprintf(tw, "pruned %s\tlast used: %s ago\tsize: %.2f\t%s\n",
+			r.id,
+			time.Since(r.lastUsed).Truncate(time.Second),
+			units.Bytes(r.size),
+			r.desc)
+
+		remaining -= r.size
+		total += r.size
+	}
+
+	fmt.Fprintf(tw, "total: %.2f\n", units.Bytes(total))
+
+	return tw.Flush()
+}
+
+func (runtime *Docker) Caches(ctx context.Context) ([]bass.CacheInfo, error) {
+	volumes, err := runtime.volumes(ctx, dockerCacheLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	var caches []bass.CacheInfo
+	for _, vol := range volumes {
+		info := bass.CacheInfo{
+			ID:          vol.Name,
+			Description: vol.Labels[dockerCacheLabel],
+			Size:        volumeSize(vol),
+		}
+
+		if vol.UsageData != nil {
+			info.UsageCount = int(vol.UsageData.RefCount)
+		}
+
+		// Docker doesn't record when a volume was last used, so go by when it
+		// was created instead
+		if created, err := time.Parse(time.RFC3339, vol.CreatedAt); err == nil {
+			info.LastUsedAt = created
+		}
+
+		caches = append(caches, info)
+	}
+
+	sort.Slice(caches, func(i, j int) bool {
+		return caches[i].ID < caches[j].ID
+	})
+
+	return caches, nil
+}
+
+// volumes returns the volumes with the given label, including their disk
+// usage.
+func (runtime *Docker) volumes(ctx context.Context, label string) (map[string]*volume.Volume, error) {
+	usage, err := runtime.client.DiskUsage(ctx, types.DiskUsageOptions{
+		Types: []types.DiskUsageObject{types.VolumeObject},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := map[string]*volume.Volume{}
+	for _, vol := range usage.Volumes {
+		if _, found := vol.Labels[label]; found {
+			volumes[vol.Name] = vol
+		}
+	}
+
+	return volumes, nil
+}
+
+func volumeSize(vol *volume.Volume) int64 {
+	if vol.UsageData == nil || vol.UsageData.Size < 0 {
+		return 0
+	}
+
+	return vol.UsageData.Size
+}
+
+func (runtime *Docker) Close() error {
+	runtime.runs.Close()
+	return runtime.client.Close()
+}
+
+func (runtime *Docker) image(ctx context.Context, image *bass.ThunkImage) (dockerState, error) {
+	switch {
+	case image == nil:
+		return dockerState{
+			Image:    dockerScratch,
+			Platform: runtime.Platform,
+		}, nil
+
+	case image.Ref != nil:
+		addr, err := ref(ctx, runtime, *image.Ref)
+		if err != nil {
+			return dockerState{}, err
+		}
+
+		return runtime.pull(ctx, addr)
+
+	case image.Thunk != nil:
+		return runtime.build(ctx, *image.Thunk, false, dockerExec{})
+
+	case image.Archive != nil:
+		return runtime.importArchive(ctx, image.Archive)
+
+	case image.DockerBuild != nil:
+		return runtime.dockerBuild(ctx, image.DockerBuild)
+
+	default:
+		return dockerState{}, fmt.Errorf("unsupported image type: %s", image.ToValue())
+	}
+}
+
+// dockerMount is a mount source prepared for running a command.
+type dockerMount struct {
+	CommandMount
+
+	// The container path to mount to.
+	Target string
+
+	// Part of the cache key for the command.
+	Key string
+
+	// The built thunk, for thunk path mounts.
+	thunk dockerState
+}
+
+func (runtime *Docker) build(ctx context.Context, thunk bass.Thunk, forceExec bool, opts dockerExec) (dockerState, error) {
+	if thunk.TLS != nil {
+		return dockerState{}, UnsupportedError{
+			Runtime: DockerName,
+			Feature: "TLS",
+		}
+	}
+
+	base, err := runtime.image(ctx, thunk.Image)
+	if err != nil {
+		return dockerState{}, err
+	}
+
+	thunkName, err := thunk.Hash()
+	if err != nil {
+		return dockerState{}, err
+	}
+
+	cmd, err := NewCommand(ctx, runtime, thunk)
+	if err != nil {
+		return dockerState{}, err
+	}
+
+	st := base
+
+	st.Config, err = thunkImageConfig(st.Config, thunk)
+	if err != nil {
+		return dockerState{}, err
+	}
+
+	if thunk.ImageConfig != nil {
+		st, err = st.withThunkImageConfig(*thunk.ImageConfig)
+		if err != nil {
+			return dockerState{}, err
+		}
+	}
+
+	useEntrypoint := thunk.UseEntrypoint
+	if len(cmd.Args) == 0 {
+		if forceExec {
+			cmd.Args = st.Config.Cmd
+			useEntrypoint = true
+		} else {
+			// no command; just overriding config
+			st.Key = resultKey("config", base.Key, thunkName)
+			return st, nil
+		}
+	}
+
+	if useEntrypoint {
+		cmd.Args = append(append([]string{}, st.Config.Entrypoint...), cmd.Args...)
+	}
+
+	if len(cmd.Args) == 0 {
+		return dockerState{}, fmt.Errorf("no command specified")
+	}
+
+	mounts := make([]dockerMount, len(cmd.Mounts))
+	mountKeys := []string{}
+	for i, mount := range cmd.Mounts {
+		mounts[i], err = runtime.prepareMount(ctx, mount)
+		if err != nil {
+			return dockerState{}, err
+		}
+
+		mountKeys = append(mountKeys, mounts[i].Target+"="+mounts[i].Key)
+	}
+
+	mode := "run"
+	if opts.Result {
+		mode = "result"
+	}
+
+	st.Key = resultKey(append([]string{mode, base.Key, thunkName}, mountKeys...)...)
+
+	name := st.Key
+	if opts.Service {
+		name = "service-" + identity.NewID()
+	}
+
+	st.Volume = dockerResultVolume(name)
+
+	var stream *outputStream
+	if opts.Stdout != nil {
+		stream = &outputStream{w: opts.Stdout}
+	}
+
+	runCtx, done := runtime.runs.Track(ctx)
+
+	unlock := runtime.lock(st.Key)
+
+	var cached bool
+	if !runtime.Config.DisableCache && !opts.Service {
+		st.Image, cached = runtime.cached(runCtx, st)
+	}
+
+	if !cached {
+		st.Image, err = runtime.exec(runCtx, thunk, thunkName, cmd, base, st, mounts, opts, stream)
+	}
+
+	unlock()
+
+	if opts.Service {
+		// services are never cached
+		_ = runtime.client.VolumeRemove(context.Background(), st.Volume, true)
+	}
+
+	done()
+
+	if err != nil {
+		return dockerState{}, err
+	}
+
+	if stream != nil {
+		out, err := runtime.openFile(ctx, st.Volume, outputFile)
+		if err != nil {
+			return dockerState{}, err
+		}
+
+		defer out.Close()
+
+		if err := stream.Finish(out); err != nil {
+			return dockerState{}, err
+		}
+	}
+
+	return st, nil
+}
+
+// dockerResultVolume returns the name of the volume for a result.
+func dockerResultVolume(name string) string {
+	return dockerResultRepo + "-" + name
+}
+
+// lock prevents the same thunk from running concurrently, returning a func
+// to call once it's done.
+func (runtime *Docker) lock(key string) func() {
+	l := runtime.mutex(key)
+	l.Lock()
+	return l.Unlock
+}
+
+// mutex returns the mutex for the given key.
+func (runtime *Docker) mutex(key string) *sync.Mutex {
+	runtime.buildingL.Lock()
+	defer runtime.buildingL.Unlock()
+
+	l, found := runtime.building[key]
+	if !found {
+		l = &sync.Mutex{}
+		runtime.building[key] = l
+	}
+
+	return l
+}
+
+// cached returns the committed image for the result, if both it and its
+// volume exist.
+func (runtime *Docker) cached(ctx context.Context, st dockerState) (string, bool) {
+	inspect, _, err := runtime.client.ImageInspectWithRaw(ctx, dockerResultRepo+":"+st.Key)
+	if err != nil {
+		return "", false
+	}
+
+	if _, err := runtime.client.VolumeInspect(ctx, st.Volume); err != nil {
+		return "", false
+	}
+
+	return inspect.ID, true
+}
+
+func (runtime *Docker) prepareMount(ctx context.Context, mount CommandMount) (dockerMount, error) {
+	dm := dockerMount{
+		CommandMount: mount,
+	}
+
+	if path.IsAbs(mount.Target) {
+		dm.Target = mount.Target
+	} else {
+		dm.Target = path.Join(workDir, mount.Target)
+	}
+
+	source := mount.Source
+	switch {
+	case source.ThunkPath != nil:
+		dep, err := runtime.build(ctx, source.ThunkPath.Thunk, true, dockerExec{})
+		if err != nil {
+			return dm, fmt.Errorf("thunk path %s: %w", source.ThunkPath, err)
+		}
+
+		dm.thunk = dep
+		dm.Key = resultKey("thunk", dep.Key, source.ThunkPath.Path.Slash())
+
+	case source.HostPath != nil:
+		key, err := hostPathKey(ctx, *source.HostPath)
+		if err != nil {
+			return dm, err
+		}
+
+		dm.Key = key
+
+	case source.FSPath != nil:
+		hash, err := source.FSPath.Hash()
+		if err != nil {
+			return dm, err
+		}
+
+		dm.Key = resultKey("fs", hash, source.FSPath.Path.Slash())
+
+	case source.Cache != nil:
+		if path.Clean(source.Cache.Path.Slash()) != "." {
+			return dm, UnsupportedError{
+				Runtime: DockerName,
+				Feature: "mounting cache subpaths",
+			}
+		}
+
+		dm.Key = resultKey("cache", source.Cache.ID)
+
+	case source.Secret != nil:
+		dm.Key = resultKey("secret", source.Secret.Name)
+
+	default:
+		return dm, fmt.Errorf("unrecognized mount source: %s", source.ToValue())
+	}
+
+	return dm, nil
+}
+
+// exec runs the command in a container, committing its root filesystem to an
+// image and leaving its working directory and output in the result's volume.
+//
+// Returns the ID of the committed image.
+func (runtime *Docker) exec(
+	ctx context.Context,
+	thunk bass.Thunk,
+	thunkName string,
+	cmd Command,
+	base, st dockerState,
+	mounts []dockerMount,
+	opts dockerExec,
+	stream *outputStream,
+) (string, error) {
+	// clean up after any previous attempt that was interrupted
+	err := runtime.client.VolumeRemove(ctx, st.Volume, true)
+	if err != nil && !errdefs.IsNotFound(err) {
+		return "", fmt.Errorf("remove stale volume: %w", err)
+	}
+
+	_, err = runtime.client.VolumeCreate(ctx, volume.CreateOptions{
+		Name: st.Volume,
+		Labels: map[string]string{
+			dockerResultLabel: st.Key,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("create volume: %w", err)
+	}
+
+	hostname := "thunk"
+	if len(thunk.Ports) > 0 {
+		hostname = thunkName
+	}
+
+	// the bass settings are always set so that any committed to a base image
+	// are overridden
+	env := append([]string{}, st.Config.Env...)
+	if !hasEnv(env, "PATH") {
+		env = append(env, "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin")
+	}
+
+	env = append(env, "_BASS_OUTPUT="+outputFile)
+
+	if opts.Result {
+		env = append(env,
+			"_BASS_STDERR="+stderrFile,
+			"_BASS_EXIT_CODE="+exitCodeFile)
+	} else {
+		env = append(env, "_BASS_STDERR=", "_BASS_EXIT_CODE=")
+	}
+
+	if runtime.Config.Debug {
+		env = append(env, "_BASS_DEBUG=1")
+	} else {
+		env = append(env, "_BASS_DEBUG=")
+	}
+
+	// secrets are passed to the shim rather than the container so that they
+	// aren't committed to the image; the shim removes the file once read
+	cmd.Env = append([]string{}, cmd.Env...)
+	for _, secret := range cmd.SecretEnv {
+		cmd.Env = append(cmd.Env, secret.Name+"="+string(secret.Secret.Reveal()))
+	}
+
+	hostConfig := &container.HostConfig{
+		NetworkMode: dockerNetwork,
+		Privileged:  thunk.Insecure,
+		Mounts: []mount.Mount{
+			{
+				Type:          mount.TypeVolume,
+				Source:        st.Volume,
+				Target:        dockerBassDir,
+				VolumeOptions: &mount.VolumeOptions{NoCopy: true},
+			},
+		},
+	}
+
+	switch thunk.Network {
+	case bass.NetworkModeNone:
+		hostConfig.NetworkMode = "none"
+	case bass.NetworkModeHost:
+		hostConfig.NetworkMode = "host"
+	}
+
+	// limits are enforced by Docker rather than the shim
+	if res := cmd.Resources; res != nil {
+		hostConfig.NanoCPUs = int64(res.CPU * 1e9)
+		hostConfig.Memory = res.Memory
+		if res.Pids > 0 {
+			pids := res.Pids
+			hostConfig.PidsLimit = &pids
+		}
+
+		cmd.Resources = nil
+	}
+
+	var netConfig *network.NetworkingConfig
+	if len(thunk.Ports) > 0 && hostConfig.NetworkMode == dockerNetwork {
+		netConfig = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				dockerNetwork: {
+					Aliases: []string{thunkName},
+				},
+			},
+		}
+	}
+
+	cmdPayload, err := bass.MarshalJSON(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	var inputs []dockerInput
+	var remountedWorkdir bool
+	for _, mount := range mounts {
+		m, input, release, err := runtime.mountSource(ctx, mount)
+		if err != nil {
+			return "", fmt.Errorf("mount %s: %w", mount.Target, err)
+		}
+
+		defer release()
+
+		if input != nil {
+			if mount.Target == workDir {
+				// the mount becomes the output
+				remountedWorkdir = true
+			}
+
+			inputs = append(inputs, *input)
+		}
+
+		if m != nil && mount.Target != workDir {
+			hostConfig.Mounts = append(hostConfig.Mounts, *m)
+		}
+	}
+
+	if !remountedWorkdir && base.Volume != "" {
+		inputs = append([]dockerInput{{
+			Target: workDir,
+			Dir:    true,
+			Write: func(ctx context.Context, w io.Writer) error {
+				return runtime.writeVolumePath(ctx, w, base.Volume, workDir, nil, "")
+			},
+		}}, inputs...)
+	}
+
+	resp, err := runtime.client.ContainerCreate(ctx, &container.Config{
+		Image:      base.Image,
+		Hostname:   hostname,
+		User:       "0:0",
+		Env:        env,
+		Entrypoint: []string{shimExePath, "run", inputFile},
+		WorkingDir: workDir,
+		Healthcheck: &container.HealthConfig{
+			// don't run the image's healthcheck
+			Test: []string{"NONE"},
+		},
+	}, hostConfig, netConfig, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("create container: %w", err)
+	}
+
+	defer runtime.removeContainer(resp.ID)
+
+	shimExe, found := allShims["exe."+runtime.Platform.Architecture]
+	if !found {
+		return "", fmt.Errorf("no shim found for %s", runtime.Platform.Architecture)
+	}
+
+	// populate the result volume with the shim, the command, and an empty
+	// working directory
+	bassDir, err := tarball(func(tw *tar.Writer) error {
+		if err := writeTarDir(tw, path.Base(ioDir), 0700); err != nil {
+			return err
+		}
+
+		if err := writeTarFile(tw, path.Join(path.Base(ioDir), path.Base(inputFile)), 0600, cmdPayload); err != nil {
+			return err
+		}
+
+		if err := writeTarFile(tw, path.Base(shimExePath), 0755, shimExe); err != nil {
+			return err
+		}
+
+		return writeTarDir(tw, path.Base(workDir), 0755)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = cli.Step(ctx, "[hide] prepare "+thunk.String(), func(ctx context.Context, _ *progrock.VertexRecorder) error {
+		err := runtime.client.CopyToContainer(ctx, resp.ID, dockerBassDir, bytes.NewReader(bassDir), types.CopyToContainerOptions{})
+		if err != nil {
+			return fmt.Errorf("copy shim: %w", err)
+		}
+
+		for _, input := range inputs {
+			if err := runtime.copyIn(ctx, resp.ID, input); err != nil {
+				return fmt.Errorf("copy %s: %w", input.Target, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = cli.Step(ctx, thunk.Cmdline(), func(ctx context.Context, vtx *progrock.VertexRecorder) error {
+		var stdout io.Writer = vtx.Stdout()
+		if stream != nil {
+			stdout = io.MultiWriter(stdout, stream)
+		}
+
+		code, err := runtime.runContainer(ctx, resp.ID, stdout, vtx.Stderr())
+		if err != nil {
+			return err
+		}
+
+		if code != 0 {
+			return fmt.Errorf("exit status %d", code)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Service {
+		return "", nil
+	}
+
+	commit, err := runtime.client.ContainerCommit(ctx, resp.ID, types.ContainerCommitOptions{
+		Reference: dockerResultRepo + ":" + st.Key,
+	})
+	if err != nil {
+		return "", fmt.Errorf("commit: %w", err)
+	}
+
+	return commit.ID, nil
+}
+
+// runContainer starts the container and waits for it to exit, returning its
+// exit code. The container is killed if the context is canceled.
+func (runtime *Docker) runContainer(ctx context.Context, id string, stdout, stderr io.Writer) (int, error) {
+	attach, err := runtime.client.ContainerAttach(ctx, id, types.ContainerAttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("attach: %w", err)
+	}
+
+	defer attach.Close()
+
+	copied := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(stdout, stderr, attach.Reader)
+		copied <- err
+	}()
+
+	waitCh, errCh := runtime.client.ContainerWait(ctx, id, container.WaitConditionNextExit)
+
+	if err := runtime.client.ContainerStart(ctx, id, types.ContainerStartOptions{}); err != nil {
+		return 0, fmt.Errorf("start: %w", err)
+	}
+
+	select {
+	case res := <-waitCh:
+		if res.Error != nil {
+			return 0, errors.New(res.Error.Message)
+		}
+
+		// wait for the remaining output
+		if err := <-copied; err != nil {
+			return 0, fmt.Errorf("copy output: %w", err)
+		}
+
+		return int(res.StatusCode), nil
+
+	case err := <-errCh:
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		return 0, fmt.Errorf("wait: %w", err)
+	}
+}
+
+// removeContainer forcibly removes a container and its anonymous volumes.
+//
+// It runs with a background context since it's part of cleaning up after
+// an interruption.
+func (runtime *Docker) removeContainer(id string) {
+	_ = runtime.client.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{
+		Force:         true,
+		RemoveVolumes: true,
+	})
+}
+
+// copyShim copies the shim into a container which isn't using a result
+// volume.
+func (runtime *Docker) copyShim(ctx context.Context, id string) error {
+	shimExe, found := allShims["exe."+runtime.Platform.Architecture]
+	if !found {
+		return fmt.Errorf("no shim found for %s", runtime.Platform.Architecture)
+	}
+
+	return runtime.copyIn(ctx, id, dockerInput{
+		Target: shimExePath,
+		Write: func(_ context.Context, w io.Writer) error {
+			tw := tar.NewWriter(w)
+			if err := writeTarFile(tw, path.Base(shimExePath), 0755, shimExe); err != nil {
+				return err
+			}
+
+			return tw.Close()
+		},
+	})
+}
+
+// mountSource prepares a mount for a command, returning the mount to add to
+// the container and the content to copy into it, along with a func to call
+// once the command has finished.
+//
+// Copies are made so that commands can't modify their inputs. Files are
+// copied into place since volumes can only be mounted as directories.
+func (runtime *Docker) mountSource(ctx context.Context, dm dockerMount) (*mount.Mount, *dockerInput, func(), error) {
+	noop := func() {}
+
+	// an anonymous volume, removed along with the container
+	anonymous := &mount.Mount{
+		Type:          mount.TypeVolume,
+		Target:        dm.Target,
+		VolumeOptions: &mount.VolumeOptions{NoCopy: true},
+	}
+
+	source := dm.Source
+	switch {
+	case source.ThunkPath != nil:
+		fsp := source.ThunkPath.Path.FilesystemPath()
+
+		input := &dockerInput{
+			Target: dm.Target,
+			Dir:    fsp.IsDir(),
+			Write: func(ctx context.Context, w io.Writer) error {
+				return runtime.writeWorkPath(ctx, w, dm.thunk, *source.ThunkPath, path.Base(dm.Target))
+			},
+		}
+
+		if !input.Dir {
+			return nil, input, noop, nil
+		}
+
+		return anonymous, input, noop, nil
+
+	case source.HostPath != nil:
+		input := &dockerInput{
+			Target: dm.Target,
+			Dir:    source.HostPath.Path.FilesystemPath().IsDir(),
+			Write: func(ctx context.Context, w io.Writer) error {
+				return writeHostPath(ctx, w, *source.HostPath, path.Base(dm.Target))
+			},
+		}
+
+		if !input.Dir {
+			return nil, input, noop, nil
+		}
+
+		return anonymous, input, noop, nil
+
+	case source.FSPath != nil:
+		input := &dockerInput{
+			Target: dm.Target,
+			Dir:    source.FSPath.Path.File == nil,
+			Write: func(ctx context.Context, w io.Writer) error {
+				return writeFSPath(w, *source.FSPath, path.Base(dm.Target))
+			},
+		}
+
+		if !input.Dir {
+			return nil, input, noop, nil
+		}
+
+		return anonymous, input, noop, nil
+
+	case source.Cache != nil:
+		return runtime.cacheMount(ctx, dm.Target, *source.Cache)
+
+	case source.Secret != nil:
+		secret := source.Secret.Reveal()
+		if secret == nil {
+			return nil, nil, noop, fmt.Errorf("missing secret: %s", source.Secret.Name)
+		}
+
+		// secrets are bind mounted from the host so that they're never
+		// committed to an image, which requires a daemon that shares the
+		// host's filesystem
+		dir, err := os.MkdirTemp("", "bass-secret-")
+		if err != nil {
+			return nil, nil, noop, err
+		}
+
+		release := func() { _ = os.RemoveAll(dir) }
+
+		secretPath := filepath.Join(dir, "secret")
+		if err := os.WriteFile(secretPath, secret, 0400); err != nil {
+			release()
+			return nil, nil, noop, err
+		}
+
+		return &mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   secretPath,
+			Target:   dm.Target,
+			ReadOnly: true,
+		}, nil, release, nil
+
+	default:
+		return nil, nil, noop, fmt.Errorf("unrecognized mount source: %s", source.ToValue())
+	}
+}
+
+// cacheMount returns the volume to mount for a cache, taking a lock
+// according to the cache's concurrency mode.
+//
+// Locks are only held within this process.
+func (runtime *Docker) cacheMount(ctx context.Context, target string, cache bass.CachePath) (*mount.Mount, *dockerInput, func(), error) {
+	noop := func() {}
+
+	name := "bass-cache-" + digest.FromString(cache.ID).Encoded()
+
+	// creating a volume that already exists is a no-op
+	_, err := runtime.client.VolumeCreate(ctx, volume.CreateOptions{
+		Name: name,
+		Labels: map[string]string{
+			dockerCacheLabel: cache.ID,
+		},
+	})
+	if err != nil {
+		return nil, nil, noop, fmt.Errorf("create cache volume: %w", err)
+	}
+
+	m := &mount.Mount{
+		Type:          mount.TypeVolume,
+		Source:        name,
+		Target:        target,
+		VolumeOptions: &mount.VolumeOptions{NoCopy: true},
+	}
+
+	release := noop
+
+	switch cache.ConcurrencyMode {
+	case bass.ConcurrencyModeLocked:
+		release = runtime.lock("cache:" + name)
+
+	case bass.ConcurrencyModePrivate:
+		l := runtime.mutex("cache:" + name)
+		if l.TryLock() {
+			release = l.Unlock
+		} else {
+			// already in use; give this command its own empty cache
+			m.Source = ""
+		}
+	}
+
+	return m, nil, release, nil
+}
@@ -0,0 +1,464 @@
+package runtimes
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/containerd/containerd/archive"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
+	"github.com/tonistiigi/fsutil"
+	fstypes "github.com/tonistiigi/fsutil/types"
+
+	"github.com/vito/bass/pkg/bass"
+)
+
+// dockerInput is content to copy into a container before it starts.
+type dockerInput struct {
+	// The container path to copy to.
+	Target string
+
+	// Whether the content is a directory. Directories are written as a
+	// tarball of their contents; files are written as a tarball containing a
+	// single entry named after the target.
+	Dir bool
+
+	// Writes the tarball.
+	Write func(context.Context, io.Writer) error
+}
+
+// copyIn copies the input into the container, creating any missing parent
+// directories.
+func (runtime *Docker) copyIn(ctx context.Context, id string, input dockerInput) error {
+	dest := input.Target
+	if !input.Dir {
+		dest = path.Dir(dest)
+	}
+
+	// find the closest parent that exists; CopyToContainer won't create them
+	root := dest
+	for root != "/" {
+		_, err := runtime.client.ContainerStatPath(ctx, id, root)
+		if err == nil {
+			break
+		}
+
+		if !errdefs.IsNotFound(err) {
+			return fmt.Errorf("stat %s: %w", root, err)
+		}
+
+		root = path.Dir(root)
+	}
+
+	prefix := strings.TrimPrefix(strings.TrimPrefix(dest, root), "/")
+
+	r, w := io.Pipe()
+	go func() {
+		if prefix == "" {
+			w.CloseWithError(input.Write(ctx, w))
+		} else {
+			w.CloseWithError(prefixTar(ctx, w, prefix, input.Write))
+		}
+	}()
+
+	defer r.Close()
+
+	return runtime.client.CopyToContainer(ctx, id, root, r, types.CopyToContainerOptions{})
+}
+
+// prefixTar rewrites a tarball so that all of its entries are within the
+// given directory, including entries for the directory and its parents.
+func prefixTar(ctx context.Context, w io.Writer, prefix string, write func(context.Context, io.Writer) error) error {
+	tw := tar.NewWriter(w)
+
+	parts := strings.Split(prefix, "/")
+	for i := range parts {
+		if err := writeTarDir(tw, path.Join(parts[:i+1]...), 0755); err != nil {
+			return err
+		}
+	}
+
+	r, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(write(ctx, pw))
+	}()
+
+	defer r.Close()
+
+	err := rewriteTar(tw, r, func(name string) (string, bool) {
+		return path.Join(prefix, name), true
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// rewriteTar copies the entries of a tarball to the writer, renaming them
+// and skipping any for which rename returns false.
+func rewriteTar(tw *tar.Writer, r io.Reader, rename func(string) (string, bool)) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		name, ok := rename(hdr.Name)
+		if !ok {
+			continue
+		}
+
+		if hdr.Typeflag == tar.TypeDir && !strings.HasSuffix(name, "/") {
+			name += "/"
+		}
+
+		hdr.Name = name
+
+		if hdr.Typeflag == tar.TypeLink {
+			if linkname, ok := rename(hdr.Linkname); ok {
+				hdr.Linkname = linkname
+			}
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// helper creates a container which is never started, for copying files to
+// and from the image and mounts.
+func (runtime *Docker) helper(ctx context.Context, image string, mounts []mount.Mount) (string, func(), error) {
+	resp, err := runtime.client.ContainerCreate(ctx, &container.Config{
+		Image:      image,
+		Entrypoint: []string{shimExePath},
+	}, &container.HostConfig{
+		NetworkMode: "none",
+		Mounts:      mounts,
+	}, nil, nil, "")
+	if err != nil {
+		return "", nil, fmt.Errorf("create helper: %w", err)
+	}
+
+	return resp.ID, func() { runtime.removeContainer(resp.ID) }, nil
+}
+
+// volumeHelper creates a helper container with the volume mounted read-only.
+func (runtime *Docker) volumeHelper(ctx context.Context, vol string) (string, func(), error) {
+	if vol == "" {
+		return "", nil, fmt.Errorf("no output: thunk did not run a command")
+	}
+
+	return runtime.helper(ctx, dockerScratch, []mount.Mount{
+		{
+			Type:     mount.TypeVolume,
+			Source:   vol,
+			Target:   dockerBassDir,
+			ReadOnly: true,
+		},
+	})
+}
+
+// openFile opens a file in a result's volume.
+func (runtime *Docker) openFile(ctx context.Context, vol string, p string) (io.ReadCloser, error) {
+	id, cleanup, err := runtime.volumeHelper(ctx, vol)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, _, err := runtime.client.CopyFromContainer(ctx, id, p)
+	if err != nil {
+		cleanup()
+		return nil, fmt.Errorf("open %s: %w", p, err)
+	}
+
+	tr := tar.NewReader(rc)
+	if _, err := tr.Next(); err != nil {
+		rc.Close()
+		cleanup()
+		return nil, fmt.Errorf("open %s: %w", p, err)
+	}
+
+	return helperFile{
+		Reader: tr,
+		close: func() error {
+			defer cleanup()
+			return rc.Close()
+		},
+	}, nil
+}
+
+type helperFile struct {
+	io.Reader
+	close func() error
+}
+
+func (file helperFile) Close() error {
+	return file.close()
+}
+
+// readFile reads a file in a result's volume.
+func (runtime *Docker) readFile(ctx context.Context, vol string, p string) ([]byte, error) {
+	rc, err := runtime.openFile(ctx, vol, p)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// writeWorkPath writes a tarball of a path in a result's working directory.
+func (runtime *Docker) writeWorkPath(ctx context.Context, w io.Writer, st dockerState, tp bass.ThunkPath, name string) error {
+	return runtime.writeVolumePath(ctx, w, st.Volume, path.Join(workDir, tp.Path.FilesystemPath().Slash()), &fsutil.WalkOpt{
+		IncludePatterns: tp.Includes(),
+		ExcludePatterns: tp.Excludes(),
+	}, name)
+}
+
+// writeVolumePath writes a tarball of a path in a result's volume.
+//
+// Directories are written with their contents at the root, filtered by the
+// given options. Files are written as a single entry with the given name.
+func (runtime *Docker) writeVolumePath(ctx context.Context, w io.Writer, vol string, p string, filter *fsutil.WalkOpt, name string) error {
+	id, cleanup, err := runtime.volumeHelper(ctx, vol)
+	if err != nil {
+		return err
+	}
+
+	defer cleanup()
+
+	stat, err := runtime.client.ContainerStatPath(ctx, id, p)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", p, err)
+	}
+
+	if !stat.Mode.IsDir() {
+		rc, _, err := runtime.client.CopyFromContainer(ctx, id, p)
+		if err != nil {
+			return err
+		}
+
+		defer rc.Close()
+
+		tw := tar.NewWriter(w)
+
+		err = rewriteTar(tw, rc, func(string) (string, bool) {
+			return name, true
+		})
+		if err != nil {
+			return err
+		}
+
+		return tw.Close()
+	}
+
+	rc, _, err := runtime.client.CopyFromContainer(ctx, id, p+"/.")
+	if err != nil {
+		return err
+	}
+
+	defer rc.Close()
+
+	if filter != nil && (len(filter.IncludePatterns) > 0 || len(filter.ExcludePatterns) > 0) {
+		// go through a temporary directory to filter the same way as other
+		// runtimes
+		tmp, err := os.MkdirTemp("", "bass-filter-")
+		if err != nil {
+			return err
+		}
+
+		defer os.RemoveAll(tmp)
+
+		if _, err := archive.Apply(ctx, tmp, rc, archive.WithNoSameOwner()); err != nil {
+			return fmt.Errorf("extract %s: %w", p, err)
+		}
+
+		return fsutil.WriteTar(ctx, fsutil.NewFS(tmp, &fsutil.WalkOpt{
+			IncludePatterns: filter.IncludePatterns,
+			ExcludePatterns: filter.ExcludePatterns,
+			Map: func(_ string, st *fstypes.Stat) fsutil.MapResult {
+				st.Uid = 0
+				st.Gid = 0
+				return fsutil.MapResultKeep
+			},
+		}), w)
+	}
+
+	tw := tar.NewWriter(w)
+
+	err = rewriteTar(tw, rc, func(name string) (string, bool) {
+		name = strings.TrimPrefix(name, "./")
+		if name == "" || name == "." || name == "./" {
+			return "", false
+		}
+
+		return name, true
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// writeHostPath writes a tarball of a host path.
+//
+// Directories are written with their contents at the root. Files are written
+// as a single entry with the given name.
+func writeHostPath(ctx context.Context, w io.Writer, hp bass.HostPath, name string) error {
+	src, walkOpt, err := hostPathSource(ctx, hp)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return fsutil.WriteTar(ctx, fsutil.NewFS(src, walkOpt), w)
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	tw := tar.NewWriter(w)
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+
+	hdr.Name = name
+	hdr.Uid, hdr.Gid = 0, 0
+	hdr.Uname, hdr.Gname = "", ""
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write tar header: %w", err)
+	}
+
+	if _, err := io.Copy(tw, f); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// writeFSPath writes a tarball of a path in an embedded filesystem.
+//
+// Directories are written with their contents at the root. Files are written
+// as a single entry with the given name.
+func writeFSPath(w io.Writer, fsp bass.FSPath, name string) error {
+	root := path.Clean(fsp.Path.Slash())
+
+	tw := tar.NewWriter(w)
+
+	if fsp.Path.File != nil {
+		content, err := fs.ReadFile(fsp.FS, root)
+		if err != nil {
+			return err
+		}
+
+		if err := writeTarFile(tw, name, 0644, content); err != nil {
+			return err
+		}
+
+		return tw.Close()
+	}
+
+	err := fs.WalkDir(fsp.FS, root, func(walkPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if walkPath == root {
+			return nil
+		}
+
+		rel := strings.TrimPrefix(walkPath, root+"/")
+		if root == "." {
+			rel = walkPath
+		}
+
+		if d.IsDir() {
+			return writeTarDir(tw, rel, 0755)
+		}
+
+		content, err := fs.ReadFile(fsp.FS, walkPath)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", walkPath, err)
+		}
+
+		return writeTarFile(tw, rel, 0644, content)
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// tarball returns a tarball of the entries written by the func.
+func tarball(write func(*tar.Writer) error) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	tw := tar.NewWriter(buf)
+	if err := write(tw); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeTarDir(tw *tar.Writer, name string, mode fs.FileMode) error {
+	return tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     name + "/",
+		Mode:     int64(mode),
+	})
+}
+
+func writeTarFile(tw *tar.Writer, name string, mode fs.FileMode, content []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(mode),
+		Size:     int64(len(content)),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tw.Write(content)
+	return err
+}
@@ -0,0 +1,560 @@
+package runtimes
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/containerd/containerd/content"
+	imagearchive "github.com/containerd/containerd/images/archive"
+	transferarchive "github.com/containerd/containerd/pkg/transfer/archive"
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/moby/buildkit/exporter/containerimage/image"
+	"github.com/moby/buildkit/session/auth"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/vito/progrock"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/cli"
+)
+
+// dockerOCICache caches OCI archives loaded by the Docker runtime.
+var dockerOCICache = newProtoCache[dockerState]()
+
+// inspect returns the state for an image in the Docker image store, keyed by
+// its ID.
+func (runtime *Docker) inspect(ctx context.Context, ref string) (dockerState, error) {
+	inspect, _, err := runtime.client.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return dockerState{}, fmt.Errorf("inspect %s: %w", ref, err)
+	}
+
+	st := dockerState{
+		Key:      inspect.ID,
+		Image:    inspect.ID,
+		Platform: runtime.Platform,
+	}
+
+	if inspect.Os != "" && inspect.Architecture != "" {
+		st.Platform = platforms.Normalize(ocispecs.Platform{
+			OS:           inspect.Os,
+			Architecture: inspect.Architecture,
+			Variant:      inspect.Variant,
+		})
+	}
+
+	if cfg := inspect.Config; cfg != nil {
+		st.Config = ocispecs.ImageConfig{
+			User:       cfg.User,
+			Env:        cfg.Env,
+			Entrypoint: cfg.Entrypoint,
+			Cmd:        cfg.Cmd,
+			WorkingDir: cfg.WorkingDir,
+			Labels:     cfg.Labels,
+			StopSignal: cfg.StopSignal,
+			Volumes:    cfg.Volumes,
+		}
+
+		if len(cfg.ExposedPorts) > 0 {
+			st.Config.ExposedPorts = map[string]struct{}{}
+			for port := range cfg.ExposedPorts {
+				st.Config.ExposedPorts[string(port)] = struct{}{}
+			}
+		}
+
+		if hc := cfg.Healthcheck; hc != nil {
+			st.Healthcheck = &image.HealthConfig{
+				Test:        hc.Test,
+				Interval:    hc.Interval,
+				Timeout:     hc.Timeout,
+				StartPeriod: hc.StartPeriod,
+				Retries:     hc.Retries,
+			}
+		}
+	}
+
+	return st, nil
+}
+
+// pull pulls an image into the Docker image store for the runtime's
+// platform.
+func (runtime *Docker) pull(ctx context.Context, ref string) (dockerState, error) {
+	runtime.pulledL.Lock()
+	st, found := runtime.pulled[ref]
+	runtime.pulledL.Unlock()
+	if found {
+		return st, nil
+	}
+
+	defer runtime.auth.Use(ctx)()
+
+	err := cli.Step(ctx, "pull "+ref, func(ctx context.Context, vtx *progrock.VertexRecorder) error {
+		// images pinned to a digest never change, so skip pulling them again
+		if strings.Contains(ref, "@") {
+			existing, err := runtime.inspect(ctx, ref)
+			if err == nil {
+				st = existing
+				return nil
+			}
+		}
+
+		registryAuth, err := runtime.registryAuth(ctx, ref)
+		if err != nil {
+			return err
+		}
+
+		rc, err := runtime.client.ImagePull(ctx, ref, types.ImagePullOptions{
+			RegistryAuth: registryAuth,
+			Platform:     platforms.Format(runtime.Platform),
+		})
+		if err != nil {
+			return err
+		}
+
+		defer rc.Close()
+
+		if err := dockerMessages(rc, vtx.Stdout(), nil); err != nil {
+			return err
+		}
+
+		st, err = runtime.inspect(ctx, ref)
+		return err
+	})
+	if err != nil {
+		return dockerState{}, err
+	}
+
+	runtime.pulledL.Lock()
+	runtime.pulled[ref] = st
+	runtime.pulledL.Unlock()
+
+	return st, nil
+}
+
+// importArchive loads the image for the runtime's platform from an OCI image
+// archive into the Docker image store.
+//
+// The archive is converted to the Docker format through the content store,
+// since Docker can't load OCI archives itself.
+func (runtime *Docker) importArchive(ctx context.Context, imageArchive *bass.ImageArchive) (dockerState, error) {
+	cached, found := dockerOCICache.Get(ctx, imageArchive)
+	if found {
+		return cached, nil
+	}
+
+	rc, err := imageArchive.File.ToReadable().Open(ctx)
+	if err != nil {
+		return dockerState{}, fmt.Errorf("image archive file: %w", err)
+	}
+
+	defer rc.Close()
+
+	var st dockerState
+	err = cli.Step(ctx, fmt.Sprintf("import %s", imageArchive.File.ToValue()), func(ctx context.Context, vtx *progrock.VertexRecorder) error {
+		desc, err := transferarchive.NewImageImportStream(rc, "").Import(ctx, runtime.store)
+		if err != nil {
+			return err
+		}
+
+		manifestDesc, err := resolveIndex(ctx, runtime.store, desc, runtime.Platform, imageArchive.Tag)
+		if err != nil {
+			return fmt.Errorf("resolve index: %w", err)
+		}
+
+		platform := runtime.Platform
+		manifestDesc.Platform = &platform
+
+		name := "bass-archive:" + manifestDesc.Digest.Encoded()
+
+		if err := runtime.load(ctx, vtx.Stdout(), *manifestDesc, name); err != nil {
+			return err
+		}
+
+		st, err = runtime.inspect(ctx, name)
+		return err
+	})
+	if err != nil {
+		return dockerState{}, fmt.Errorf("image archive import: %w", err)
+	}
+
+	dockerOCICache.Put(ctx, imageArchive, st)
+
+	return st, nil
+}
+
+// dockerBuild builds an image from a Dockerfile with the daemon's builder.
+func (runtime *Docker) dockerBuild(ctx context.Context, build *bass.ImageDockerBuild) (dockerState, error) {
+	var writeContext func(context.Context, io.Writer) error
+	switch {
+	case build.Context.Thunk != nil:
+		tp := *build.Context.Thunk
+
+		dep, err := runtime.build(ctx, tp.Thunk, true, dockerExec{})
+		if err != nil {
+			return dockerState{}, fmt.Errorf("docker build context %s: %w", tp, err)
+		}
+
+		writeContext = func(ctx context.Context, w io.Writer) error {
+			return runtime.writeWorkPath(ctx, w, dep, tp, "")
+		}
+
+	case build.Context.Host != nil:
+		writeContext = func(ctx context.Context, w io.Writer) error {
+			return writeHostPath(ctx, w, *build.Context.Host, "")
+		}
+
+	case build.Context.FS != nil:
+		writeContext = func(ctx context.Context, w io.Writer) error {
+			return writeFSPath(w, *build.Context.FS, "")
+		}
+
+	default:
+		return dockerState{}, fmt.Errorf("unsupported docker build context")
+	}
+
+	dockerfile := "Dockerfile"
+	if build.Dockerfile != nil {
+		dockerfile = path.Clean(build.Dockerfile.Slash())
+	}
+
+	buildArgs := map[string]*string{}
+	if build.Args != nil {
+		err := build.Args.Each(func(k bass.Symbol, v bass.Value) error {
+			var val string
+			if err := v.Decode(&val); err != nil {
+				return err
+			}
+
+			buildArgs[k.String()] = &val
+			return nil
+		})
+		if err != nil {
+			return dockerState{}, fmt.Errorf("docker build args: %w", err)
+		}
+	}
+
+	var st dockerState
+	err := cli.Step(ctx, "docker build "+build.Context.ToValue().String(), func(ctx context.Context, vtx *progrock.VertexRecorder) error {
+		r, w := io.Pipe()
+		go func() {
+			w.CloseWithError(writeContext(ctx, w))
+		}()
+
+		defer r.Close()
+
+		resp, err := runtime.client.ImageBuild(ctx, r, types.ImageBuildOptions{
+			Dockerfile:  dockerfile,
+			Target:      build.Target,
+			BuildArgs:   buildArgs,
+			Platform:    build.Platform.String(),
+			Remove:      true,
+			ForceRemove: true,
+			Version:     types.BuilderV1,
+		})
+		if err != nil {
+			return err
+		}
+
+		defer resp.Body.Close()
+
+		var id string
+		err = dockerMessages(resp.Body, vtx.Stdout(), func(aux json.RawMessage) error {
+			var result types.BuildResult
+			if err := json.Unmarshal(aux, &result); err != nil {
+				return err
+			}
+
+			id = result.ID
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if id == "" {
+			return fmt.Errorf("docker build did not return an image ID")
+		}
+
+		st, err = runtime.inspect(ctx, id)
+		return err
+	})
+	if err != nil {
+		return dockerState{}, err
+	}
+
+	return st, nil
+}
+
+// writeImage writes the image for the state to the content store with its
+// final config, returning the manifest descriptor.
+//
+// Committed images carry the config of the container that ran the command,
+// so it is replaced with the config tracked for the thunk.
+func (runtime *Docker) writeImage(ctx context.Context, st dockerState) (ocispecs.Descriptor, error) {
+	var manifestDesc ocispecs.Descriptor
+	err := cli.Step(ctx, "[hide] save "+st.Image, func(ctx context.Context, _ *progrock.VertexRecorder) error {
+		rc, err := runtime.client.ImageSave(ctx, []string{st.Image})
+		if err != nil {
+			return err
+		}
+
+		defer rc.Close()
+
+		idx, err := transferarchive.NewImageImportStream(rc, "").Import(ctx, runtime.store)
+		if err != nil {
+			return fmt.Errorf("import: %w", err)
+		}
+
+		desc, err := resolveIndex(ctx, runtime.store, idx, st.Platform, "")
+		if err != nil {
+			return fmt.Errorf("resolve index: %w", err)
+		}
+
+		manifestBlob, err := content.ReadBlob(ctx, runtime.store, *desc)
+		if err != nil {
+			return fmt.Errorf("read manifest: %w", err)
+		}
+
+		var manifest ocispecs.Manifest
+		if err := json.Unmarshal(manifestBlob, &manifest); err != nil {
+			return fmt.Errorf("unmarshal manifest: %w", err)
+		}
+
+		configBlob, err := content.ReadBlob(ctx, runtime.store, manifest.Config)
+		if err != nil {
+			return fmt.Errorf("read config: %w", err)
+		}
+
+		var img image.Image
+		if err := json.Unmarshal(configBlob, &img); err != nil {
+			return fmt.Errorf("unmarshal config: %w", err)
+		}
+
+		img.Config.ImageConfig = st.Config
+		img.Config.Healthcheck = st.Healthcheck
+
+		manifest.Config, err = writeJSON(ctx, runtime.store, manifest.Config.MediaType, img)
+		if err != nil {
+			return fmt.Errorf("write config: %w", err)
+		}
+
+		manifest.Annotations = st.Annotations
+
+		manifestDesc, err = writeJSON(ctx, runtime.store, desc.MediaType, manifest)
+		if err != nil {
+			return fmt.Errorf("write manifest: %w", err)
+		}
+
+		platform := st.Platform
+		manifestDesc.Platform = &platform
+
+		return nil
+	})
+	if err != nil {
+		return ocispecs.Descriptor{}, err
+	}
+
+	return manifestDesc, nil
+}
+
+// load loads an image in the content store into the Docker image store
+// under the given name.
+func (runtime *Docker) load(ctx context.Context, w io.Writer, desc ocispecs.Descriptor, name string) error {
+	r, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(imagearchive.Export(ctx, runtime.store, pw,
+			imagearchive.WithPlatform(platforms.Only(*desc.Platform)),
+			imagearchive.WithManifest(desc, name)))
+	}()
+
+	defer r.Close()
+
+	resp, err := runtime.client.ImageLoad(ctx, r, true)
+	if err != nil {
+		return fmt.Errorf("load: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if !resp.JSON {
+		_, err := io.Copy(w, resp.Body)
+		return err
+	}
+
+	return dockerMessages(resp.Body, w, nil)
+}
+
+// exportRootfs writes a tarball of the state's root filesystem.
+func (runtime *Docker) exportRootfs(ctx context.Context, w io.Writer, st dockerState) error {
+	return cli.Step(ctx, "[hide] export rootfs "+st.Image, func(ctx context.Context, _ *progrock.VertexRecorder) error {
+		id, cleanup, err := runtime.helper(ctx, st.Image, nil)
+		if err != nil {
+			return err
+		}
+
+		defer cleanup()
+
+		rc, err := runtime.client.ContainerExport(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		defer rc.Close()
+
+		tw := tar.NewWriter(w)
+
+		// skip the files created by Docker for the container and the
+		// mountpoint for the result volume
+		err = rewriteTar(tw, rc, func(name string) (string, bool) {
+			switch strings.TrimSuffix(strings.TrimPrefix(name, "./"), "/") {
+			case ".dockerenv", path.Base(dockerBassDir):
+				return "", false
+			default:
+				return name, true
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		return tw.Close()
+	})
+}
+
+// push loads an image in the content store into the Docker image store under
+// the given name and pushes it, returning the digest of the pushed manifest.
+func (runtime *Docker) push(ctx context.Context, addr string, desc ocispecs.Descriptor) (string, error) {
+	var dig string
+	err := cli.Step(ctx, "push "+addr, func(ctx context.Context, vtx *progrock.VertexRecorder) error {
+		if err := runtime.load(ctx, vtx.Stdout(), desc, addr); err != nil {
+			return err
+		}
+
+		registryAuth, err := runtime.registryAuth(ctx, addr)
+		if err != nil {
+			return err
+		}
+
+		rc, err := runtime.client.ImagePush(ctx, addr, types.ImagePushOptions{
+			RegistryAuth: registryAuth,
+		})
+		if err != nil {
+			return err
+		}
+
+		defer rc.Close()
+
+		return dockerMessages(rc, vtx.Stdout(), func(aux json.RawMessage) error {
+			var result types.PushResult
+			if err := json.Unmarshal(aux, &result); err != nil {
+				return err
+			}
+
+			dig = result.Digest
+			return nil
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if dig == "" {
+		return "", fmt.Errorf("push %s: no digest returned", addr)
+	}
+
+	return dig, nil
+}
+
+// registryAuth returns the encoded credentials for the registry of the
+// given image reference, if any.
+func (runtime *Docker) registryAuth(ctx context.Context, addr string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(addr)
+	if err != nil {
+		return "", fmt.Errorf("normalize ref: %w", err)
+	}
+
+	domain := reference.Domain(named)
+
+	creds, err := runtime.auth.Credentials(ctx, &auth.CredentialsRequest{
+		Host: registryHost(domain),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if creds.Username == "" && creds.Secret == "" {
+		return "", nil
+	}
+
+	config := types.AuthConfig{
+		ServerAddress: domain,
+	}
+
+	if creds.Username == "" {
+		config.IdentityToken = creds.Secret
+	} else {
+		config.Username = creds.Username
+		config.Password = creds.Secret
+	}
+
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(payload), nil
+}
+
+// dockerMessages copies the progress messages streamed by the daemon to the
+// writer, returning the first error and passing any auxiliary messages to
+// the callback.
+func dockerMessages(r io.Reader, w io.Writer, aux func(json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		var msg struct {
+			Stream   string           `json:"stream"`
+			Status   string           `json:"status"`
+			Progress string           `json:"progress"`
+			ID       string           `json:"id"`
+			Error    string           `json:"error"`
+			Aux      *json.RawMessage `json:"aux"`
+		}
+
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		if msg.Error != "" {
+			return errors.New(msg.Error)
+		}
+
+		if msg.Aux != nil && aux != nil {
+			if err := aux(*msg.Aux); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case msg.Stream != "":
+			fmt.Fprint(w, msg.Stream)
+		case msg.Progress != "":
+			// skip progress bars
+		case msg.Status != "" && msg.ID != "":
+			fmt.Fprintf(w, "%s: %s\n", msg.ID, msg.Status)
+		case msg.Status != "":
+			fmt.Fprintln(w, msg.Status)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package runtimes_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/runtimes"
+)
+
+func TestDockerRuntime(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+		return
+	}
+
+	if os.Getenv("SKIP_DOCKER_TESTS") != "" {
+		t.Skipf("$SKIP_DOCKER_TESTS set; skipping!")
+		return
+	}
+
+	t.Parallel()
+
+	runtimes.Suite(testCtx, t, bass.RuntimeConfig{
+		Platform: bass.LinuxPlatform,
+		Runtime:  runtimes.DockerName,
+		Config: bass.Bindings{
+			"data_dir": bass.String(t.TempDir()),
+			"debug":    bass.Bool(true),
+		}.Scope(),
+	}, runtimes.SkipSuites("tls.bass"))
+}
@@ -1,6 +1,7 @@
 package runtimes
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"sort"
@@ -10,11 +11,19 @@ import (
 	"github.com/vito/bass/pkg/bass"
 )
 
+// ErrRegistryAuthForwarding is returned when publishing through a remote
+// runtime with credentials set by bass.WithRegistryAuth, since secrets are
+// never sent to remote runtimes.
+var ErrRegistryAuthForwarding = errors.New("registry auth cannot be forwarded to a remote runtime")
+
 // NoRuntimeError is returned when a platform has no runtime associated to it.
 type NoRuntimeError struct {
 	Platform bass.Platform
 
 	AllRuntimes []Assoc
+
+	// Runtimes which were configured but could not be initialized.
+	Unavailable []UnavailableRuntime
 }
 
 func (err NoRuntimeError) Error() string {
@@ -31,9 +40,54 @@ func (err NoRuntimeError) NiceError(w io.Writer, outer error) error {
 			fmt.Fprintf(w, "* %s", assoc.Platform)
 		}
 	}
+
+	if len(err.Unavailable) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "unavailable runtimes: %d\n", len(err.Unavailable))
+		for _, unavailable := range err.Unavailable {
+			fmt.Fprintf(w, "* %s (%s): %s\n", unavailable.Platform, unavailable.Runtime, unavailable.Err)
+		}
+
+		for _, unavailable := range err.Unavailable {
+			var notFound BuildkitNotFoundError
+			if errors.As(unavailable.Err, &notFound) {
+				fmt.Fprintln(w)
+				fmt.Fprintln(w, aec.YellowF.Apply("buildkit could not be found; to use Docker instead, configure the docker runtime in ~/.config/bass/config.json:"))
+				fmt.Fprintln(w)
+				fmt.Fprintln(w, `  {"runtimes": [{"platform": {"os": "linux"}, "runtime": "docker"}]}`)
+				break
+			}
+		}
+	}
+
 	return nil
 }
 
+// UnavailableRuntime is a configured runtime which could not be initialized.
+type UnavailableRuntime struct {
+	Platform bass.Platform
+	Runtime  string
+	Err      error
+}
+
+// BuildkitNotFoundError is returned when no Buildkit address is configured
+// and the default installation could not be started because Docker or the
+// Buildkit image is unavailable.
+//
+// Failing to reach an explicitly configured address is a regular error.
+type BuildkitNotFoundError struct {
+	Err error
+}
+
+func (err BuildkitNotFoundError) Error() string {
+	return err.Err.Error()
+}
+
+func (err BuildkitNotFoundError) Unwrap() error {
+	return err.Err
+}
+
 // UnknownRuntimeError is returned when an unknown runtime is configured.
 type UnknownRuntimeError struct {
 	Name string
@@ -53,3 +107,14 @@ func (err UnknownRuntimeError) Error() string {
 		strings.Join(available, ", "),
 	)
 }
+
+// UnsupportedError is returned when a runtime cannot honor part of a thunk's
+// configuration.
+type UnsupportedError struct {
+	Runtime string
+	Feature string
+}
+
+func (err UnsupportedError) Error() string {
+	return fmt.Sprintf("%s runtime does not support %s", err.Runtime, err.Feature)
+}
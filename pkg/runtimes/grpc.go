@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/ioctx"
 	"github.com/vito/bass/pkg/proto"
 	"github.com/vito/progrock"
 	"google.golang.org/grpc"
@@ -70,6 +71,11 @@ func (client *Client) Resolve(ctx context.Context, ref bass.ImageRef) (bass.Thun
 }
 
 func (client *Client) Run(ctx context.Context, thunk bass.Thunk) error {
+	ctx, done := thunk.TimeoutContext(ctx)
+	return done(client.run(ctx, thunk))
+}
+
+func (client *Client) run(ctx context.Context, thunk bass.Thunk) error {
 	p, err := thunk.MarshalProto()
 	if err != nil {
 		return err
@@ -105,6 +111,11 @@ func (client *Client) Run(ctx context.Context, thunk bass.Thunk) error {
 }
 
 func (client *Client) Read(ctx context.Context, w io.Writer, thunk bass.Thunk) error {
+	ctx, done := thunk.TimeoutContext(ctx)
+	return done(client.read(ctx, w, thunk))
+}
+
+func (client *Client) read(ctx context.Context, w io.Writer, thunk bass.Thunk) error {
 	p, err := thunk.MarshalProto()
 	if err != nil {
 		return err
@@ -145,13 +156,71 @@ func (client *Client) Read(ctx context.Context, w io.Writer, thunk bass.Thunk) e
 	return nil
 }
 
-func (client *Client) Export(ctx context.Context, w io.Writer, thunk bass.Thunk) error {
+func (client *Client) RunResult(ctx context.Context, thunk bass.Thunk) (bass.RunResult, error) {
+	ctx, done := thunk.TimeoutContext(ctx)
+	result, err := client.runResult(ctx, thunk)
+	return result, done(err)
+}
+
+func (client *Client) runResult(ctx context.Context, thunk bass.Thunk) (bass.RunResult, error) {
+	ret := bass.RunResult{}
+
+	p, err := thunk.MarshalProto()
+	if err != nil {
+		return ret, err
+	}
+
+	r, err := client.RuntimeClient.RunResult(ctx, p.(*proto.Thunk))
+	if err != nil {
+		return ret, err
+	}
+
+	recorder := progrock.RecorderFromContext(ctx)
+
+	for {
+		pov, err := r.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return ret, err
+		}
+
+		switch x := pov.GetInner().(type) {
+		case *proto.RunResultResponse_Progress:
+			recorder.Record(x.Progress)
+
+		case *proto.RunResultResponse_Result:
+			ret.ExitCode = int(x.Result.GetExitCode())
+			ret.Stdout = x.Result.GetStdout()
+			ret.Stderr = x.Result.GetStderr()
+
+		default:
+			return ret, fmt.Errorf("unhandled stream message: %T", x)
+		}
+	}
+
+	return ret, nil
+}
+
+func (client *Client) Export(ctx context.Context, w io.Writer, thunk bass.Thunk, opts bass.ExportOpts) error {
 	p, err := thunk.MarshalProto()
 	if err != nil {
 		return err
 	}
 
-	stream, err := client.RuntimeClient.Export(ctx, p.(*proto.Thunk))
+	var stream exportResponseClient
+	if (opts.Format == "" || opts.Format == bass.ExportOCI) && len(opts.Tags) == 0 {
+		// use the original RPC so that older servers are supported
+		stream, err = client.RuntimeClient.Export(ctx, p.(*proto.Thunk))
+	} else {
+		stream, err = client.RuntimeClient.ExportImage(ctx, &proto.ExportRequest{
+			Thunk:  p.(*proto.Thunk),
+			Format: string(opts.Format),
+			Tags:   opts.Tags,
+		})
+	}
 	if err != nil {
 		return err
 	}
@@ -189,6 +258,10 @@ func (client *Client) Export(ctx context.Context, w io.Writer, thunk bass.Thunk)
 func (client *Client) Publish(ctx context.Context, ref bass.ImageRef, thunk bass.Thunk) (bass.ImageRef, error) {
 	ret := bass.ImageRef{}
 
+	if len(bass.RegistryAuthsFromContext(ctx)) > 0 {
+		return ret, ErrRegistryAuthForwarding
+	}
+
 	t, err := ref.MarshalProto()
 	if err != nil {
 		return ret, err
@@ -237,6 +310,66 @@ func (client *Client) Publish(ctx context.Context, ref bass.ImageRef, thunk bass
 	return ret, nil
 }
 
+func (client *Client) PublishIndex(ctx context.Context, ref bass.ImageRef, thunks []bass.Thunk) (bass.ImageRef, error) {
+	ret := bass.ImageRef{}
+
+	if len(bass.RegistryAuthsFromContext(ctx)) > 0 {
+		return ret, ErrRegistryAuthForwarding
+	}
+
+	r, err := ref.MarshalProto()
+	if err != nil {
+		return ret, err
+	}
+
+	req := &proto.PublishIndexRequest{
+		Ref: r.(*proto.ImageRef),
+	}
+
+	for _, thunk := range thunks {
+		t, err := thunk.MarshalProto()
+		if err != nil {
+			return ret, err
+		}
+
+		req.Thunks = append(req.Thunks, t.(*proto.Thunk))
+	}
+
+	stream, err := client.RuntimeClient.PublishIndex(ctx, req)
+	if err != nil {
+		return ref, err
+	}
+
+	recorder := progrock.RecorderFromContext(ctx)
+
+	for {
+		pov, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return ret, err
+		}
+
+		switch x := pov.GetInner().(type) {
+		case *proto.PublishResponse_Progress:
+			recorder.Record(x.Progress)
+
+		case *proto.PublishResponse_Published:
+			err := ret.UnmarshalProto(x.Published)
+			if err != nil {
+				return ret, err
+			}
+
+		default:
+			return ret, fmt.Errorf("unhandled stream message: %T", x)
+		}
+	}
+
+	return ret, nil
+}
+
 func (client *Client) ExportPath(ctx context.Context, w io.Writer, tp bass.ThunkPath) error {
 	p, err := tp.MarshalProto()
 	if err != nil {
@@ -278,8 +411,69 @@ func (client *Client) ExportPath(ctx context.Context, w io.Writer, tp bass.Thunk
 	return nil
 }
 
-func (client *Client) Prune(context.Context, bass.PruneOpts) error {
-	return fmt.Errorf("Prune unimplemented")
+func (client *Client) Prune(ctx context.Context, opts bass.PruneOpts) error {
+	stream, err := client.RuntimeClient.Prune(ctx, &proto.PruneRequest{
+		All:            opts.All,
+		KeepDurationNs: int64(opts.KeepDuration),
+		KeepBytes:      opts.KeepBytes,
+		Ids:            opts.IDs,
+	})
+	if err != nil {
+		return err
+	}
+
+	recorder := progrock.RecorderFromContext(ctx)
+	stderr := ioctx.StderrFromContext(ctx)
+
+	for {
+		pod, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return err
+		}
+
+		switch x := pod.GetInner().(type) {
+		case *proto.PruneResponse_Progress:
+			recorder.Record(x.Progress)
+
+		case *proto.PruneResponse_Output:
+			_, err = stderr.Write(x.Output)
+			if err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("unhandled stream message: %T", x)
+		}
+	}
+
+	return nil
+}
+
+func (client *Client) Caches(ctx context.Context) ([]bass.CacheInfo, error) {
+	res, err := client.RuntimeClient.Caches(ctx, &proto.CachesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	caches := make([]bass.CacheInfo, len(res.GetCaches()))
+	for i, c := range res.GetCaches() {
+		caches[i] = bass.CacheInfo{
+			ID:          c.GetId(),
+			Description: c.GetDescription(),
+			Size:        c.GetSize(),
+			UsageCount:  int(c.GetUsageCount()),
+		}
+
+		if c.GetLastUsedAtUnixNs() != 0 {
+			caches[i].LastUsedAt = time.Unix(0, c.GetLastUsedAtUnixNs())
+		}
+	}
+
+	return caches, nil
 }
 
 func (client *Client) Close() error {
@@ -342,6 +536,33 @@ func (srv *Server) Read(p *proto.Thunk, readSrv proto.Runtime_ReadServer) error
 	return srv.Runtime.Read(ctx, readSrvWriter{readSrv}, thunk)
 }
 
+func (srv *Server) RunResult(p *proto.Thunk, resultSrv proto.Runtime_RunResultServer) error {
+	thunk := bass.Thunk{}
+
+	err := thunk.UnmarshalProto(p)
+	if err != nil {
+		return err
+	}
+
+	recorder := progrock.NewRecorder(runResultSrvRecorder{resultSrv})
+	ctx := progrock.RecorderToContext(srv.Context, recorder)
+
+	result, err := srv.Runtime.RunResult(ctx, thunk)
+	if err != nil {
+		return err
+	}
+
+	return resultSrv.Send(&proto.RunResultResponse{
+		Inner: &proto.RunResultResponse_Result{
+			Result: &proto.RunResult{
+				ExitCode: int32(result.ExitCode),
+				Stdout:   result.Stdout,
+				Stderr:   result.Stderr,
+			},
+		},
+	})
+}
+
 func (srv *Server) Export(p *proto.Thunk, exportSrv proto.Runtime_ExportServer) error {
 	thunk := bass.Thunk{}
 
@@ -353,7 +574,29 @@ func (srv *Server) Export(p *proto.Thunk, exportSrv proto.Runtime_ExportServer)
 	recorder := progrock.NewRecorder(exportSrvRecorder{exportSrv})
 	ctx := progrock.RecorderToContext(srv.Context, recorder)
 
-	return srv.Runtime.Export(ctx, exportSrvWriter{exportSrv}, thunk)
+	return srv.Runtime.Export(ctx, exportSrvWriter{exportSrv}, thunk, bass.ExportOpts{})
+}
+
+func (srv *Server) ExportImage(p *proto.ExportRequest, exportSrv proto.Runtime_ExportImageServer) error {
+	thunk := bass.Thunk{}
+
+	err := thunk.UnmarshalProto(p.GetThunk())
+	if err != nil {
+		return err
+	}
+
+	format, err := bass.ParseExportFormat(p.GetFormat())
+	if err != nil {
+		return err
+	}
+
+	recorder := progrock.NewRecorder(exportSrvRecorder{exportSrv})
+	ctx := progrock.RecorderToContext(srv.Context, recorder)
+
+	return srv.Runtime.Export(ctx, exportSrvWriter{exportSrv}, thunk, bass.ExportOpts{
+		Format: format,
+		Tags:   p.GetTags(),
+	})
 }
 
 func (srv *Server) Publish(p *proto.PublishRequest, pubSrv proto.Runtime_PublishServer) error {
@@ -387,6 +630,39 @@ func (srv *Server) Publish(p *proto.PublishRequest, pubSrv proto.Runtime_Publish
 	})
 }
 
+func (srv *Server) PublishIndex(p *proto.PublishIndexRequest, pubSrv proto.Runtime_PublishIndexServer) error {
+	ref := bass.ImageRef{}
+	if err := ref.UnmarshalProto(p.GetRef()); err != nil {
+		return err
+	}
+
+	thunks := make([]bass.Thunk, len(p.GetThunks()))
+	for i, t := range p.GetThunks() {
+		if err := thunks[i].UnmarshalProto(t); err != nil {
+			return err
+		}
+	}
+
+	recorder := progrock.NewRecorder(publishSrvRecorder{pubSrv})
+	ctx := progrock.RecorderToContext(srv.Context, recorder)
+
+	ref, err := srv.Runtime.PublishIndex(ctx, ref, thunks)
+	if err != nil {
+		return err
+	}
+
+	pRef, err := ref.MarshalProto()
+	if err != nil {
+		return err
+	}
+
+	return pubSrv.Send(&proto.PublishResponse{
+		Inner: &proto.PublishResponse_Published{
+			Published: pRef.(*proto.ImageRef),
+		},
+	})
+}
+
 func (srv *Server) ExportPath(p *proto.ThunkPath, exportSrv proto.Runtime_ExportPathServer) error {
 	tp := bass.ThunkPath{}
 
@@ -415,6 +691,44 @@ func (w runSrvRecorder) WriteStatus(status *progrock.StatusUpdate) error {
 
 func (w runSrvRecorder) Close() error { return nil }
 
+func (srv *Server) Prune(p *proto.PruneRequest, pruneSrv proto.Runtime_PruneServer) error {
+	recorder := progrock.NewRecorder(pruneSrvRecorder{pruneSrv})
+	ctx := progrock.RecorderToContext(srv.Context, recorder)
+	ctx = ioctx.StderrToContext(ctx, pruneSrvWriter{pruneSrv})
+
+	return srv.Runtime.Prune(ctx, bass.PruneOpts{
+		All:          p.GetAll(),
+		KeepDuration: time.Duration(p.GetKeepDurationNs()),
+		KeepBytes:    p.GetKeepBytes(),
+		IDs:          p.GetIds(),
+	})
+}
+
+func (srv *Server) Caches(ctx context.Context, p *proto.CachesRequest) (*proto.CachesResponse, error) {
+	caches, err := srv.Runtime.Caches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &proto.CachesResponse{}
+	for _, c := range caches {
+		info := &proto.CacheInfo{
+			Id:          c.ID,
+			Description: c.Description,
+			Size:        c.Size,
+			UsageCount:  int64(c.UsageCount),
+		}
+
+		if !c.LastUsedAt.IsZero() {
+			info.LastUsedAtUnixNs = c.LastUsedAt.UnixNano()
+		}
+
+		res.Caches = append(res.Caches, info)
+	}
+
+	return res, nil
+}
+
 type readSrvRecorder struct {
 	readSrv proto.Runtime_ReadServer
 }
@@ -445,8 +759,26 @@ func (w readSrvWriter) Write(p []byte) (int, error) {
 	return len(p), nil
 }
 
+type runResultSrvRecorder struct {
+	srv proto.Runtime_RunResultServer
+}
+
+func (w runResultSrvRecorder) WriteStatus(status *progrock.StatusUpdate) error {
+	return w.srv.Send(&proto.RunResultResponse{
+		Inner: &proto.RunResultResponse_Progress{
+			Progress: status,
+		},
+	})
+}
+
+func (w runResultSrvRecorder) Close() error { return nil }
+
+type publishResponseSrv interface {
+	Send(*proto.PublishResponse) error
+}
+
 type publishSrvRecorder struct {
-	publishSrv proto.Runtime_PublishServer
+	publishSrv publishResponseSrv
 }
 
 func (w publishSrvRecorder) WriteStatus(status *progrock.StatusUpdate) error {
@@ -467,6 +799,10 @@ type exportResponseSrv interface {
 	Send(*proto.ExportResponse) error
 }
 
+type exportResponseClient interface {
+	Recv() (*proto.ExportResponse, error)
+}
+
 type exportSrvWriter struct {
 	srv exportResponseSrv
 }
@@ -497,3 +833,33 @@ func (w exportSrvRecorder) WriteStatus(status *progrock.StatusUpdate) error {
 }
 
 func (w exportSrvRecorder) Close() error { return nil }
+
+type pruneSrvRecorder struct {
+	srv proto.Runtime_PruneServer
+}
+
+func (w pruneSrvRecorder) WriteStatus(status *progrock.StatusUpdate) error {
+	return w.srv.Send(&proto.PruneResponse{
+		Inner: &proto.PruneResponse_Progress{
+			Progress: status,
+		},
+	})
+}
+
+func (w pruneSrvRecorder) Close() error { return nil }
+
+type pruneSrvWriter struct {
+	srv proto.Runtime_PruneServer
+}
+
+func (w pruneSrvWriter) Write(p []byte) (int, error) {
+	err := w.srv.Send(&proto.PruneResponse{
+		Inner: &proto.PruneResponse_Output{
+			Output: p,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
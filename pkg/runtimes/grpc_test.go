@@ -1,14 +1,18 @@
 package runtimes_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/ioctx"
 	"github.com/vito/bass/pkg/proto"
 	"github.com/vito/bass/pkg/runtimes"
 	"github.com/vito/bass/pkg/runtimes/util/buildkitd"
@@ -81,3 +85,98 @@ func TestGRPCRuntime(t *testing.T) {
 		"secrets.bass",
 	))
 }
+
+// cacheRuntime implements only the cache-related methods of bass.Runtime.
+type cacheRuntime struct {
+	bass.Runtime
+
+	caches []bass.CacheInfo
+	pruned []bass.PruneOpts
+}
+
+func (runtime *cacheRuntime) Caches(context.Context) ([]bass.CacheInfo, error) {
+	return runtime.caches, nil
+}
+
+func (runtime *cacheRuntime) Prune(ctx context.Context, opts bass.PruneOpts) error {
+	runtime.pruned = append(runtime.pruned, opts)
+	fmt.Fprintf(ioctx.StderrFromContext(ctx), "pruned %d\n", len(opts.IDs))
+	return nil
+}
+
+func TestGRPCCaches(t *testing.T) {
+	is := is.New(t)
+
+	ctx := context.Background()
+
+	sockPath := filepath.Join(t.TempDir(), "sock")
+	listener, err := net.Listen("unix", sockPath)
+	is.NoErr(err)
+
+	defer listener.Close()
+
+	lastUsed := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	fake := &cacheRuntime{
+		caches: []bass.CacheInfo{
+			{
+				ID:          "abc",
+				Description: "cached mount /go/pkg/mod from exec",
+				Size:        1024,
+				LastUsedAt:  lastUsed,
+				UsageCount:  3,
+			},
+			{
+				ID:   "def",
+				Size: 42,
+			},
+		},
+	}
+
+	srv := grpc.NewServer()
+	proto.RegisterRuntimeServer(srv, &runtimes.Server{
+		Context: ctx,
+		Runtime: fake,
+	})
+
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, net.ErrClosed) {
+			panic(err)
+		}
+	}()
+
+	defer srv.Stop()
+
+	client, err := runtimes.NewClient(ctx, nil, bass.Bindings{
+		"target": bass.String("unix://" + sockPath),
+	}.Scope())
+	is.NoErr(err)
+
+	defer client.Close()
+
+	caches, err := client.Caches(ctx)
+	is.NoErr(err)
+	is.Equal(len(caches), 2)
+	is.Equal(caches[0].ID, "abc")
+	is.Equal(caches[0].Description, "cached mount /go/pkg/mod from exec")
+	is.Equal(caches[0].Size, int64(1024))
+	is.True(caches[0].LastUsedAt.Equal(lastUsed))
+	is.Equal(caches[0].UsageCount, 3)
+	is.Equal(caches[1].ID, "def")
+	is.True(caches[1].LastUsedAt.IsZero())
+
+	stderr := new(bytes.Buffer)
+	err = client.Prune(ioctx.StderrToContext(ctx, stderr), bass.PruneOpts{
+		KeepDuration: time.Hour,
+		KeepBytes:    1024,
+		IDs:          []string{"abc", "def"},
+	})
+	is.NoErr(err)
+	is.Equal(fake.pruned, []bass.PruneOpts{
+		{
+			KeepDuration: time.Hour,
+			KeepBytes:    1024,
+			IDs:          []string{"abc", "def"},
+		},
+	})
+	is.Equal(stderr.String(), "pruned 2\n")
+}
@@ -0,0 +1,228 @@
+package runtimes
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd/content"
+	imagearchive "github.com/containerd/containerd/images/archive"
+	"github.com/containerd/containerd/platforms"
+	"github.com/moby/buildkit/frontend/dockerfile/dockerignore"
+	"github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/tonistiigi/fsutil"
+	"github.com/vito/bass/pkg/bass"
+)
+
+// outputStream streams a command's stdout to a writer while it runs.
+//
+// Writes never fail so that the command isn't interrupted; the first error
+// is returned by Finish instead.
+type outputStream struct {
+	w       io.Writer
+	written int64
+	err     error
+}
+
+func (stream *outputStream) Write(p []byte) (int, error) {
+	if stream.err == nil {
+		n, err := stream.w.Write(p)
+		stream.written += int64(n)
+		stream.err = err
+	}
+
+	return len(p), nil
+}
+
+// Finish copies the remaining output that was not already streamed, e.g. all
+// of it for a cached result.
+func (stream *outputStream) Finish(output io.Reader) error {
+	if stream.err != nil {
+		return stream.err
+	}
+
+	if _, err := io.CopyN(io.Discard, output, stream.written); err != nil {
+		return fmt.Errorf("skip streamed output: %w", err)
+	}
+
+	_, err := io.Copy(stream.w, output)
+	return err
+}
+
+// runTracker tracks running commands so that they can be interrupted and
+// waited on by Close, letting them clean up before the process exits.
+type runTracker struct {
+	running  map[*context.CancelFunc]struct{}
+	runningL sync.Mutex
+	runningW sync.WaitGroup
+}
+
+// Track returns a context which is canceled by Close, and a func to call once
+// the work is done.
+func (tracker *runTracker) Track(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	tracker.runningL.Lock()
+	if tracker.running == nil {
+		tracker.running = map[*context.CancelFunc]struct{}{}
+	}
+	tracker.running[&cancel] = struct{}{}
+	tracker.runningL.Unlock()
+
+	tracker.runningW.Add(1)
+
+	return ctx, func() {
+		cancel()
+
+		tracker.runningL.Lock()
+		delete(tracker.running, &cancel)
+		tracker.runningL.Unlock()
+
+		tracker.runningW.Done()
+	}
+}
+
+// Close interrupts all running commands and waits for them to finish.
+func (tracker *runTracker) Close() {
+	tracker.runningL.Lock()
+	for cancel := range tracker.running {
+		(*cancel)()
+	}
+	tracker.runningL.Unlock()
+
+	tracker.runningW.Wait()
+}
+
+// resultKey derives a cache key from the given parts.
+func resultKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// hostPathKey derives a cache key from the content of a host path.
+func hostPathKey(ctx context.Context, source bass.HostPath) (string, error) {
+	hash := digest.Canonical.Digester()
+
+	src, walkOpt, err := hostPathSource(ctx, source)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return "", err
+	}
+
+	if info.IsDir() {
+		err = fsutil.WriteTar(ctx, fsutil.NewFS(src, walkOpt), hash.Hash())
+	} else {
+		err = hashFile(src, info, hash.Hash())
+	}
+	if err != nil {
+		return "", fmt.Errorf("hash %s: %w", source, err)
+	}
+
+	return resultKey("host", hash.Digest().String()), nil
+}
+
+// hostPathSource returns the host path to copy from and the filters to apply,
+// including any .bassignore in the context dir.
+func hostPathSource(ctx context.Context, source bass.HostPath) (string, *fsutil.WalkOpt, error) {
+	walkOpt := &fsutil.WalkOpt{
+		IncludePatterns: source.Includes(),
+		ExcludePatterns: source.Excludes(),
+	}
+
+	ignorePath := bass.HostPath{
+		ContextDir: source.ContextDir,
+		Path:       bass.ParseFileOrDirPath(".bassignore"),
+	}
+
+	ignore, err := ignorePath.Open(ctx)
+	if err == nil {
+		defer ignore.Close()
+
+		ignores, err := dockerignore.ReadAll(ignore)
+		if err != nil {
+			return "", nil, fmt.Errorf("parse %s: %w", ignorePath, err)
+		}
+
+		walkOpt.ExcludePatterns = append(walkOpt.ExcludePatterns, ignores...)
+	}
+
+	return filepath.Join(source.ContextDir, source.Path.FilesystemPath().FromSlash()), walkOpt, nil
+}
+
+func hashFile(src string, info fs.FileInfo, w io.Writer) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(w, "%s\x00", info.Mode()); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// writeJSON writes a JSON blob to the content store.
+func writeJSON(ctx context.Context, store content.Store, mediaType string, val any) (ocispecs.Descriptor, error) {
+	payload, err := json.Marshal(val)
+	if err != nil {
+		return ocispecs.Descriptor{}, err
+	}
+
+	desc := ocispecs.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(payload),
+		Size:      int64(len(payload)),
+	}
+
+	err = content.WriteBlob(ctx, store, desc.Digest.String(), bytes.NewReader(payload), desc)
+	if err != nil {
+		return ocispecs.Descriptor{}, err
+	}
+
+	return desc, nil
+}
+
+// exportImage writes an image archive for the manifest in the content store.
+func exportImage(ctx context.Context, store content.Store, w io.Writer, desc ocispecs.Descriptor, opts bass.ExportOpts) error {
+	exportOpts := []imagearchive.ExportOpt{
+		imagearchive.WithPlatform(platforms.Only(*desc.Platform)),
+	}
+
+	if opts.Format == bass.ExportDocker {
+		exportOpts = append(exportOpts, imagearchive.WithManifest(desc, opts.Tags...))
+	} else {
+		exportOpts = append(exportOpts,
+			imagearchive.WithManifest(desc),
+			imagearchive.WithSkipDockerManifest())
+	}
+
+	return imagearchive.Export(ctx, store, w, exportOpts...)
+}
+
+func hasEnv(env []string, name string) bool {
+	for _, e := range env {
+		if strings.HasPrefix(e, name+"=") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,1490 @@
+//go:build linux
+
+package runtimes
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/platforms"
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/gofrs/flock"
+	"github.com/moby/buildkit/exporter/containerimage/image"
+	"github.com/moby/buildkit/identity"
+	"github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/tonistiigi/fsutil"
+	fscopy "github.com/tonistiigi/fsutil/copy"
+	"github.com/tonistiigi/units"
+	"github.com/vito/progrock"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/cli"
+	"github.com/vito/bass/pkg/ioctx"
+)
+
+const NativeName = "native"
+
+func init() {
+	RegisterRuntime(NativeName, NewNative)
+}
+
+// NativeConfig configures the native runtime.
+type NativeConfig struct {
+	// Directory to store images, thunk results, and caches in. Defaults to
+	// $XDG_DATA_HOME/bass/native.
+	DataDir string `json:"data_dir,omitempty"`
+
+	// OCI runtime to run containers with, e.g. runc or crun. Defaults to the
+	// first of either found in $PATH, falling back to running containers in
+	// user namespaces directly. Set to "none" to skip the lookup.
+	OCIRuntime string `json:"oci_runtime,omitempty"`
+
+	Debug        bool `json:"debug,omitempty"`
+	DisableCache bool `json:"disable_cache,omitempty"`
+}
+
+// Native is a runtime which runs thunks on the local Linux machine without a
+// daemon.
+//
+// Images are unpacked from a local content store and thunks run with the
+// shim in containers created with runc or crun, or in user namespaces
+// directly. Results are cached on disk by thunk hash and inputs.
+type Native struct {
+	Config   NativeConfig
+	Platform ocispecs.Platform
+
+	dataDir string
+	root    bool
+	runner  nativeRunner
+
+	auth  *registryAuthProvider
+	store content.Store
+
+	pulled  map[string]nativeState
+	pulledL sync.Mutex
+
+	runs runTracker
+}
+
+var _ bass.Runtime = &Native{}
+
+// nativeState is the result of building a thunk or image.
+type nativeState struct {
+	// A key derived from the thunk and all of its inputs, used to cache
+	// results of thunks which build on this one.
+	Key string
+
+	// The root filesystem. Empty for scratch.
+	Rootfs string
+
+	// The working directory, i.e. the thunk's output. Empty if nothing has
+	// run yet.
+	Work string
+
+	// The directory containing the command's stdout, stderr, and exit code.
+	IO string
+
+	Platform    ocispecs.Platform
+	Config      ocispecs.ImageConfig
+	Healthcheck *image.HealthConfig
+	Annotations map[string]string
+}
+
+// withImageConfig sets the image config from a config blob.
+func (st nativeState) withImageConfig(config []byte) (nativeState, error) {
+	// NB: parse as a Docker image to preserve its healthcheck
+	var img image.Image
+	if err := json.Unmarshal(config, &img); err != nil {
+		return st, err
+	}
+
+	st.Config = img.Config.ImageConfig
+	st.Healthcheck = img.Config.Healthcheck
+
+	if img.Architecture != "" && img.OS != "" {
+		st.Platform = ocispecs.Platform{
+			OS:           img.OS,
+			Architecture: img.Architecture,
+			Variant:      img.Variant,
+		}
+	}
+
+	return st, nil
+}
+
+// withThunkImageConfig applies the config that only affects the published
+// image.
+func (st nativeState) withThunkImageConfig(cfg bass.ThunkImageConfig) (nativeState, error) {
+	ib, err := IntermediateBuild{
+		Config:      st.Config,
+		Healthcheck: st.Healthcheck,
+		Annotations: st.Annotations,
+	}.withThunkImageConfig(cfg)
+	if err != nil {
+		return st, err
+	}
+
+	st.Config = ib.Config
+	st.Healthcheck = ib.Healthcheck
+	st.Annotations = ib.Annotations
+
+	return st, nil
+}
+
+// nativeExec configures how the native runtime runs a thunk's command.
+type nativeExec struct {
+	// Stream the command's stdout to the writer, or copy it from the cached
+	// result.
+	Stdout io.Writer
+
+	// Record the exit code and stderr instead of failing.
+	Result bool
+
+	// Run a service; never use or save a cached result.
+	Service bool
+}
+
+func NewNative(ctx context.Context, _ bass.RuntimePool, cfg *bass.Scope) (bass.Runtime, error) {
+	var config NativeConfig
+	if cfg != nil {
+		if err := cfg.Decode(&config); err != nil {
+			return nil, fmt.Errorf("native runtime config: %w", err)
+		}
+	}
+
+	if config.DataDir == "" {
+		config.DataDir = filepath.Join(xdg.DataHome, "bass", "native")
+	}
+
+	for _, dir := range []string{"images", "thunks", "caches", "run", "state"} {
+		if err := os.MkdirAll(filepath.Join(config.DataDir, dir), 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	runner, err := newNativeRunner(config.OCIRuntime, filepath.Join(config.DataDir, "state"))
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := local.NewStore(filepath.Join(config.DataDir, "content"))
+	if err != nil {
+		return nil, fmt.Errorf("create content store: %w", err)
+	}
+
+	return &Native{
+		Config:   config,
+		Platform: platforms.Normalize(platforms.DefaultSpec()),
+
+		dataDir: config.DataDir,
+		root:    os.Geteuid() == 0,
+		runner:  runner,
+
+		auth:  newRegistryAuthProvider(os.Stderr),
+		store: store,
+
+		pulled: map[string]nativeState{},
+	}, nil
+}
+
+func (runtime *Native) Resolve(ctx context.Context, imageRef bass.ImageRef) (bass.Thunk, error) {
+	// track dependent services
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	ctx, rec := progrock.WithGroup(ctx, "resolve "+imageRef.Thunk().String())
+	defer rec.Complete()
+
+	addr, err := ref(ctx, runtime, imageRef)
+	if err != nil {
+		return bass.Thunk{}, fmt.Errorf("resolve ref %v: %w", imageRef, err)
+	}
+
+	_, desc, err := runtime.resolveRef(ctx, addr)
+	if err != nil {
+		return bass.Thunk{}, fmt.Errorf("resolve image: %w", err)
+	}
+
+	imageRef.Digest = desc.Digest.String()
+
+	return imageRef.Thunk(), nil
+}
+
+func (runtime *Native) Run(ctx context.Context, thunk bass.Thunk) error {
+	ctx, rec := progrock.WithGroup(ctx, "run "+thunk.String())
+	defer rec.Complete()
+
+	ctx, done := thunk.TimeoutContext(ctx)
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	_, err := runtime.build(ctx, thunk, true, nativeExec{})
+	return done(err)
+}
+
+func (runtime *Native) Start(ctx context.Context, thunk bass.Thunk) (StartResult, error) {
+	ctx, rec := progrock.WithGroup(ctx, "start "+thunk.String())
+	defer rec.Complete()
+
+	ctx, stop := context.WithCancel(ctx)
+
+	runs := bass.RunsFromContext(ctx)
+
+	exited := make(chan error, 1)
+	runs.Go(stop, func() error {
+		_, err := runtime.build(ctx, thunk, true, nativeExec{Service: true})
+		exited <- err
+		return err
+	})
+
+	checked := make(chan error, 1)
+	go func() {
+		checked <- pollPorts(ctx, thunk.Ports)
+	}()
+
+	select {
+	case err := <-checked:
+		if err != nil {
+			return StartResult{}, fmt.Errorf("check error: %w", err)
+		}
+
+		result := StartResult{
+			Ports: PortInfos{},
+		}
+
+		// services share the host network
+		for _, port := range thunk.Ports {
+			result.Ports[port.Name] = bass.Bindings{
+				"host": bass.String("127.0.0.1"),
+				"port": bass.Int(port.Port),
+			}.Scope()
+		}
+
+		return result, nil
+	case err := <-exited:
+		stop() // interrupt healthcheck
+
+		if err != nil {
+			return StartResult{}, err
+		}
+
+		return StartResult{}, fmt.Errorf("service exited before healthcheck")
+	}
+}
+
+// pollPorts waits for each port to accept connections on the host.
+func pollPorts(ctx context.Context, ports []bass.ThunkPort) error {
+	dialer := net.Dialer{
+		Timeout: time.Second,
+	}
+
+	for _, port := range ports {
+		addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port.Port))
+		for {
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			if err == nil {
+				_ = conn.Close()
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("poll %s: %w", port.Name, ctx.Err())
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+	}
+
+	return nil
+}
+
+func (runtime *Native) Read(ctx context.Context, w io.Writer, thunk bass.Thunk) error {
+	ctx, rec := progrock.WithGroup(ctx, "read "+thunk.String())
+	defer rec.Complete()
+
+	ctx, done := thunk.TimeoutContext(ctx)
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	_, err := runtime.build(ctx, thunk, true, nativeExec{Stdout: w})
+	return done(err)
+}
+
+func (runtime *Native) RunResult(ctx context.Context, thunk bass.Thunk) (bass.RunResult, error) {
+	ctx, rec := progrock.WithGroup(ctx, "run "+thunk.String())
+	defer rec.Complete()
+
+	ctx, done := thunk.TimeoutContext(ctx)
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	st, err := runtime.build(ctx, thunk, true, nativeExec{Result: true})
+	if err != nil {
+		return bass.RunResult{}, done(err)
+	}
+
+	var result bass.RunResult
+
+	result.Stdout, err = os.ReadFile(filepath.Join(st.IO, path.Base(outputFile)))
+	if err != nil {
+		return result, done(err)
+	}
+
+	result.Stderr, err = os.ReadFile(filepath.Join(st.IO, path.Base(stderrFile)))
+	if err != nil {
+		return result, done(err)
+	}
+
+	exitCode, err := os.ReadFile(filepath.Join(st.IO, path.Base(exitCodeFile)))
+	if err != nil {
+		return result, done(err)
+	}
+
+	result.ExitCode, err = strconv.Atoi(string(exitCode))
+	if err != nil {
+		return result, done(fmt.Errorf("malformed exit code: %w", err))
+	}
+
+	if result.ExitCode != 0 {
+		// don't cache failures; the next run should try again
+		err := runtime.remove(ctx, filepath.Dir(st.IO))
+		if err != nil {
+			return result, done(err)
+		}
+	}
+
+	return result, done(nil)
+}
+
+func (runtime *Native) Export(ctx context.Context, w io.Writer, thunk bass.Thunk, opts bass.ExportOpts) error {
+	ctx, rec := progrock.WithGroup(ctx, "export "+thunk.String())
+	defer rec.Complete()
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	switch opts.Format {
+	case bass.ExportOCI, bass.ExportDocker, bass.ExportRootfs, "":
+	default:
+		return UnsupportedError{
+			Runtime: NativeName,
+			Feature: fmt.Sprintf("export format %s", opts.Format),
+		}
+	}
+
+	st, err := runtime.build(ctx, thunk, false, nativeExec{})
+	if err != nil {
+		return err
+	}
+
+	if opts.Format == bass.ExportRootfs {
+		return runtime.writeTar(ctx, st.Rootfs, nil, w)
+	}
+
+	desc, err := runtime.writeImage(ctx, st)
+	if err != nil {
+		return err
+	}
+
+	return exportImage(ctx, runtime.store, w, desc, opts)
+}
+
+func (runtime *Native) Publish(ctx context.Context, ref bass.ImageRef, thunk bass.Thunk) (bass.ImageRef, error) {
+	ctx, rec := progrock.WithGroup(ctx, "publish "+thunk.String())
+	defer rec.Complete()
+
+	defer runtime.auth.Use(ctx)()
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	addr, err := ref.Ref()
+	if err != nil {
+		return ref, err
+	}
+
+	st, err := runtime.build(ctx, thunk, false, nativeExec{})
+	if err != nil {
+		return ref, err
+	}
+
+	desc, err := runtime.writeImage(ctx, st)
+	if err != nil {
+		return ref, err
+	}
+
+	if err := runtime.push(ctx, addr, desc); err != nil {
+		return ref, err
+	}
+
+	ref.Digest = desc.Digest.String()
+
+	return ref, nil
+}
+
+func (runtime *Native) PublishIndex(ctx context.Context, ref bass.ImageRef, thunks []bass.Thunk) (bass.ImageRef, error) {
+	addr, err := ref.Ref()
+	if err != nil {
+		return ref, err
+	}
+
+	ctx, rec := progrock.WithGroup(ctx, "publish index "+addr)
+	defer rec.Complete()
+
+	defer runtime.auth.Use(ctx)()
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	manifests := make([]ocispecs.Descriptor, len(thunks))
+	for i, thunk := range thunks {
+		st, err := runtime.build(ctx, thunk, false, nativeExec{})
+		if err != nil {
+			return ref, fmt.Errorf("%s: %w", thunk.Platform(), err)
+		}
+
+		manifests[i], err = runtime.writeImage(ctx, st)
+		if err != nil {
+			return ref, fmt.Errorf("%s: %w", thunk.Platform(), err)
+		}
+	}
+
+	desc, err := runtime.writeIndex(ctx, manifests)
+	if err != nil {
+		return ref, err
+	}
+
+	if err := runtime.push(ctx, addr, desc); err != nil {
+		return ref, err
+	}
+
+	ref.Digest = desc.Digest.String()
+
+	return ref, nil
+}
+
+func (runtime *Native) ExportPath(ctx context.Context, w io.Writer, tp bass.ThunkPath) error {
+	ctx, rec := progrock.WithGroup(ctx, "export path "+tp.String())
+	defer rec.Complete()
+
+	ctx, svcs := bass.TrackRuns(ctx)
+	defer svcs.StopAndWait()
+
+	st, err := runtime.build(ctx, tp.Thunk, true, nativeExec{})
+	if err != nil {
+		return err
+	}
+
+	fsp := tp.Path.FilesystemPath()
+
+	// resolve symlinks as the thunk would see them, never on the host
+	source, err := securejoin.SecureJoin(st.Work, fsp.FromSlash())
+	if err != nil {
+		return err
+	}
+
+	if fsp.IsDir() {
+		return runtime.writeTar(ctx, source, &fsutil.WalkOpt{
+			IncludePatterns: tp.Includes(),
+			ExcludePatterns: tp.Excludes(),
+		}, w)
+	}
+
+	f, err := openInRoot(source)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(w)
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+
+	hdr.Name = fsp.FromSlash()
+	hdr.Uid, hdr.Gid = 0, 0
+	hdr.Uname, hdr.Gname = "", ""
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write tar header: %w", err)
+	}
+
+	if _, err := io.Copy(tw, f); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// nativeCacheMeta records the usage of a cache directory.
+type nativeCacheMeta struct {
+	ID         string    `json:"id"`
+	UsageCount int       `json:"usage_count"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+func (runtime *Native) Prune(ctx context.Context, opts bass.PruneOpts) error {
+	stderr := ioctx.StderrFromContext(ctx)
+	tw := tabwriter.NewWriter(stderr, 2, 8, 2, ' ', 0)
+
+	type record struct {
+		id       string
+		desc     string
+		path     string
+		size     int64
+		lastUsed time.Time
+	}
+
+	var records []record
+
+	if len(opts.IDs) > 0 {
+		caches, err := runtime.Caches(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, cache := range caches {
+			for _, id := range opts.IDs {
+				if cache.ID == id {
+					records = append(records, record{
+						id:       cache.ID,
+						desc:     cache.Description,
+						path:     filepath.Join(runtime.dataDir, "caches", cache.ID),
+						size:     cache.Size,
+						lastUsed: cache.LastUsedAt,
+					})
+				}
+			}
+		}
+	} else {
+		for _, kind := range []string{"thunks", "images"} {
+			entries, err := os.ReadDir(filepath.Join(runtime.dataDir, kind))
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					continue
+				}
+
+				info, err := entry.Info()
+				if err != nil {
+					return err
+				}
+
+				dir := filepath.Join(runtime.dataDir, kind, entry.Name())
+
+				size, err := dirSize(dir)
+				if err != nil {
+					return err
+				}
+
+				records = append(records, record{
+					id:       entry.Name(),
+					desc:     kind,
+					path:     dir,
+					size:     size,
+					lastUsed: info.ModTime(),
+				})
+			}
+		}
+
+		if opts.All {
+			caches, err := runtime.Caches(ctx)
+			if err != nil {
+				return err
+			}
+
+			for _, cache := range caches {
+				records = append(records, record{
+					id:       cache.ID,
+					desc:     cache.Description,
+					path:     filepath.Join(runtime.dataDir, "caches", cache.ID),
+					size:     cache.Size,
+					lastUsed: cache.LastUsedAt,
+				})
+			}
+		}
+	}
+
+	// prune the least recently used first
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].lastUsed.Before(records[j].lastUsed)
+	})
+
+	var remaining int64
+	for _, r := range records {
+		remaining += r.size
+	}
+
+	total := int64(0)
+	for _, r := range records {
+		if opts.KeepDuration > 0 && time.Since(r.lastUsed) < opts.KeepDuration {
+			continue
+		}
+
+		if opts.KeepBytes > 0 && remaining <= opts.KeepBytes {
+			break
+		}
+
+		if err := runtime.remove(ctx, r.path); err != nil {
+			return fmt.Errorf("prune %s: %w", r.id, err)
+		}
+
+		fmt.Fprintf(tw, "pruned %s\tlast used: %s ago\tsize: %.2f\t%s\n",
+			r.id,
+			time.Since(r.lastUsed).Truncate(time.Second),
+			units.Bytes(r.size),
+			r.desc)
+
+		remaining -= r.size
+		total += r.size
+	}
+
+	if opts.All && len(opts.IDs) == 0 {
+		if err := runtime.remove(ctx, filepath.Join(runtime.dataDir, "content")); err != nil {
+			return fmt.Errorf("prune content: %w", err)
+		}
+
+		store, err := local.NewStore(filepath.Join(runtime.dataDir, "content"))
+		if err != nil {
+			return fmt.Errorf("create content store: %w", err)
+		}
+
+		runtime.store = store
+	}
+
+	fmt.Fprintf(tw, "total: %.2f\n", units.Bytes(total))
+
+	return tw.Flush()
+}
+
+func (runtime *Native) Caches(ctx context.Context) ([]bass.CacheInfo, error) {
+	entries, err := os.ReadDir(filepath.Join(runtime.dataDir, "caches"))
+	if err != nil {
+		return nil, err
+	}
+
+	var caches []bass.CacheInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(runtime.dataDir, "caches", entry.Name())
+
+		var meta nativeCacheMeta
+		payload, err := os.ReadFile(dir + ".json")
+		if err == nil {
+			err = json.Unmarshal(payload, &meta)
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("cache %s: %w", entry.Name(), err)
+		}
+
+		size, err := dirSize(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		caches = append(caches, bass.CacheInfo{
+			ID:          entry.Name(),
+			Description: meta.ID,
+			Size:        size,
+			LastUsedAt:  meta.LastUsedAt,
+			UsageCount:  meta.UsageCount,
+		})
+	}
+
+	return caches, nil
+}
+
+func (runtime *Native) Close() error {
+	runtime.runs.Close()
+	return nil
+}
+
+// remove removes a directory and any lock or metadata files alongside it,
+// waiting for the lock.
+func (runtime *Native) remove(ctx context.Context, dir string) error {
+	lock := flock.New(dir + ".lock")
+	if _, err := lock.TryLockContext(ctx, 100*time.Millisecond); err != nil {
+		return err
+	}
+
+	defer lock.Unlock()
+
+	if err := removeTree(dir); err != nil {
+		return err
+	}
+
+	if err := os.Remove(dir + ".json"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			size += info.Size()
+		}
+
+		return nil
+	})
+	return size, err
+}
+
+// materialize populates a directory by calling the given function with a
+// temporary directory which is renamed to the target once populated.
+//
+// Concurrent calls for the same directory, including from other processes,
+// wait for the first one to finish. If the directory already exists it is
+// left alone unless force is true.
+func (runtime *Native) materialize(ctx context.Context, dir string, force bool, populate func(string) error) error {
+	lock := flock.New(dir + ".lock")
+	if _, err := lock.TryLockContext(ctx, 100*time.Millisecond); err != nil {
+		return err
+	}
+
+	defer lock.Unlock()
+
+	if _, err := os.Stat(dir); err == nil {
+		if !force {
+			// bump the time for pruning
+			now := time.Now()
+			return os.Chtimes(dir, now, now)
+		}
+
+		if err := removeTree(dir); err != nil {
+			return err
+		}
+	}
+
+	tmp := dir + ".tmp"
+	if err := removeTree(tmp); err != nil {
+		return err
+	}
+
+	if err := os.Mkdir(tmp, 0700); err != nil {
+		return err
+	}
+
+	if err := populate(tmp); err != nil {
+		_ = removeTree(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, dir)
+}
+
+func (runtime *Native) image(ctx context.Context, image *bass.ThunkImage) (nativeState, error) {
+	switch {
+	case image == nil:
+		return nativeState{
+			Platform: runtime.Platform,
+		}, nil
+
+	case image.Ref != nil:
+		addr, err := ref(ctx, runtime, *image.Ref)
+		if err != nil {
+			return nativeState{}, err
+		}
+
+		return runtime.pull(ctx, addr)
+
+	case image.Thunk != nil:
+		return runtime.build(ctx, *image.Thunk, false, nativeExec{})
+
+	case image.Archive != nil:
+		return runtime.importArchive(ctx, image.Archive)
+
+	case image.DockerBuild != nil:
+		return nativeState{}, UnsupportedError{
+			Runtime: NativeName,
+			Feature: "docker builds",
+		}
+
+	default:
+		return nativeState{}, fmt.Errorf("unsupported image type: %s", image.ToValue())
+	}
+}
+
+// nativeMount is a mount source prepared for running a command.
+type nativeMount struct {
+	CommandMount
+
+	// The container path to mount to.
+	Target string
+
+	// Part of the cache key for the command.
+	Key string
+
+	// The built thunk, for thunk path mounts.
+	thunk nativeState
+}
+
+func (runtime *Native) build(ctx context.Context, thunk bass.Thunk, forceExec bool, opts nativeExec) (nativeState, error) {
+	if thunk.TLS != nil {
+		return nativeState{}, UnsupportedError{
+			Runtime: NativeName,
+			Feature: "TLS",
+		}
+	}
+
+	base, err := runtime.image(ctx, thunk.Image)
+	if err != nil {
+		return nativeState{}, err
+	}
+
+	thunkName, err := thunk.Hash()
+	if err != nil {
+		return nativeState{}, err
+	}
+
+	cmd, err := NewCommand(ctx, runtime, thunk)
+	if err != nil {
+		return nativeState{}, err
+	}
+
+	st := base
+
+	st.Config, err = thunkImageConfig(st.Config, thunk)
+	if err != nil {
+		return nativeState{}, err
+	}
+
+	if thunk.ImageConfig != nil {
+		st, err = st.withThunkImageConfig(*thunk.ImageConfig)
+		if err != nil {
+			return nativeState{}, err
+		}
+	}
+
+	useEntrypoint := thunk.UseEntrypoint
+	if len(cmd.Args) == 0 {
+		if forceExec {
+			cmd.Args = st.Config.Cmd
+			useEntrypoint = true
+		} else {
+			// no command; just overriding config
+			st.Key = resultKey("config", base.Key, thunkName)
+			return st, nil
+		}
+	}
+
+	if useEntrypoint {
+		cmd.Args = append(append([]string{}, st.Config.Entrypoint...), cmd.Args...)
+	}
+
+	if len(cmd.Args) == 0 {
+		return nativeState{}, fmt.Errorf("no command specified")
+	}
+
+	if cmd.User != "" && !runtime.root && cmd.User != "root" && cmd.User != "0" && cmd.User != "0:0" {
+		return nativeState{}, UnsupportedError{
+			Runtime: NativeName,
+			Feature: "running as a non-root user (with-user) without root privileges",
+		}
+	}
+
+	mounts := make([]nativeMount, len(cmd.Mounts))
+	mountKeys := []string{}
+	for i, mount := range cmd.Mounts {
+		mounts[i], err = runtime.prepareMount(ctx, mount)
+		if err != nil {
+			return nativeState{}, err
+		}
+
+		mountKeys = append(mountKeys, mounts[i].Target+"="+mounts[i].Key)
+	}
+
+	mode := "run"
+	if opts.Result {
+		mode = "result"
+	}
+
+	st.Key = resultKey(append([]string{mode, base.Key, thunkName}, mountKeys...)...)
+
+	dir := filepath.Join(runtime.dataDir, "thunks", st.Key)
+	if opts.Service {
+		dir = filepath.Join(runtime.dataDir, "run", "service-"+identity.NewID())
+	}
+
+	var stream *outputStream
+	if opts.Stdout != nil {
+		stream = &outputStream{w: opts.Stdout}
+	}
+
+	runCtx, done := runtime.runs.Track(ctx)
+
+	err = runtime.materialize(runCtx, dir, runtime.Config.DisableCache, func(tmp string) error {
+		return runtime.exec(runCtx, tmp, thunk, thunkName, cmd, base, st, mounts, opts, stream)
+	})
+
+	if opts.Service {
+		// services are never cached
+		_ = runtime.remove(context.Background(), dir)
+		_ = os.Remove(dir + ".lock")
+	}
+
+	done()
+
+	if err != nil {
+		return nativeState{}, err
+	}
+
+	st.Rootfs = filepath.Join(dir, "rootfs")
+	st.Work = filepath.Join(dir, "work")
+	st.IO = filepath.Join(dir, "io")
+
+	if stream != nil {
+		out, err := os.Open(filepath.Join(st.IO, path.Base(outputFile)))
+		if err != nil {
+			return nativeState{}, err
+		}
+
+		defer out.Close()
+
+		if err := stream.Finish(out); err != nil {
+			return nativeState{}, err
+		}
+	}
+
+	return st, nil
+}
+
+func (runtime *Native) prepareMount(ctx context.Context, mount CommandMount) (nativeMount, error) {
+	nm := nativeMount{
+		CommandMount: mount,
+	}
+
+	if filepath.IsAbs(mount.Target) {
+		nm.Target = mount.Target
+	} else {
+		nm.Target = filepath.Join(workDir, mount.Target)
+	}
+
+	source := mount.Source
+	switch {
+	case source.ThunkPath != nil:
+		dep, err := runtime.build(ctx, source.ThunkPath.Thunk, true, nativeExec{})
+		if err != nil {
+			return nm, fmt.Errorf("thunk path %s: %w", source.ThunkPath, err)
+		}
+
+		nm.thunk = dep
+		nm.Key = resultKey("thunk", dep.Key, source.ThunkPath.Path.Slash())
+
+	case source.HostPath != nil:
+		key, err := hostPathKey(ctx, *source.HostPath)
+		if err != nil {
+			return nm, err
+		}
+
+		nm.Key = key
+
+	case source.FSPath != nil:
+		hash, err := source.FSPath.Hash()
+		if err != nil {
+			return nm, err
+		}
+
+		nm.Key = resultKey("fs", hash, source.FSPath.Path.Slash())
+
+	case source.Cache != nil:
+		nm.Key = resultKey("cache", source.Cache.ID, source.Cache.Path.Slash())
+
+	case source.Secret != nil:
+		nm.Key = resultKey("secret", source.Secret.Name)
+
+	default:
+		return nm, fmt.Errorf("unrecognized mount source: %s", source.ToValue())
+	}
+
+	return nm, nil
+}
+
+// exec runs the command, populating the result directory with the resulting
+// root filesystem, working directory, and command output.
+func (runtime *Native) exec(
+	ctx context.Context,
+	resultDir string,
+	thunk bass.Thunk,
+	thunkName string,
+	cmd Command,
+	base, st nativeState,
+	mounts []nativeMount,
+	opts nativeExec,
+	stream *outputStream,
+) error {
+	bundle, err := os.MkdirTemp(filepath.Join(runtime.dataDir, "run"), "bass-")
+	if err != nil {
+		return err
+	}
+
+	defer removeTree(bundle)
+
+	rootfs := filepath.Join(resultDir, "rootfs")
+	work := filepath.Join(resultDir, "work")
+	ioPath := filepath.Join(resultDir, "io")
+
+	if base.Rootfs != "" {
+		err = cli.Step(ctx, "[hide] copy rootfs for "+thunk.String(), func(ctx context.Context, _ *progrock.VertexRecorder) error {
+			return copyTree(base.Rootfs, rootfs)
+		})
+	} else {
+		err = os.Mkdir(rootfs, 0755)
+	}
+	if err != nil {
+		return fmt.Errorf("rootfs: %w", err)
+	}
+
+	if err := os.Mkdir(ioPath, 0700); err != nil {
+		return err
+	}
+
+	// limits are enforced on the container rather than by the shim
+	resources := cmd.Resources
+	cmd.Resources = nil
+
+	cmdPayload, err := bass.MarshalJSON(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(ioPath, path.Base(inputFile)), cmdPayload, 0600); err != nil {
+		return err
+	}
+
+	shimExe, found := allShims["exe."+runtime.Platform.Architecture]
+	if !found {
+		return fmt.Errorf("no shim found for %s", runtime.Platform.Architecture)
+	}
+
+	shimPath := filepath.Join(bundle, "shim")
+	if err := os.WriteFile(shimPath, shimExe, 0755); err != nil {
+		return err
+	}
+
+	hostname := "thunk"
+	if len(thunk.Ports) > 0 {
+		hostname = thunkName
+	}
+
+	hostsPath := filepath.Join(bundle, "hosts")
+	hosts := fmt.Sprintf("127.0.0.1\tlocalhost %[1]s\n::1\tlocalhost %[1]s\n", hostname)
+	if err := os.WriteFile(hostsPath, []byte(hosts), 0644); err != nil {
+		return err
+	}
+
+	specMounts := []specs.Mount{
+		bindMount(ioPath, ioDir, false),
+		bindMount(shimPath, shimExePath, true),
+		bindMount(hostsPath, "/etc/hosts", true),
+	}
+
+	if _, err := os.Stat("/etc/resolv.conf"); err == nil {
+		specMounts = append(specMounts, bindMount("/etc/resolv.conf", "/etc/resolv.conf", true))
+	}
+
+	var remountedWorkdir bool
+	var userMounts []specs.Mount
+	for i, mount := range mounts {
+		source, release, err := runtime.mountSource(ctx, bundle, i, mount)
+		if err != nil {
+			return fmt.Errorf("mount %s: %w", mount.Target, err)
+		}
+
+		defer release()
+
+		if mount.Target == workDir {
+			// the mount becomes the output
+			if err := copyTree(source, work); err != nil {
+				return fmt.Errorf("remount workdir: %w", err)
+			}
+
+			remountedWorkdir = true
+			continue
+		}
+
+		userMounts = append(userMounts, bindMount(source, mount.Target, false))
+	}
+
+	if !remountedWorkdir {
+		if base.Work != "" {
+			err = copyTree(base.Work, work)
+		} else {
+			err = os.Mkdir(work, 0755)
+		}
+		if err != nil {
+			return fmt.Errorf("workdir: %w", err)
+		}
+	}
+
+	// mount the workdir first so that mounts into it are layered on top
+	specMounts = append(specMounts, bindMount(work, workDir, false))
+	specMounts = append(specMounts, userMounts...)
+
+	env := append([]string{}, st.Config.Env...)
+	if !hasEnv(env, "PATH") {
+		env = append(env, "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin")
+	}
+
+	env = append(env, "_BASS_OUTPUT="+outputFile)
+
+	if opts.Result {
+		env = append(env,
+			"_BASS_STDERR="+stderrFile,
+			"_BASS_EXIT_CODE="+exitCodeFile)
+	}
+
+	if runtime.Config.Debug {
+		env = append(env, "_BASS_DEBUG=1")
+	}
+
+	for _, secret := range cmd.SecretEnv {
+		env = append(env, secret.Name+"="+string(secret.Secret.Reveal()))
+	}
+
+	ctr := nativeContainer{
+		Rootfs:    rootfs,
+		Hostname:  hostname,
+		Args:      []string{shimExePath, "run", inputFile},
+		Env:       env,
+		Cwd:       workDir,
+		Mounts:    specMounts,
+		NoNetwork: thunk.Network == bass.NetworkModeNone,
+		Insecure:  thunk.Insecure,
+		Rootless:  !runtime.root,
+		Resources: resources,
+	}
+
+	spec := ctr.Spec()
+
+	// create mountpoints ahead of time so they can be removed from the
+	// results, mapping paths in the workdir to the workdir on the host
+	var created []string
+	for _, m := range spec.Mounts {
+		root, rel := rootfs, m.Destination
+		if wrel, err := filepath.Rel(workDir, m.Destination); err == nil && wrel != "." && !strings.HasPrefix(wrel, "..") {
+			root, rel = work, wrel
+		}
+
+		// the image and workdir may contain symlinks leading anywhere on the
+		// host, so they're resolved as if root were /
+		dest, err := securejoin.SecureJoin(root, rel)
+		if err != nil {
+			return fmt.Errorf("resolve mountpoint %s: %w", m.Destination, err)
+		}
+
+		paths, err := createMountpoint(root, m, dest)
+		if err != nil {
+			return fmt.Errorf("create mountpoint %s: %w", m.Destination, err)
+		}
+
+		created = append(created, paths...)
+	}
+
+	err = cli.Step(ctx, thunk.Cmdline(), func(ctx context.Context, vtx *progrock.VertexRecorder) error {
+		var stdout io.Writer = vtx.Stdout()
+		if stream != nil {
+			stdout = io.MultiWriter(stdout, stream)
+		}
+
+		return runtime.runner.Run(ctx, bundle, spec, stdout, vtx.Stderr())
+	})
+	if err != nil {
+		return err
+	}
+
+	// remove mountpoints deepest-first; anything left non-empty is kept
+	for i := len(created) - 1; i >= 0; i-- {
+		_ = os.Remove(created[i])
+	}
+
+	return nil
+}
+
+// mountSource prepares the host path to bind mount for a command, returning
+// a func to call once the command has finished.
+func (runtime *Native) mountSource(ctx context.Context, bundle string, i int, mount nativeMount) (string, func(), error) {
+	noop := func() {}
+
+	// copies are made so that commands can't modify their inputs
+	dest := filepath.Join(bundle, "mounts", strconv.Itoa(i))
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return "", noop, err
+	}
+
+	source := mount.Source
+	switch {
+	case source.ThunkPath != nil:
+		fsp := source.ThunkPath.Path.FilesystemPath()
+		src, err := securejoin.SecureJoin(mount.thunk.Work, fsp.FromSlash())
+		if err != nil {
+			return "", noop, err
+		}
+
+		return dest, noop, copyFiltered(ctx, src, dest, &fsutil.WalkOpt{
+			IncludePatterns: source.ThunkPath.Includes(),
+			ExcludePatterns: source.ThunkPath.Excludes(),
+		})
+
+	case source.HostPath != nil:
+		src, walkOpt, err := hostPathSource(ctx, *source.HostPath)
+		if err != nil {
+			return "", noop, err
+		}
+
+		return dest, noop, copyFiltered(ctx, src, dest, walkOpt)
+
+	case source.FSPath != nil:
+		root := path.Clean(source.FSPath.Path.Slash())
+
+		if source.FSPath.Path.File != nil {
+			content, err := fs.ReadFile(source.FSPath.FS, root)
+			if err != nil {
+				return "", noop, err
+			}
+
+			return dest, noop, os.WriteFile(dest, content, 0644)
+		}
+
+		err := fs.WalkDir(source.FSPath.FS, root, func(walkPath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(root, walkPath)
+			if err != nil {
+				return err
+			}
+
+			target := filepath.Join(dest, rel)
+
+			if d.IsDir() {
+				return os.MkdirAll(target, 0755)
+			}
+
+			content, err := fs.ReadFile(source.FSPath.FS, walkPath)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", walkPath, err)
+			}
+
+			return os.WriteFile(target, content, 0644)
+		})
+
+		return dest, noop, err
+
+	case source.Cache != nil:
+		return runtime.cacheSource(ctx, bundle, i, *source.Cache)
+
+	case source.Secret != nil:
+		secret := source.Secret.Reveal()
+		if secret == nil {
+			return "", noop, fmt.Errorf("missing secret: %s", source.Secret.Name)
+		}
+
+		return dest, noop, os.WriteFile(dest, secret, 0400)
+
+	default:
+		return "", noop, fmt.Errorf("unrecognized mount source: %s", source.ToValue())
+	}
+}
+
+// cacheSource returns the directory to mount for a cache path, taking a lock
+// according to the cache's concurrency mode.
+func (runtime *Native) cacheSource(ctx context.Context, bundle string, i int, cache bass.CachePath) (string, func(), error) {
+	noop := func() {}
+
+	name := digest.FromString(cache.ID).Encoded()
+	dir := filepath.Join(runtime.dataDir, "caches", name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", noop, err
+	}
+
+	if err := touchCache(dir, cache.ID); err != nil {
+		return "", noop, err
+	}
+
+	release := noop
+
+	switch cache.ConcurrencyMode {
+	case bass.ConcurrencyModeLocked:
+		lock := flock.New(dir + ".lock")
+		if _, err := lock.TryLockContext(ctx, 100*time.Millisecond); err != nil {
+			return "", noop, err
+		}
+
+		release = func() { _ = lock.Unlock() }
+
+	case bass.ConcurrencyModePrivate:
+		lock := flock.New(dir + ".lock")
+
+		locked, err := lock.TryLock()
+		if err != nil {
+			return "", noop, err
+		}
+
+		if !locked {
+			// already in use; give this command its own empty cache
+			dir = filepath.Join(bundle, "caches", strconv.Itoa(i))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return "", noop, err
+			}
+		} else {
+			release = func() { _ = lock.Unlock() }
+		}
+	}
+
+	// caches are written to by commands, so they may contain symlinks
+	source, err := securejoin.SecureJoin(dir, cache.Path.FilesystemPath().FromSlash())
+	if err != nil {
+		release()
+		return "", noop, err
+	}
+
+	if err := os.MkdirAll(source, 0755); err != nil {
+		release()
+		return "", noop, err
+	}
+
+	return source, release, nil
+}
+
+// touchCache records a use of the cache directory.
+func touchCache(dir string, id string) error {
+	metaPath := dir + ".json"
+
+	meta := nativeCacheMeta{ID: id}
+
+	payload, err := os.ReadFile(metaPath)
+	if err == nil {
+		if err := json.Unmarshal(payload, &meta); err != nil {
+			return fmt.Errorf("cache metadata: %w", err)
+		}
+	}
+
+	meta.UsageCount++
+	meta.LastUsedAt = time.Now()
+
+	payload, err = json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(metaPath, payload, 0600)
+}
+
+// copyFiltered copies a file or the contents of a directory, applying the
+// include and exclude patterns.
+func copyFiltered(ctx context.Context, src, dest string, walkOpt *fsutil.WalkOpt) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		if err := copyFile(src, dest, info.Mode().Perm()); err != nil {
+			return err
+		}
+
+		return copyMetadata(dest, info, false)
+	}
+
+	return fscopy.Copy(ctx, src, ".", dest, ".", fscopy.WithCopyInfo(fscopy.CopyInfo{
+		CopyDirContents: true,
+		IncludePatterns: walkOpt.IncludePatterns,
+		ExcludePatterns: walkOpt.ExcludePatterns,
+		XAttrErrorHandler: func(dst, src, xattrKey string, err error) error {
+			// xattrs may not be copyable without privileges
+			return nil
+		},
+	}))
+}
+
+func bindMount(source, target string, readonly bool) specs.Mount {
+	opts := []string{"rbind"}
+	if readonly {
+		opts = append(opts, "ro")
+	}
+
+	return specs.Mount{
+		Destination: target,
+		Type:        "none",
+		Source:      source,
+		Options:     opts,
+	}
+}
+
+// createMountpoint creates an empty file or directory at dest to mount onto,
+// returning any paths created under root.
+func createMountpoint(root string, m specs.Mount, dest string) ([]string, error) {
+	var missing []string
+	for dir := dest; dir != root && dir != "/"; dir = filepath.Dir(dir) {
+		if _, err := os.Lstat(dir); err == nil {
+			break
+		}
+
+		missing = append([]string{dir}, missing...)
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	if m.Type != "none" && m.Type != "bind" {
+		// proc, tmpfs, etc.
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return nil, err
+		}
+	} else if err := ensureMountpoint(m.Source, dest); err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
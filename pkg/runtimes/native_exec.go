@@ -0,0 +1,810 @@
+//go:build linux
+
+package runtimes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/docker/docker/pkg/reexec"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/vito/bass/pkg/bass"
+	"golang.org/x/sys/unix"
+)
+
+// nativeInitName is the name that the native runtime re-executes the current
+// binary as in order to set up a container in fresh namespaces.
+//
+// Binaries which use the native runtime must call reexec.Init() first thing
+// in main.
+const nativeInitName = "bass-native-init"
+
+func init() {
+	reexec.Register(nativeInitName, nativeInit)
+}
+
+// nativeContainer configures a process run by the native runtime.
+type nativeContainer struct {
+	Rootfs   string
+	Hostname string
+	Args     []string
+	Env      []string
+	Cwd      string
+	Mounts   []specs.Mount
+
+	// Run in a network namespace with only a loopback interface instead of
+	// sharing the host network.
+	NoNetwork bool
+
+	// Grant all capabilities and, on cgroup v2 hosts, a writable cgroup
+	// namespace so that commands can manage cgroups of their own.
+	Insecure bool
+
+	// Map the current user to root in a new user namespace.
+	Rootless bool
+
+	// Limits enforced on the container's cgroup by the OCI runtime.
+	Resources *bass.ThunkResources
+}
+
+// defaultCapabilities is the set of capabilities granted to secure
+// containers, matching Docker's defaults.
+var defaultCapabilities = []string{
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_FSETID",
+	"CAP_FOWNER",
+	"CAP_MKNOD",
+	"CAP_NET_RAW",
+	"CAP_SETGID",
+	"CAP_SETUID",
+	"CAP_SETFCAP",
+	"CAP_SETPCAP",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_SYS_CHROOT",
+	"CAP_KILL",
+	"CAP_AUDIT_WRITE",
+}
+
+// insecureCapabilities are granted in addition to the defaults for insecure
+// containers.
+var insecureCapabilities = []string{
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_RESOURCE",
+	"CAP_SYS_TIME",
+	"CAP_NET_ADMIN",
+	"CAP_LINUX_IMMUTABLE",
+	"CAP_IPC_LOCK",
+	"CAP_IPC_OWNER",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_MAC_ADMIN",
+	"CAP_MAC_OVERRIDE",
+	"CAP_SYSLOG",
+	"CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND",
+	"CAP_AUDIT_CONTROL",
+	"CAP_AUDIT_READ",
+	"CAP_LEASE",
+	"CAP_SYS_BOOT",
+	"CAP_SYS_NICE",
+	"CAP_SYS_PACCT",
+	"CAP_SYS_TTY_CONFIG",
+}
+
+// Spec returns the OCI runtime spec for the container.
+func (ctr nativeContainer) Spec() *specs.Spec {
+	caps := defaultCapabilities
+	if ctr.Insecure {
+		caps = append(append([]string{}, defaultCapabilities...), insecureCapabilities...)
+	}
+
+	spec := &specs.Spec{
+		Version:  specs.Version,
+		Hostname: ctr.Hostname,
+		Root: &specs.Root{
+			Path: ctr.Rootfs,
+		},
+		Process: &specs.Process{
+			Args: ctr.Args,
+			Env:  ctr.Env,
+			Cwd:  ctr.Cwd,
+			Capabilities: &specs.LinuxCapabilities{
+				Bounding:  caps,
+				Effective: caps,
+				Permitted: caps,
+			},
+		},
+		Linux: &specs.Linux{
+			Namespaces: []specs.LinuxNamespace{
+				{Type: specs.PIDNamespace},
+				{Type: specs.IPCNamespace},
+				{Type: specs.UTSNamespace},
+				{Type: specs.MountNamespace},
+			},
+		},
+	}
+
+	spec.Mounts = []specs.Mount{
+		{
+			Destination: "/proc",
+			Type:        "proc",
+			Source:      "proc",
+		},
+		{
+			Destination: "/dev",
+			Type:        "tmpfs",
+			Source:      "tmpfs",
+			Options:     []string{"nosuid", "strictatime", "mode=755", "size=65536k"},
+		},
+		{
+			Destination: "/dev/pts",
+			Type:        "devpts",
+			Source:      "devpts",
+			Options:     []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620"},
+		},
+		{
+			Destination: "/dev/shm",
+			Type:        "tmpfs",
+			Source:      "shm",
+			Options:     []string{"nosuid", "noexec", "nodev", "mode=1777", "size=65536k"},
+		},
+		{
+			Destination: "/tmp",
+			Type:        "tmpfs",
+			Source:      "tmpfs",
+			Options:     []string{"nosuid", "nodev", "mode=1777"},
+		},
+	}
+
+	if ctr.NoNetwork {
+		spec.Linux.Namespaces = append(spec.Linux.Namespaces, specs.LinuxNamespace{
+			Type: specs.NetworkNamespace,
+		})
+
+		// sysfs can only be mounted fresh when the network namespace is owned
+		spec.Mounts = append(spec.Mounts, specs.Mount{
+			Destination: "/sys",
+			Type:        "sysfs",
+			Source:      "sysfs",
+			Options:     []string{"nosuid", "noexec", "nodev", "ro"},
+		})
+	} else {
+		spec.Mounts = append(spec.Mounts, specs.Mount{
+			Destination: "/sys",
+			Type:        "none",
+			Source:      "/sys",
+			Options:     []string{"rbind", "nosuid", "noexec", "nodev", "ro"},
+		})
+	}
+
+	if ctr.Insecure && cgroupV2() {
+		spec.Linux.Namespaces = append(spec.Linux.Namespaces, specs.LinuxNamespace{
+			Type: specs.CgroupNamespace,
+		})
+
+		spec.Mounts = append(spec.Mounts, specs.Mount{
+			Destination: "/sys/fs/cgroup",
+			Type:        "cgroup2",
+			Source:      "cgroup2",
+			Options:     []string{"nosuid", "noexec", "nodev"},
+		})
+	}
+
+	spec.Mounts = append(spec.Mounts, ctr.Mounts...)
+
+	if res := ctr.Resources; res != nil {
+		spec.Linux.Resources = linuxResources(*res)
+	}
+
+	if ctr.Rootless {
+		spec.Linux.Namespaces = append(spec.Linux.Namespaces, specs.LinuxNamespace{
+			Type: specs.UserNamespace,
+		})
+
+		spec.Linux.UIDMappings = []specs.LinuxIDMapping{
+			{ContainerID: 0, HostID: uint32(os.Getuid()), Size: 1},
+		}
+
+		spec.Linux.GIDMappings = []specs.LinuxIDMapping{
+			{ContainerID: 0, HostID: uint32(os.Getgid()), Size: 1},
+		}
+	}
+
+	return spec
+}
+
+// linuxResources converts thunk resource limits to their OCI equivalent.
+func linuxResources(res bass.ThunkResources) *specs.LinuxResources {
+	lr := &specs.LinuxResources{}
+
+	if res.CPU > 0 {
+		quota := int64(res.CPU * cpuPeriod)
+		period := uint64(cpuPeriod)
+		lr.CPU = &specs.LinuxCPU{
+			Quota:  &quota,
+			Period: &period,
+		}
+	}
+
+	if res.Memory > 0 {
+		limit := res.Memory
+		lr.Memory = &specs.LinuxMemory{
+			Limit: &limit,
+		}
+	}
+
+	if res.Pids > 0 {
+		lr.Pids = &specs.LinuxPids{
+			Limit: res.Pids,
+		}
+	}
+
+	return lr
+}
+
+// cpuPeriod is the scheduling period used for CPU limits, in microseconds.
+const cpuPeriod = 100000
+
+// cgroupV2 returns true if the host uses the unified cgroup hierarchy.
+func cgroupV2() bool {
+	_, err := os.Stat("/sys/fs/cgroup/cgroup.controllers")
+	return err == nil
+}
+
+// nativeRunner runs a container from a bundle directory.
+type nativeRunner interface {
+	// Run runs the container to completion, returning an *exec.ExitError if
+	// it exits nonzero.
+	Run(ctx context.Context, bundle string, spec *specs.Spec, stdout, stderr io.Writer) error
+}
+
+// newNativeRunner returns a runner for the configured OCI runtime, falling
+// back to running containers in user namespaces directly.
+func newNativeRunner(ociRuntime string, stateDir string) (nativeRunner, error) {
+	switch ociRuntime {
+	case "none":
+		return usernsRunner{}, nil
+	case "":
+		for _, name := range []string{"runc", "crun"} {
+			path, err := exec.LookPath(name)
+			if err == nil {
+				return ociRunner{Path: path, Root: stateDir}, nil
+			}
+		}
+
+		return usernsRunner{}, nil
+	default:
+		path, err := exec.LookPath(ociRuntime)
+		if err != nil {
+			return nil, fmt.Errorf("oci runtime: %w", err)
+		}
+
+		return ociRunner{Path: path, Root: stateDir}, nil
+	}
+}
+
+// ociRunner runs containers with an OCI runtime CLI, e.g. runc or crun.
+type ociRunner struct {
+	Path string
+	Root string
+}
+
+func (runner ociRunner) Run(ctx context.Context, bundle string, spec *specs.Spec, stdout, stderr io.Writer) error {
+	if err := writeSpec(bundle, spec); err != nil {
+		return err
+	}
+
+	id := filepath.Base(bundle)
+
+	cmd := exec.CommandContext(ctx, runner.Path, "--root", runner.Root, "run", "--bundle", bundle, id)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Cancel = func() error {
+		// signals sent to the runtime are not forwarded once it's killed, so
+		// kill the container instead
+		return exec.Command(runner.Path, "--root", runner.Root, "kill", id, "KILL").Run()
+	}
+
+	// don't wait forever on output copied to a writer that's gone away
+	cmd.WaitDelay = time.Second
+
+	defer exec.Command(runner.Path, "--root", runner.Root, "delete", "--force", id).Run()
+
+	return cmd.Run()
+}
+
+// usernsRunner runs containers by re-executing the current binary in new
+// namespaces to set up the container's mounts and root filesystem.
+type usernsRunner struct{}
+
+func (usernsRunner) Run(ctx context.Context, bundle string, spec *specs.Spec, stdout, stderr io.Writer) error {
+	if spec.Linux.Resources != nil {
+		// there's nobody to manage a cgroup for the container
+		return UnsupportedError{
+			Runtime: NativeName,
+			Feature: "resource limits without an OCI runtime (runc or crun)",
+		}
+	}
+
+	if err := writeSpec(bundle, spec); err != nil {
+		return err
+	}
+
+	attr := &syscall.SysProcAttr{
+		Pdeathsig: syscall.SIGKILL,
+	}
+
+	for _, ns := range spec.Linux.Namespaces {
+		switch ns.Type {
+		case specs.PIDNamespace:
+			attr.Cloneflags |= syscall.CLONE_NEWPID
+		case specs.IPCNamespace:
+			attr.Cloneflags |= syscall.CLONE_NEWIPC
+		case specs.UTSNamespace:
+			attr.Cloneflags |= syscall.CLONE_NEWUTS
+		case specs.MountNamespace:
+			attr.Cloneflags |= syscall.CLONE_NEWNS
+		case specs.NetworkNamespace:
+			attr.Cloneflags |= syscall.CLONE_NEWNET
+		case specs.UserNamespace:
+			attr.Cloneflags |= syscall.CLONE_NEWUSER
+		case specs.CgroupNamespace:
+			attr.Cloneflags |= syscall.CLONE_NEWCGROUP
+		}
+	}
+
+	for _, m := range spec.Linux.UIDMappings {
+		attr.UidMappings = append(attr.UidMappings, syscall.SysProcIDMap{
+			ContainerID: int(m.ContainerID),
+			HostID:      int(m.HostID),
+			Size:        int(m.Size),
+		})
+	}
+
+	for _, m := range spec.Linux.GIDMappings {
+		attr.GidMappings = append(attr.GidMappings, syscall.SysProcIDMap{
+			ContainerID: int(m.ContainerID),
+			HostID:      int(m.HostID),
+			Size:        int(m.Size),
+		})
+	}
+
+	cmd := exec.CommandContext(ctx, reexec.Self())
+	cmd.Args = []string{nativeInitName, bundle}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.SysProcAttr = attr
+	cmd.WaitDelay = time.Second
+
+	// the init process execs into the shim as PID 1 of its namespace, so
+	// killing it kills everything
+	return cmd.Run()
+}
+
+func writeSpec(bundle string, spec *specs.Spec) error {
+	payload, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("marshal spec: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(bundle, "config.json"), payload, 0600)
+}
+
+// nativeInit is run in the namespaces created by usernsRunner. It mounts
+// everything in the spec, pivots into the root filesystem, and execs the
+// process.
+func nativeInit() {
+	if len(os.Args) != 2 {
+		fmt.Fprintf(os.Stderr, "usage: %s <bundle>\n", nativeInitName)
+		os.Exit(1)
+	}
+
+	if err := initContainer(os.Args[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", nativeInitName, err)
+		os.Exit(1)
+	}
+}
+
+func initContainer(bundle string) error {
+	payload, err := os.ReadFile(filepath.Join(bundle, "config.json"))
+	if err != nil {
+		return fmt.Errorf("read spec: %w", err)
+	}
+
+	var spec specs.Spec
+	if err := json.Unmarshal(payload, &spec); err != nil {
+		return fmt.Errorf("unmarshal spec: %w", err)
+	}
+
+	rootfs := spec.Root.Path
+
+	// don't propagate any of our mounts back to the host
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("make / private: %w", err)
+	}
+
+	// pivot_root requires the new root to be a mount point
+	if err := unix.Mount(rootfs, rootfs, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return fmt.Errorf("bind rootfs: %w", err)
+	}
+
+	for _, m := range spec.Mounts {
+		if err := mountInRoot(rootfs, m); err != nil {
+			return fmt.Errorf("mount %s: %w", m.Destination, err)
+		}
+
+		if m.Destination == "/dev" {
+			if err := populateDev(rootfs); err != nil {
+				return fmt.Errorf("populate /dev: %w", err)
+			}
+		}
+	}
+
+	for _, ns := range spec.Linux.Namespaces {
+		if ns.Type == specs.NetworkNamespace {
+			if err := loopbackUp(); err != nil {
+				return fmt.Errorf("bring up loopback: %w", err)
+			}
+		}
+	}
+
+	if spec.Hostname != "" {
+		if err := unix.Sethostname([]byte(spec.Hostname)); err != nil {
+			return fmt.Errorf("set hostname: %w", err)
+		}
+	}
+
+	if err := os.Chdir(rootfs); err != nil {
+		return err
+	}
+
+	if err := unix.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("pivot root: %w", err)
+	}
+
+	// the old root is stacked under the new one; detach it
+	if err := unix.Unmount(".", unix.MNT_DETACH); err != nil {
+		return fmt.Errorf("unmount old root: %w", err)
+	}
+
+	if err := os.Chdir(spec.Process.Cwd); err != nil {
+		return fmt.Errorf("chdir: %w", err)
+	}
+
+	return unix.Exec(spec.Process.Args[0], spec.Process.Args, spec.Process.Env)
+}
+
+// devices are bind mounted from the host, since they cannot be created in a
+// user namespace.
+var devices = []string{"null", "zero", "full", "random", "urandom", "tty"}
+
+func populateDev(rootfs string) error {
+	for _, dev := range devices {
+		target := filepath.Join(rootfs, "dev", dev)
+
+		f, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+
+		_ = f.Close()
+
+		if err := unix.Mount("/dev/"+dev, target, "", unix.MS_BIND, ""); err != nil {
+			return fmt.Errorf("bind %s: %w", dev, err)
+		}
+	}
+
+	for name, target := range map[string]string{
+		"fd":     "/proc/self/fd",
+		"stdin":  "/proc/self/fd/0",
+		"stdout": "/proc/self/fd/1",
+		"stderr": "/proc/self/fd/2",
+		"ptmx":   "pts/ptmx",
+	} {
+		if err := os.Symlink(target, filepath.Join(rootfs, "dev", name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func loopbackUp() error {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+
+	defer unix.Close(fd)
+
+	ifr, err := unix.NewIfreq("lo")
+	if err != nil {
+		return err
+	}
+
+	ifr.SetUint16(unix.IFF_UP | unix.IFF_LOOPBACK | unix.IFF_RUNNING)
+
+	return unix.IoctlIfreq(fd, unix.SIOCSIFFLAGS, ifr)
+}
+
+var mountFlags = map[string]uintptr{
+	"ro":          unix.MS_RDONLY,
+	"nosuid":      unix.MS_NOSUID,
+	"nodev":       unix.MS_NODEV,
+	"noexec":      unix.MS_NOEXEC,
+	"strictatime": unix.MS_STRICTATIME,
+	"relatime":    unix.MS_RELATIME,
+	"noatime":     unix.MS_NOATIME,
+	"bind":        unix.MS_BIND,
+	"rbind":       unix.MS_BIND | unix.MS_REC,
+}
+
+func mountInRoot(rootfs string, m specs.Mount) error {
+	// symlinks in the image must not lead the mount out of the rootfs
+	target, err := securejoin.SecureJoin(rootfs, m.Destination)
+	if err != nil {
+		return err
+	}
+
+	var flags uintptr
+	var data []string
+	for _, opt := range m.Options {
+		flag, found := mountFlags[opt]
+		if found {
+			flags |= flag
+		} else {
+			data = append(data, opt)
+		}
+	}
+
+	bind := flags&unix.MS_BIND != 0
+
+	if bind {
+		if err := ensureMountpoint(m.Source, target); err != nil {
+			return err
+		}
+
+		// read-only and other flags are ignored for the initial bind
+		if err := unix.Mount(m.Source, target, "", flags&(unix.MS_BIND|unix.MS_REC), ""); err != nil {
+			return err
+		}
+
+		if flags&^(unix.MS_BIND|unix.MS_REC) == 0 {
+			return nil
+		}
+
+		// flags locked by the host mount must be kept when remounting
+		var st unix.Statfs_t
+		if err := unix.Statfs(target, &st); err != nil {
+			return err
+		}
+
+		locked := map[int64]uintptr{
+			unix.ST_NOSUID:  unix.MS_NOSUID,
+			unix.ST_NODEV:   unix.MS_NODEV,
+			unix.ST_NOEXEC:  unix.MS_NOEXEC,
+			unix.ST_RDONLY:  unix.MS_RDONLY,
+			unix.ST_NOATIME: unix.MS_NOATIME,
+		}
+		for stFlag, flag := range locked {
+			if st.Flags&stFlag != 0 {
+				flags |= flag
+			}
+		}
+
+		return unix.Mount("", target, "", flags|unix.MS_REMOUNT|unix.MS_BIND, "")
+	}
+
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+
+	return unix.Mount(m.Source, target, m.Type, flags, strings.Join(data, ","))
+}
+
+// ensureMountpoint creates an empty file or directory to bind mount the
+// source onto, matching the source's type.
+func ensureMountpoint(source, target string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Lstat(target); err == nil {
+		return nil
+	}
+
+	if info.IsDir() {
+		return os.MkdirAll(target, 0755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// copyTree copies a directory tree, preserving modes, timestamps, and
+// symlinks, and ownership when running as root.
+//
+// Directories are left writable while they are populated so that read-only
+// directories can be copied without privileges.
+func copyTree(src, dst string) error {
+	type dirMode struct {
+		path string
+		info fs.FileInfo
+	}
+
+	var dirs []dirMode
+
+	root := os.Geteuid() == 0
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			if err := os.Mkdir(target, 0700); err != nil && !errors.Is(err, fs.ErrExist) {
+				return err
+			}
+
+			dirs = append(dirs, dirMode{target, info})
+
+			// timestamps and modes are applied once populated
+			return nil
+
+		case mode&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+
+		case mode.IsRegular():
+			if err := copyFile(path, target, mode.Perm()); err != nil {
+				return err
+			}
+
+		case mode&fs.ModeNamedPipe != 0:
+			if err := unix.Mkfifo(target, uint32(mode.Perm())); err != nil {
+				return err
+			}
+
+		default:
+			if !root {
+				// devices and sockets can't be created without privileges
+				return nil
+			}
+
+			st := info.Sys().(*syscall.Stat_t)
+			if err := unix.Mknod(target, st.Mode, int(st.Rdev)); err != nil {
+				return err
+			}
+		}
+
+		return copyMetadata(target, info, root)
+	})
+	if err != nil {
+		return err
+	}
+
+	// apply in reverse so that parent timestamps aren't bumped by children
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := copyMetadata(dirs[i].path, dirs[i].info, root); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// openInRoot opens a path which has been resolved within a root, refusing
+// to follow a symlink swapped in after it was resolved.
+func openInRoot(path string) (*os.File, error) {
+	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+
+	return os.NewFile(uintptr(fd), path), nil
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm|0200)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+func copyMetadata(target string, info fs.FileInfo, root bool) error {
+	st := info.Sys().(*syscall.Stat_t)
+
+	if root {
+		if err := os.Lchown(target, int(st.Uid), int(st.Gid)); err != nil {
+			return err
+		}
+	}
+
+	if info.Mode()&fs.ModeSymlink == 0 {
+		if err := os.Chmod(target, info.Mode()&(fs.ModePerm|fs.ModeSetuid|fs.ModeSetgid|fs.ModeSticky)); err != nil {
+			return err
+		}
+	}
+
+	ts := []unix.Timespec{
+		unix.NsecToTimespec(syscall.TimespecToNsec(st.Atim)),
+		unix.NsecToTimespec(syscall.TimespecToNsec(st.Mtim)),
+	}
+
+	return unix.UtimesNanoAt(unix.AT_FDCWD, target, ts, unix.AT_SYMLINK_NOFOLLOW)
+}
+
+// removeTree removes a directory tree, first making each directory writable
+// so that read-only directories can be removed without privileges.
+func removeTree(dir string) error {
+	err := os.RemoveAll(dir)
+	if err == nil {
+		return nil
+	}
+
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			_ = os.Chmod(path, 0700)
+		}
+
+		return nil
+	})
+
+	return os.RemoveAll(dir)
+}
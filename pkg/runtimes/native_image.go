@@ -0,0 +1,388 @@
+//go:build linux
+
+package runtimes
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/archive"
+	"github.com/containerd/containerd/archive/compression"
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	transferarchive "github.com/containerd/containerd/pkg/transfer/archive"
+	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/exporter/containerimage/image"
+	"github.com/moby/buildkit/identity"
+	"github.com/moby/buildkit/session/auth"
+	"github.com/opencontainers/go-digest"
+	imagespecs "github.com/opencontainers/image-spec/specs-go"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/tonistiigi/fsutil"
+	fstypes "github.com/tonistiigi/fsutil/types"
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/cli"
+	"github.com/vito/progrock"
+)
+
+// nativeOCICache caches OCI archives imported by the native runtime.
+var nativeOCICache = newProtoCache[nativeState]()
+
+// resolver returns a registry resolver which authenticates using any
+// credentials set by bass.WithRegistryAuth, falling back to the Docker
+// config.
+func (runtime *Native) resolver() remotes.Resolver {
+	return docker.NewResolver(docker.ResolverOptions{
+		Hosts: docker.ConfigureDefaultRegistries(
+			docker.WithAuthorizer(docker.NewDockerAuthorizer(
+				docker.WithAuthCreds(func(host string) (string, string, error) {
+					creds, err := runtime.auth.Credentials(context.Background(), &auth.CredentialsRequest{
+						Host: host,
+					})
+					if err != nil {
+						return "", "", err
+					}
+
+					return creds.Username, creds.Secret, nil
+				}),
+			)),
+		),
+	})
+}
+
+// resolveRef resolves an image reference to its digest.
+func (runtime *Native) resolveRef(ctx context.Context, ref string) (string, ocispecs.Descriptor, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", ocispecs.Descriptor{}, fmt.Errorf("normalize ref: %w", err)
+	}
+
+	name, desc, err := runtime.resolver().Resolve(ctx, reference.TagNameOnly(named).String())
+	if err != nil {
+		return "", ocispecs.Descriptor{}, err
+	}
+
+	return name, desc, nil
+}
+
+// pull fetches an image for the runtime's platform into the content store
+// and unpacks it.
+func (runtime *Native) pull(ctx context.Context, ref string) (nativeState, error) {
+	runtime.pulledL.Lock()
+	st, found := runtime.pulled[ref]
+	runtime.pulledL.Unlock()
+	if found {
+		return st, nil
+	}
+
+	err := cli.Step(ctx, "pull "+ref, func(ctx context.Context, vtx *progrock.VertexRecorder) error {
+		name, desc, err := runtime.resolveRef(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("resolve: %w", err)
+		}
+
+		fetcher, err := runtime.resolver().Fetcher(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		err = images.Dispatch(ctx, images.Handlers(
+			remotes.FetchHandler(runtime.store, fetcher),
+			images.FilterPlatforms(images.ChildrenHandler(runtime.store), platforms.Only(runtime.Platform)),
+		), nil, desc)
+		if err != nil {
+			return fmt.Errorf("fetch: %w", err)
+		}
+
+		st, err = runtime.unpack(ctx, desc)
+		return err
+	})
+	if err != nil {
+		return nativeState{}, err
+	}
+
+	runtime.pulledL.Lock()
+	runtime.pulled[ref] = st
+	runtime.pulledL.Unlock()
+
+	return st, nil
+}
+
+// importArchive imports an OCI image archive into the content store and
+// unpacks the image for the runtime's platform.
+func (runtime *Native) importArchive(ctx context.Context, imageArchive *bass.ImageArchive) (nativeState, error) {
+	cached, found := nativeOCICache.Get(ctx, imageArchive)
+	if found {
+		return cached, nil
+	}
+
+	rc, err := imageArchive.File.ToReadable().Open(ctx)
+	if err != nil {
+		return nativeState{}, fmt.Errorf("image archive file: %w", err)
+	}
+
+	defer rc.Close()
+
+	var desc ocispecs.Descriptor
+	err = cli.Step(ctx, fmt.Sprintf("import %s", imageArchive.File.ToValue()), func(ctx context.Context, rec *progrock.VertexRecorder) error {
+		desc, err = transferarchive.NewImageImportStream(rc, "").Import(ctx, runtime.store)
+		return err
+	})
+	if err != nil {
+		return nativeState{}, fmt.Errorf("image archive import: %w", err)
+	}
+
+	manifestDesc, err := resolveIndex(ctx, runtime.store, desc, runtime.Platform, imageArchive.Tag)
+	if err != nil {
+		return nativeState{}, fmt.Errorf("image archive resolve index: %w", err)
+	}
+
+	st, err := runtime.unpack(ctx, *manifestDesc)
+	if err != nil {
+		return nativeState{}, err
+	}
+
+	nativeOCICache.Put(ctx, imageArchive, st)
+
+	return st, nil
+}
+
+// unpack applies the layers of an image in the content store to a root
+// filesystem, keyed by the image's config digest.
+func (runtime *Native) unpack(ctx context.Context, desc ocispecs.Descriptor) (nativeState, error) {
+	manifest, err := images.Manifest(ctx, runtime.store, desc, platforms.Only(runtime.Platform))
+	if err != nil {
+		return nativeState{}, fmt.Errorf("manifest: %w", err)
+	}
+
+	configBlob, err := content.ReadBlob(ctx, runtime.store, manifest.Config)
+	if err != nil {
+		return nativeState{}, fmt.Errorf("read config: %w", err)
+	}
+
+	dir := filepath.Join(runtime.dataDir, "images", manifest.Config.Digest.Encoded())
+
+	err = runtime.materialize(ctx, dir, false, func(tmp string) error {
+		rootfs := filepath.Join(tmp, "rootfs")
+		if err := os.Mkdir(rootfs, 0755); err != nil {
+			return err
+		}
+
+		return runtime.applyLayers(ctx, rootfs, manifest.Layers)
+	})
+	if err != nil {
+		return nativeState{}, fmt.Errorf("unpack: %w", err)
+	}
+
+	st := nativeState{
+		Key:      manifest.Config.Digest.String(),
+		Rootfs:   filepath.Join(dir, "rootfs"),
+		Platform: runtime.Platform,
+	}
+
+	return st.withImageConfig(configBlob)
+}
+
+func (runtime *Native) applyLayers(ctx context.Context, rootfs string, layers []ocispecs.Descriptor) error {
+	var opts []archive.ApplyOpt
+
+	// directory modes to restore once every layer is applied
+	dirModes := map[string]int64{}
+
+	if !runtime.root {
+		opts = append(opts,
+			archive.WithNoSameOwner(),
+			archive.WithFilter(func(hdr *tar.Header) (bool, error) {
+				switch hdr.Typeflag {
+				case tar.TypeChar, tar.TypeBlock:
+					// devices can't be created without privileges
+					return false, nil
+				case tar.TypeDir:
+					// keep directories writable so later entries can be created
+					dirModes[hdr.Name] = hdr.Mode
+					hdr.Mode |= 0700
+				}
+
+				return true, nil
+			}),
+		)
+	}
+
+	for _, layer := range layers {
+		ra, err := runtime.store.ReaderAt(ctx, layer)
+		if err != nil {
+			return fmt.Errorf("open layer %s: %w", layer.Digest, err)
+		}
+
+		ds, err := compression.DecompressStream(content.NewReader(ra))
+		if err != nil {
+			_ = ra.Close()
+			return fmt.Errorf("decompress layer %s: %w", layer.Digest, err)
+		}
+
+		_, err = archive.Apply(ctx, rootfs, ds, opts...)
+		_ = ds.Close()
+		_ = ra.Close()
+		if err != nil {
+			return fmt.Errorf("apply layer %s: %w", layer.Digest, err)
+		}
+	}
+
+	for name, mode := range dirModes {
+		err := os.Chmod(filepath.Join(rootfs, name), os.FileMode(mode).Perm())
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeImage writes the state's root filesystem to the content store as a
+// single-layer image, returning the manifest descriptor.
+func (runtime *Native) writeImage(ctx context.Context, st nativeState) (ocispecs.Descriptor, error) {
+	layerDesc, diffID, err := runtime.writeLayer(ctx, st.Rootfs)
+	if err != nil {
+		return ocispecs.Descriptor{}, fmt.Errorf("write layer: %w", err)
+	}
+
+	configDesc, err := writeJSON(ctx, runtime.store, ocispecs.MediaTypeImageConfig, image.Image{
+		Image: ocispecs.Image{
+			Platform: st.Platform,
+			RootFS: ocispecs.RootFS{
+				Type:    "layers",
+				DiffIDs: []digest.Digest{diffID},
+			},
+		},
+		Config: image.ImageConfig{
+			ImageConfig: st.Config,
+			Healthcheck: st.Healthcheck,
+		},
+	})
+	if err != nil {
+		return ocispecs.Descriptor{}, fmt.Errorf("write config: %w", err)
+	}
+
+	manifestDesc, err := writeJSON(ctx, runtime.store, ocispecs.MediaTypeImageManifest, ocispecs.Manifest{
+		Versioned:   imagespecs.Versioned{SchemaVersion: 2},
+		MediaType:   ocispecs.MediaTypeImageManifest,
+		Config:      configDesc,
+		Layers:      []ocispecs.Descriptor{layerDesc},
+		Annotations: st.Annotations,
+	})
+	if err != nil {
+		return ocispecs.Descriptor{}, fmt.Errorf("write manifest: %w", err)
+	}
+
+	platform := st.Platform
+	manifestDesc.Platform = &platform
+
+	return manifestDesc, nil
+}
+
+// writeIndex writes an image index for the given manifests to the content
+// store.
+func (runtime *Native) writeIndex(ctx context.Context, manifests []ocispecs.Descriptor) (ocispecs.Descriptor, error) {
+	return writeJSON(ctx, runtime.store, ocispecs.MediaTypeImageIndex, ocispecs.Index{
+		Versioned: imagespecs.Versioned{SchemaVersion: 2},
+		MediaType: ocispecs.MediaTypeImageIndex,
+		Manifests: manifests,
+	})
+}
+
+// writeLayer writes a gzipped tarball of the directory to the content store,
+// returning its descriptor and the digest of the uncompressed tarball.
+func (runtime *Native) writeLayer(ctx context.Context, dir string) (ocispecs.Descriptor, digest.Digest, error) {
+	diffID := digest.Canonical.Digester()
+
+	r, w := io.Pipe()
+	go func() {
+		gw := gzip.NewWriter(w)
+
+		err := runtime.writeTar(ctx, dir, nil, io.MultiWriter(gw, diffID.Hash()))
+		if err == nil {
+			err = gw.Close()
+		}
+
+		w.CloseWithError(err)
+	}()
+
+	cw, err := content.OpenWriter(ctx, runtime.store, content.WithRef("layer-"+identity.NewID()))
+	if err != nil {
+		_ = r.CloseWithError(err)
+		return ocispecs.Descriptor{}, "", err
+	}
+
+	defer cw.Close()
+
+	size, err := io.Copy(cw, r)
+	if err != nil {
+		return ocispecs.Descriptor{}, "", err
+	}
+
+	dgst := cw.Digest()
+
+	err = cw.Commit(ctx, size, dgst)
+	if err != nil && !errdefs.IsAlreadyExists(err) {
+		return ocispecs.Descriptor{}, "", err
+	}
+
+	return ocispecs.Descriptor{
+		MediaType: ocispecs.MediaTypeImageLayerGzip,
+		Digest:    dgst,
+		Size:      size,
+	}, diffID.Digest(), nil
+}
+
+// writeTar writes a tarball of the directory, mapping files owned by the
+// current user to root when running rootless.
+func (runtime *Native) writeTar(ctx context.Context, dir string, opts *fsutil.WalkOpt, w io.Writer) error {
+	if opts == nil {
+		opts = &fsutil.WalkOpt{}
+	}
+
+	if !runtime.root {
+		uid, gid := uint32(os.Getuid()), uint32(os.Getgid())
+		opts.Map = func(_ string, st *fstypes.Stat) fsutil.MapResult {
+			if st.Uid == uid {
+				st.Uid = 0
+			}
+
+			if st.Gid == gid {
+				st.Gid = 0
+			}
+
+			return fsutil.MapResultKeep
+		}
+	}
+
+	return fsutil.WriteTar(ctx, fsutil.NewFS(dir, opts), w)
+}
+
+// push pushes an image or index in the content store to a registry.
+func (runtime *Native) push(ctx context.Context, addr string, desc ocispecs.Descriptor) error {
+	named, err := reference.ParseNormalizedNamed(addr)
+	if err != nil {
+		return fmt.Errorf("normalize ref: %w", err)
+	}
+
+	ref := reference.TagNameOnly(named).String()
+
+	return cli.Step(ctx, "push "+ref, func(ctx context.Context, vtx *progrock.VertexRecorder) error {
+		pusher, err := runtime.resolver().Pusher(ctx, ref)
+		if err != nil {
+			return err
+		}
+
+		return remotes.PushContent(ctx, pusher, desc, runtime.store, nil, platforms.All, nil)
+	})
+}
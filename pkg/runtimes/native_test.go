@@ -0,0 +1,58 @@
+//go:build linux
+
+package runtimes_test
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/vito/bass/pkg/bass"
+	"github.com/vito/bass/pkg/runtimes"
+)
+
+func TestNativeRuntime(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+		return
+	}
+
+	if os.Getenv("SKIP_NATIVE_TESTS") != "" {
+		t.Skipf("$SKIP_NATIVE_TESTS set; skipping!")
+		return
+	}
+
+	t.Parallel()
+
+	skip := []string{
+		"tls.bass",
+		"docker-build.bass",
+	}
+
+	if os.Geteuid() != 0 {
+		// only the current user is mapped into the user namespace
+		skip = append(skip, "user.bass", "resources.bass")
+	} else if !hasOCIRuntime() {
+		// limits are enforced by the OCI runtime
+		skip = append(skip, "resources.bass")
+	}
+
+	runtimes.Suite(testCtx, t, bass.RuntimeConfig{
+		Platform: bass.LinuxPlatform,
+		Runtime:  runtimes.NativeName,
+		Config: bass.Bindings{
+			"data_dir": bass.String(t.TempDir()),
+			"debug":    bass.Bool(true),
+		}.Scope(),
+	}, runtimes.SkipSuites(skip...))
+}
+
+func hasOCIRuntime() bool {
+	for _, name := range []string{"runc", "crun"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
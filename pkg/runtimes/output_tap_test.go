@@ -0,0 +1,73 @@
+package runtimes
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/opencontainers/go-digest"
+	"github.com/vito/is"
+)
+
+func TestOutputTap(t *testing.T) {
+	vertex := digest.FromString("exec")
+
+	log := func(data string) *bkclient.VertexLog {
+		return &bkclient.VertexLog{
+			Vertex: vertex,
+			Stream: 1,
+			Data:   []byte(data),
+		}
+	}
+
+	t.Run("streams a prefix and copies the rest", func(t *testing.T) {
+		is := is.New(t)
+
+		buf := new(bytes.Buffer)
+		tap := &outputTap{w: buf}
+		tap.Watch(vertex)
+		tap.Log(log("hello "))
+		tap.Log(&bkclient.VertexLog{Vertex: vertex, Stream: 2, Data: []byte("stderr")})
+		tap.Log(&bkclient.VertexLog{Vertex: digest.FromString("other"), Stream: 1, Data: []byte("other")})
+		is.Equal(buf.String(), "hello ")
+
+		is.NoErr(tap.Finish(strings.NewReader("hello world")))
+		is.Equal(buf.String(), "hello world")
+	})
+
+	t.Run("stops at clipped logs", func(t *testing.T) {
+		is := is.New(t)
+
+		buf := new(bytes.Buffer)
+		tap := &outputTap{w: buf}
+		tap.Watch(vertex)
+		tap.Log(log("hel" + string(clippedLogMarker) + "2MiB reached]\n"))
+		tap.Log(log("lo"))
+		is.Equal(buf.String(), "hel")
+
+		is.NoErr(tap.Finish(strings.NewReader("hello world")))
+		is.Equal(buf.String(), "hello world")
+	})
+
+	t.Run("copies everything when nothing was streamed", func(t *testing.T) {
+		is := is.New(t)
+
+		buf := new(bytes.Buffer)
+		tap := &outputTap{w: buf}
+		is.NoErr(tap.Finish(strings.NewReader("hello world")))
+		is.Equal(buf.String(), "hello world")
+	})
+
+	t.Run("fails if the logs differ from the output", func(t *testing.T) {
+		is := is.New(t)
+
+		buf := new(bytes.Buffer)
+		tap := &outputTap{w: buf}
+		tap.Watch(vertex)
+		tap.Log(log("goodbye"))
+
+		is.True(tap.Finish(strings.NewReader("hello world")) != nil)
+		is.Equal(buf.String(), "goodbye")
+	})
+}
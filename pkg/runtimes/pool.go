@@ -2,6 +2,7 @@ package runtimes
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
@@ -11,6 +12,11 @@ import (
 // Pool is the full set of platform <-> runtime pairs configured by the user.
 type Pool struct {
 	Runtimes []Assoc
+
+	// Runtimes which could not be initialized because their backend could
+	// not be found. Selecting their platform returns a NoRuntimeError
+	// describing them.
+	Unavailable []UnavailableRuntime
 }
 
 // Assoc associates a platform to a runtime.
@@ -25,6 +31,16 @@ func NewPool(ctx context.Context, config *bass.Config) (*Pool, error) {
 
 	for _, config := range config.Runtimes {
 		runtime, err := Init(ctx, config.Runtime, pool, config.Config)
+		var notFound BuildkitNotFoundError
+		if errors.As(err, &notFound) {
+			pool.Unavailable = append(pool.Unavailable, UnavailableRuntime{
+				Platform: config.Platform,
+				Runtime:  config.Runtime,
+				Err:      err,
+			})
+			continue
+		}
+
 		if err != nil {
 			return nil, fmt.Errorf("init %s runtime for platform %s: %w", config.Runtime, config.Platform, err)
 		}
@@ -49,6 +65,7 @@ func (pool *Pool) Select(platform bass.Platform) (bass.Runtime, error) {
 	return nil, NoRuntimeError{
 		Platform:    platform,
 		AllRuntimes: pool.Runtimes,
+		Unavailable: pool.Unavailable,
 	}
 }
 
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var CgroupRoot = "/sys/fs/cgroup"
+
+// cpuPeriod is the scheduling period used for CPU limits, in microseconds.
+const cpuPeriod = 100000
+
+type Resources struct {
+	CPU    float64 `json:"cpu,omitempty"`
+	Memory int64   `json:"memory,omitempty"`
+	Pids   int64   `json:"pids,omitempty"`
+}
+
+// limitedCgroup is a cgroup created for a single command.
+type limitedCgroup struct {
+	*os.File
+
+	dir string
+}
+
+// Remove closes and removes the cgroup once the command has exited, killing
+// any processes it left behind.
+func (cg limitedCgroup) Remove() error {
+	_ = cg.Close()
+
+	// cgroup.kill is only available on Linux 5.14+
+	_ = writeCgroupFile(cg.dir, "cgroup.kill", "1")
+
+	var err error
+	for i := 0; i < 50; i++ {
+		err = os.Remove(cg.dir)
+		if !errors.Is(err, syscall.EBUSY) {
+			break
+		}
+
+		// killed processes take a moment to leave the cgroup
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err != nil {
+		return cgroupErr(err)
+	}
+
+	return nil
+}
+
+// limitResources creates a uniquely named cgroup with the configured limits
+// and returns it opened as a directory, so that the command can be spawned
+// directly into it.
+//
+// This is only used by runtimes which have no way to enforce limits
+// themselves, and only works in a cgroup namespace, since otherwise the
+// cgroup would be created outside of the container's own cgroup. Such
+// runtimes only allow limits for insecure thunks, since cgroups are not
+// writable otherwise.
+//
+// The shim moves itself into a sibling cgroup first, since cgroup v2 does not
+// allow a cgroup containing processes to delegate controllers to its
+// children.
+func limitResources(res Resources) (*limitedCgroup, error) {
+	if !pathExists(filepath.Join(CgroupRoot, "cgroup.controllers")) {
+		return nil, fmt.Errorf("resource limits require cgroup v2 at %s", CgroupRoot)
+	}
+
+	if err := checkCgroupNamespace(); err != nil {
+		return nil, err
+	}
+
+	var controllers []string
+	limits := map[string]string{}
+	if res.CPU > 0 {
+		controllers = append(controllers, "cpu")
+		limits["cpu.max"] = fmt.Sprintf("%d %d", int64(res.CPU*cpuPeriod), cpuPeriod)
+	}
+	if res.Memory > 0 {
+		controllers = append(controllers, "memory")
+		limits["memory.max"] = strconv.FormatInt(res.Memory, 10)
+	}
+	if res.Pids > 0 {
+		controllers = append(controllers, "pids")
+		limits["pids.max"] = strconv.FormatInt(res.Pids, 10)
+	}
+
+	shimCgroup, err := os.MkdirTemp(CgroupRoot, "bass-shim-")
+	if err != nil {
+		return nil, cgroupErr(err)
+	}
+
+	err = writeCgroupFile(shimCgroup, "cgroup.procs", strconv.Itoa(os.Getpid()))
+	if err != nil {
+		return nil, err
+	}
+
+	err = enableControllers(CgroupRoot, controllers)
+	if err != nil {
+		return nil, err
+	}
+
+	cmdCgroup, err := os.MkdirTemp(CgroupRoot, "bass-cmd-")
+	if err != nil {
+		return nil, cgroupErr(err)
+	}
+
+	for name, val := range limits {
+		if err := writeCgroupFile(cmdCgroup, name, val); err != nil {
+			_ = os.Remove(cmdCgroup)
+			return nil, err
+		}
+	}
+
+	dir, err := os.Open(cmdCgroup)
+	if err != nil {
+		_ = os.Remove(cmdCgroup)
+		return nil, cgroupErr(err)
+	}
+
+	return &limitedCgroup{
+		File: dir,
+		dir:  cmdCgroup,
+	}, nil
+}
+
+// checkCgroupNamespace returns an error if the shim is not at the root of a
+// cgroup namespace.
+func checkCgroupNamespace() error {
+	self, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return cgroupErr(err)
+	}
+
+	if strings.TrimSpace(string(self)) != "0::/" {
+		return fmt.Errorf("resource limits: not running in a cgroup namespace (cgroup: %s)", strings.TrimSpace(string(self)))
+	}
+
+	return nil
+}
+
+// enableControllers enables any of the given controllers which are not yet
+// enabled for the cgroup's children, leaving the rest untouched.
+func enableControllers(cgroup string, controllers []string) error {
+	enabled, err := os.ReadFile(filepath.Join(cgroup, "cgroup.subtree_control"))
+	if err != nil {
+		return cgroupErr(err)
+	}
+
+	have := map[string]bool{}
+	for _, c := range strings.Fields(string(enabled)) {
+		have[c] = true
+	}
+
+	var add []string
+	for _, c := range controllers {
+		if !have[c] {
+			add = append(add, "+"+c)
+		}
+	}
+
+	if len(add) == 0 {
+		return nil
+	}
+
+	return writeCgroupFile(cgroup, "cgroup.subtree_control", strings.Join(add, " "))
+}
+
+func writeCgroupFile(cgroup, name, content string) error {
+	err := os.WriteFile(filepath.Join(cgroup, name), []byte(content), 0644)
+	if err != nil {
+		return cgroupErr(err)
+	}
+
+	return nil
+}
+
+func cgroupErr(err error) error {
+	if errors.Is(err, syscall.EROFS) || errors.Is(err, os.ErrPermission) {
+		return fmt.Errorf("resource limits: cgroups are not writable in this container (try running the thunk insecure): %w", err)
+	}
+
+	return fmt.Errorf("resource limits: %w", err)
+}
@@ -23,6 +23,7 @@ func init() {
 var cmds = map[string]func([]string) error{
 	"run":   run,
 	"check": check,
+	"shell": shell,
 }
 
 var cmdArg string
@@ -10,6 +10,7 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -21,10 +22,12 @@ import (
 )
 
 type Command struct {
-	Args  []string `json:"args"`
-	Stdin []byte   `json:"stdin"`
-	Env   []string `json:"env"`
-	Dir   *string  `json:"dir"`
+	Args      []string   `json:"args"`
+	Stdin     []byte     `json:"stdin"`
+	Env       []string   `json:"env"`
+	Dir       *string    `json:"dir"`
+	Resources *Resources `json:"resources,omitempty"`
+	User      string     `json:"user,omitempty"`
 }
 
 func run(args []string) error {
@@ -86,6 +89,33 @@ func run(args []string) error {
 		stdout = io.MultiWriter(stdout, response)
 	}
 
+	stderrPath := os.Getenv("_BASS_STDERR")
+	os.Unsetenv("_BASS_STDERR")
+
+	var stderr io.Writer = os.Stderr
+	if stderrPath != "" {
+		response, err := os.Create(stderrPath)
+		if err != nil {
+			return fmt.Errorf("create stderr error: %w", err)
+		}
+
+		defer response.Close()
+
+		stderr = io.MultiWriter(stderr, response)
+	}
+
+	// when set, the exit status is recorded rather than propagated so that the
+	// command's result can be read even if it fails
+	exitCodePath := os.Getenv("_BASS_EXIT_CODE")
+	os.Unsetenv("_BASS_EXIT_CODE")
+
+	// the run ID is recorded alongside the exit code so that a cached result
+	// can be told apart from a fresh one
+	runIDPath := os.Getenv("_BASS_RUN_ID_FILE")
+	os.Unsetenv("_BASS_RUN_ID_FILE")
+	runID := os.Getenv("_BASS_RUN_ID")
+	os.Unsetenv("_BASS_RUN_ID")
+
 	for _, e := range cmd.Env {
 		segs := strings.SplitN(e, "=", 2)
 		if len(segs) != 2 {
@@ -103,10 +133,36 @@ func run(args []string) error {
 	}
 	execCmd.Stdin = bytes.NewBuffer(cmd.Stdin)
 	execCmd.Stdout = stdout
-	execCmd.Stderr = os.Stderr
+	execCmd.Stderr = stderr
+	execCmd.SysProcAttr = &syscall.SysProcAttr{}
+
+	if cmd.User != "" {
+		cred, err := lookupCredential(cmd.User)
+		if err != nil {
+			return fmt.Errorf("user %s: %w", cmd.User, err)
+		}
+
+		execCmd.SysProcAttr.Credential = cred
+	}
+
+	var cgroup *limitedCgroup
+	if cmd.Resources != nil {
+		var err error
+		cgroup, err = limitResources(*cmd.Resources)
+		if err != nil {
+			return err
+		}
+
+		execCmd.SysProcAttr.UseCgroupFD = true
+		execCmd.SysProcAttr.CgroupFD = int(cgroup.Fd())
+	}
 
 	ch, err := reaper.Default.Start(execCmd)
 	if err != nil {
+		if cgroup != nil {
+			_ = cgroup.Remove()
+		}
+
 		return fmt.Errorf("start: %w", err)
 	}
 
@@ -115,7 +171,25 @@ func run(args []string) error {
 		return fmt.Errorf("wait: %w", err)
 	}
 
-	if status != 0 {
+	if cgroup != nil {
+		if err := cgroup.Remove(); err != nil {
+			return err
+		}
+	}
+
+	if exitCodePath != "" {
+		err := os.WriteFile(exitCodePath, []byte(strconv.Itoa(status)), 0600)
+		if err != nil {
+			return fmt.Errorf("write exit code: %w", err)
+		}
+
+		if runIDPath != "" {
+			err := os.WriteFile(runIDPath, []byte(runID), 0600)
+			if err != nil {
+				return fmt.Errorf("write run id: %w", err)
+			}
+		}
+	} else if status != 0 {
 		// propagate exit status
 		os.Exit(status)
 		return nil
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// shells are tried in order when starting a debug shell.
+var shells = []string{"bash", "sh"}
+
+// shell replaces the shim with an interactive shell configured with the
+// command's env, working directory, and user, for debugging a failed command.
+func shell(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: shell <cmd.json>")
+	}
+
+	cmdPayload, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("read cmd: %w", err)
+	}
+
+	var cmd Command
+	err = json.Unmarshal(cmdPayload, &cmd)
+	if err != nil {
+		return fmt.Errorf("unmarshal cmd: %w", err)
+	}
+
+	os.Unsetenv("_BASS_OUTPUT")
+	os.Unsetenv("_BASS_STDERR")
+	os.Unsetenv("_BASS_EXIT_CODE")
+	os.Unsetenv("_BASS_RUN_ID_FILE")
+	os.Unsetenv("_BASS_RUN_ID")
+
+	for _, e := range cmd.Env {
+		segs := strings.SplitN(e, "=", 2)
+		if len(segs) != 2 {
+			return fmt.Errorf("malformed env: %s", e)
+		}
+
+		os.Setenv(segs[0], segs[1])
+	}
+
+	if cmd.Dir != nil {
+		if err := os.Chdir(*cmd.Dir); err != nil {
+			return fmt.Errorf("chdir: %w", err)
+		}
+	}
+
+	var shellPath string
+	for _, sh := range shells {
+		shellPath, err = exec.LookPath(sh)
+		if err == nil {
+			break
+		}
+	}
+	if shellPath == "" {
+		return fmt.Errorf("no shell found; tried %s", strings.Join(shells, ", "))
+	}
+
+	if cmd.User != "" {
+		cred, err := lookupCredential(cmd.User)
+		if err != nil {
+			return fmt.Errorf("user %s: %w", cmd.User, err)
+		}
+
+		if err := syscall.Setgroups(nil); err != nil {
+			return fmt.Errorf("setgroups: %w", err)
+		}
+
+		if err := syscall.Setgid(int(cred.Gid)); err != nil {
+			return fmt.Errorf("setgid: %w", err)
+		}
+
+		if err := syscall.Setuid(int(cred.Uid)); err != nil {
+			return fmt.Errorf("setuid: %w", err)
+		}
+	}
+
+	return syscall.Exec(shellPath, []string{shellPath}, os.Environ())
+}
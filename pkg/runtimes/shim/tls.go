@@ -45,6 +45,11 @@ func binaryExists(name string) bool {
 }
 
 func installCert() error {
+	if os.Geteuid() != 0 {
+		// NB: only root can modify the system trust, e.g. not (with-user)
+		return nil
+	}
+
 	cert, err := os.ReadFile(BassCAFile)
 	if err != nil {
 		if os.IsNotExist(err) {
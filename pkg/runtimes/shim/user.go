@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// lookupCredential resolves a user spec in the form user[:group] against the
+// container's /etc/passwd and /etc/group. Either side may be a name or a
+// numeric ID.
+//
+// As with Docker, a numeric user that has no passwd entry and no explicit
+// group runs with the root group.
+func lookupCredential(spec string) (*syscall.Credential, error) {
+	userPart, groupPart, hasGroup := strings.Cut(spec, ":")
+
+	cred := &syscall.Credential{
+		// clear supplementary groups inherited from the shim
+		Groups: []uint32{},
+	}
+
+	if uid, err := strconv.ParseUint(userPart, 10, 32); err == nil {
+		cred.Uid = uint32(uid)
+
+		if u, err := user.LookupId(userPart); err == nil {
+			gid, err := strconv.ParseUint(u.Gid, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("user %s: malformed gid: %s", userPart, u.Gid)
+			}
+
+			cred.Gid = uint32(gid)
+		}
+	} else {
+		u, err := user.Lookup(userPart)
+		if err != nil {
+			return nil, fmt.Errorf("lookup user: %w", err)
+		}
+
+		uid, err := strconv.ParseUint(u.Uid, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("user %s: malformed uid: %s", userPart, u.Uid)
+		}
+
+		gid, err := strconv.ParseUint(u.Gid, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("user %s: malformed gid: %s", userPart, u.Gid)
+		}
+
+		cred.Uid = uint32(uid)
+		cred.Gid = uint32(gid)
+	}
+
+	if !hasGroup {
+		return cred, nil
+	}
+
+	if gid, err := strconv.ParseUint(groupPart, 10, 32); err == nil {
+		cred.Gid = uint32(gid)
+		return cred, nil
+	}
+
+	g, err := user.LookupGroup(groupPart)
+	if err != nil {
+		return nil, fmt.Errorf("lookup group: %w", err)
+	}
+
+	gid, err := strconv.ParseUint(g.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("group %s: malformed gid: %s", groupPart, g.Gid)
+	}
+
+	cred.Gid = uint32(gid)
+
+	return cred, nil
+}
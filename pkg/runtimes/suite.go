@@ -10,6 +10,7 @@ import (
 	"io"
 	"math/rand"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -92,6 +93,58 @@ func Suite(ctx context.Context, t *testing.T, runtimeConfig bass.RuntimeConfig,
 			File:     "error.bass",
 			ErrCause: "42",
 		},
+		{
+			File: "timeout.bass",
+			Result: bass.NewList(
+				bass.String("timed out after 1s"),
+				bass.String("timed out after 1s"),
+				bass.Int(42),
+			),
+		},
+		{
+			File:   "retry.bass",
+			Result: bass.NewList(bass.Int(42), bass.Bool(false)),
+		},
+		{
+			File: "run-all.bass",
+			Result: bass.NewList(
+				bass.Int(1),
+				bass.Int(2),
+				bass.Int(3),
+				bass.Int(4),
+				bass.Int(5),
+			),
+		},
+		{
+			File:   "resources.bass",
+			Result: bass.NewList(bass.String("50"), bass.String("67108864")),
+		},
+		{
+			File:   "network.bass",
+			Result: bass.NewList(bass.String("lo")),
+		},
+		{
+			File: "user.bass",
+			Result: bass.NewList(
+				bass.NewList(bass.String("65534"), bass.String("65534")),
+				bass.NewList(bass.String("1000"), bass.String("1000")),
+			),
+		},
+		{
+			File: "run-result.bass",
+			Result: bass.NewList(
+				bass.NewList(bass.Int(3), bass.String("out\n"), bass.String("err\n")),
+				bass.NewList(bass.Int(0), bass.String("ok\n"), bass.String("")),
+			),
+		},
+		{
+			File:   "run-result-rerun.bass",
+			Result: bass.NewList(bass.Int(1), bass.Int(0)),
+		},
+		{
+			File:   "read-stream.bass",
+			Result: bass.NewList(bass.Int(1), bass.Int(2)),
+		},
 		{
 			File:   "response-file.bass",
 			Result: bass.NewList(allJSONValues...),
@@ -251,7 +304,7 @@ func Suite(ctx context.Context, t *testing.T, runtimeConfig bass.RuntimeConfig,
 					}
 
 					buf := new(bytes.Buffer)
-					err = runtime.Export(ctx, buf, thunk)
+					err = runtime.Export(ctx, buf, thunk, bass.ExportOpts{})
 					if err != nil {
 						return err
 					}
@@ -272,6 +325,16 @@ func Suite(ctx context.Context, t *testing.T, runtimeConfig bass.RuntimeConfig,
 			File:   "export.bass",
 			Result: bass.Null{},
 		},
+		{
+			File: "export-formats.bass",
+			Bindings: bass.Bindings{
+				"*tmp*": bass.NewHostDir(t.TempDir()),
+			},
+			Result: bass.NewList(
+				bass.String("1.0.0"),
+				bass.Int(2),
+			),
+		},
 		{
 			File: "write.bass",
 			Bindings: bass.Bindings{
@@ -282,6 +345,15 @@ func Suite(ctx context.Context, t *testing.T, runtimeConfig bass.RuntimeConfig,
 				bass.String("Hello, world!\n"),
 			),
 		},
+		{
+			File:     "symlink-escape.bass",
+			Bindings: symlinkEscapeBindings(t),
+			Result: bass.NewList(
+				bass.Keyword("blocked"),
+				bass.Keyword("blocked"),
+				bass.Empty{},
+			),
+		},
 		// TODO: test publishing somehow :/
 		{
 			File: "docker-build.bass",
@@ -442,6 +514,36 @@ func (test SuiteTest) Run(ctx context.Context, t *testing.T, env *bass.Scope) (v
 	return res, nil
 }
 
+// symlinkEscapeBindings provides host paths for symlinks to point to, which
+// the runtime must never follow.
+func symlinkEscapeBindings(t *testing.T) bass.Bindings {
+	secret := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(secret, []byte("hunter2"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hostDir := t.TempDir()
+
+	return bass.Bindings{
+		"*secret*":   bass.String(secret),
+		"*host-dir*": bass.String(hostDir),
+		"host-dir-entries": bass.Func("host-dir-entries", "[]", func() (bass.Value, error) {
+			entries, err := os.ReadDir(hostDir)
+			if err != nil {
+				return nil, err
+			}
+
+			var names []bass.Value
+			for _, e := range entries {
+				names = append(names, bass.String(e.Name()))
+			}
+
+			return bass.NewList(names...), nil
+		}),
+	}
+}
+
 func detectSecret(r io.Reader, needle string) error {
 	buf := new(bytes.Buffer)
 
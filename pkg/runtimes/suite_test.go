@@ -6,11 +6,17 @@ import (
 	"testing"
 
 	"dagger.io/dagger/telemetry"
+	"github.com/docker/docker/pkg/reexec"
 )
 
 var testCtx = context.Background()
 
 func TestMain(m *testing.M) {
+	// the native runtime re-executes the test binary to set up containers
+	if reexec.Init() {
+		return
+	}
+
 	testCtx = telemetry.InitEmbedded(testCtx, nil)
 	code := m.Run()
 	telemetry.Close()
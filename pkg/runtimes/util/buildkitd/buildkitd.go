@@ -42,6 +42,14 @@ const (
 	bassDNS     = "dns.bass"
 )
 
+// ErrDockerUnavailable is returned by Start when the Docker CLI can't be used
+// to manage the buildkitd container.
+var ErrDockerUnavailable = errors.New("docker is unavailable")
+
+// ErrImageUnavailable is returned by Start when the buildkitd image could not
+// be pulled.
+var ErrImageUnavailable = errors.New("buildkit image is unavailable")
+
 func Start(ctx context.Context, installation, certsDir string) (string, error) {
 	ctx, span := otel.Tracer("bass").Start(ctx, "buildkitd.Start")
 	defer span.End()
@@ -91,7 +99,7 @@ func checkBuildkit(ctx context.Context, installation string, certsDir string) er
 
 		// If that failed, it might be because the docker CLI is out of service.
 		if err := checkDocker(ctx); err != nil {
-			return err
+			return fmt.Errorf("%w: %w", ErrDockerUnavailable, err)
 		}
 
 		logger.Debug("no buildkit daemon detected")
@@ -187,7 +195,7 @@ func installBuildkit(ctx context.Context, installation string, certsDir string)
 			logger.Error("failed to pull buildkit image",
 				zap.Error(err),
 				zap.ByteString("output", output))
-			return err
+			return fmt.Errorf("%w: %w", ErrImageUnavailable, err)
 		}
 	}
 